@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -26,9 +27,11 @@ import (
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"golang.org/x/mod/semver"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/image"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -88,6 +91,12 @@ type ClusterPolicySpec struct {
 	KataManager KataManagerSpec `json:"kataManager,omitempty"`
 	// CCManager component spec
 	CCManager CCManagerSpec `json:"ccManager,omitempty"`
+	// NodePoolOverrides overrides the configuration of components for pools of nodes.
+	// One DaemonSet is deployed per node pool for every overridden component.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	NodePoolOverrides []NodePoolOverride `json:"nodePoolOverrides,omitempty"`
 }
 
 // Runtime defines container runtime type
@@ -105,6 +114,44 @@ const (
 	Containerd Runtime = "containerd"
 )
 
+// DriftPolicy defines how the operator handles operand objects modified outside of the operator
+type DriftPolicy string
+
+const (
+	// DriftPolicyEnforce restores the rendered spec of drifted operand objects
+	DriftPolicyEnforce DriftPolicy = "enforce"
+	// DriftPolicyWarn only reports drifted operand objects and leaves them as they are
+	DriftPolicyWarn DriftPolicy = "warn"
+)
+
+// Distribution defines the Kubernetes distribution the host paths of the operands are configured for
+type Distribution string
+
+const (
+	// DistributionAuto detects the Kubernetes distribution from the GPU nodes
+	DistributionAuto Distribution = "auto"
+	// DistributionKubernetes uses the default host paths of upstream Kubernetes
+	DistributionKubernetes Distribution = "kubernetes"
+	// DistributionK3s uses the host paths of k3s
+	DistributionK3s Distribution = "k3s"
+	// DistributionRKE2 uses the host paths of RKE2
+	DistributionRKE2 Distribution = "rke2"
+	// DistributionK0s uses the host paths of k0s
+	DistributionK0s Distribution = "k0s"
+	// DistributionMicroK8s uses the host paths of MicroK8s
+	DistributionMicroK8s Distribution = "microk8s"
+)
+
+// PodTemplatePatchType defines the type of a patch of the pod template of an operand DaemonSet
+type PodTemplatePatchType string
+
+const (
+	// PodTemplatePatchStrategic is a strategic merge patch of the pod template
+	PodTemplatePatchStrategic PodTemplatePatchType = "strategic"
+	// PodTemplatePatchJSON is a JSON patch (RFC 6902) of the pod template
+	PodTemplatePatchJSON PodTemplatePatchType = "json"
+)
+
 func (r Runtime) String() string {
 	switch r {
 	case Docker:
@@ -118,11 +165,155 @@ func (r Runtime) String() string {
 	}
 }
 
+// ImageVerificationSpec describes the policy the signatures of the operand images are verified with
+type ImageVerificationSpec struct {
+	// SecretName is the name of the Secret, in the operator namespace, holding the policy:
+	// the PEM encoded public keys of the signers in cosign.pub, and for keyless signatures the
+	// YAML list of the issuer and subject (or subjectRegExp) of the signers in identities, the
+	// PEM encoded certificates of the certificate authority in fulcio.crt and the PEM encoded
+	// public key of the transparency log in rekor.pub
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// ProxySpec describes the HTTP proxy settings of the operands
+type ProxySpec struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy is the URL of the proxy for HTTPS requests
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy is a comma-separated list of hostnames, domains and CIDRs for which the proxy is not used
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// TrustedCASpec references the ConfigMap holding a CA bundle trusted by the operands
+type TrustedCASpec struct {
+	// Name is the name of the ConfigMap
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key is the key of the PEM encoded CA bundle in the ConfigMap, ca-bundle.crt by default
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// GetKey returns the key of the CA bundle in the ConfigMap
+func (t *TrustedCASpec) GetKey() string {
+	if t.Key == "" {
+		return "ca-bundle.crt"
+	}
+	return t.Key
+}
+
+// AlertsSpec describes the PrometheusRule holding the alerts of the operator
+type AlertsSpec struct {
+	// Enabled indicates if the operator creates the PrometheusRule of the alerts,
+	// by default the alerts are only enabled on OpenShift
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Enable the alerts of the GPU Operator"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// AdditionalLabels are added to all the alerts, e.g. to route them in Alertmanager
+	// +optional
+	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
+	// Rules overrides the defaults of the alerts, keyed by the alert name, e.g. GPUOperatorOperandNotReady.
+	// The reconcile fails on the name of an alert which is not part of the PrometheusRules of the operator.
+	// +optional
+	Rules map[string]AlertRuleSpec `json:"rules,omitempty"`
+}
+
+// AlertRuleSpec overrides the defaults of an alert
+type AlertRuleSpec struct {
+	// Enabled indicates if the alert is part of the PrometheusRule, true by default
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// For is how long the condition of the alert holds before the alert fires
+	// +optional
+	For promv1.Duration `json:"for,omitempty"`
+	// Threshold replaces the default threshold of the alerts which have one: the seconds since
+	// the last successful reconcile for the reconciliation alerts, the number of pods or nodes
+	// otherwise
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Threshold *int64 `json:"threshold,omitempty"`
+	// Labels are added to the alert, they take precedence over the additional labels of all the alerts
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// IsEnabled returns true if the PrometheusRule of the alerts is created, defaultEnabled
+// applies if not specified by user
+func (a *AlertsSpec) IsEnabled(defaultEnabled bool) bool {
+	if a == nil || a.Enabled == nil {
+		return defaultEnabled
+	}
+	return *a.Enabled
+}
+
+// IsEnabled returns true if the alert is part of the PrometheusRule
+func (r *AlertRuleSpec) IsEnabled() bool {
+	if r.Enabled == nil {
+		// default is true if not specified by user
+		return true
+	}
+	return *r.Enabled
+}
+
 // OperatorSpec describes configuration options for the operator
 type OperatorSpec struct {
 	// +kubebuilder:validation:Enum=docker;crio;containerd
 	// +kubebuilder:default=docker
 	DefaultRuntime Runtime `json:"defaultRuntime"`
+	// DriftPolicy indicates how the operator handles operand objects, including the driver objects of the
+	// NVIDIADriver instances, modified outside of the operator:
+	// enforce restores the rendered spec, warn only reports the drift
+	// +kubebuilder:validation:Enum=enforce;warn
+	// +kubebuilder:default=enforce
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// Distribution selects the profile of host paths the operands are configured with, e.g. the
+	// containerd config and socket and the kubelet pod-resources and device plugin directories.
+	// With auto, the distribution is detected from the GPU nodes and defaults to kubernetes.
+	// +kubebuilder:validation:Enum=auto;kubernetes;k3s;rke2;k0s;microk8s
+	// +kubebuilder:default=auto
+	// +optional
+	Distribution Distribution `json:"distribution,omitempty"`
+	// PinImageDigests indicates if the operator resolves the tags of the operand images to digests
+	// and pins the operand DaemonSets to them. A tag is resolved once, the DaemonSets keep the
+	// resolved digest until the image is changed in the spec. The images are always pinned when
+	// imageVerification is set, so that the verified digests are the ones rolled out.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pin operand images to the digests of their tags"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	PinImageDigests *bool `json:"pinImageDigests,omitempty"`
+	// ImageRegistryMirrors maps image prefixes, e.g. nvcr.io/nvidia, to the prefixes of the mirrors
+	// the operand images are pulled from instead, e.g. registry.example.com/nvidia. A prefix only
+	// matches whole path components of an image and the longest matching prefix is used.
+	// +optional
+	ImageRegistryMirrors map[string]string `json:"imageRegistryMirrors,omitempty"`
+	// ImageVerification enables the verification of the signatures of the operand images before
+	// the operand DaemonSets are created or updated. DaemonSets with images which cannot be
+	// verified are not rolled out.
+	// +optional
+	ImageVerification *ImageVerificationSpec `json:"imageVerification,omitempty"`
+	// Proxy configures the HTTP proxy of the driver, GDS, GDRCopy and container-toolkit containers.
+	// It is ignored on OpenShift, where the cluster wide proxy is used.
+	// +optional
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+	// TrustedCA references a ConfigMap, in the operator namespace, holding the CA bundle mounted
+	// in the driver, GDS, GDRCopy and container-toolkit containers, e.g. to reach package
+	// repositories through a proxy. The bundle is mounted to the trust store of the OS of the
+	// nodes: it replaces the extracted CA bundle on RHEL based systems, so it must hold the public
+	// CAs too, and it is added to the certificates of update-ca-certificates on Ubuntu. It is
+	// ignored on OpenShift, where the CA bundle of the cluster wide proxy is used.
+	// +optional
+	TrustedCA *TrustedCASpec `json:"trustedCA,omitempty"`
+	// Alerts configures the PrometheusRule holding the alerts on the operator and operands metrics.
+	// It is created on clusters with the Prometheus Operator CRDs.
+	// +optional
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
 	// +kubebuilder:default=nvidia
 	RuntimeClass  string            `json:"runtimeClass,omitempty"`
 	InitContainer InitContainerSpec `json:"initContainer,omitempty"`
@@ -167,6 +358,21 @@ type ResourceRequirements struct {
 	Requests corev1.ResourceList `json:"requests,omitempty"`
 }
 
+// PodTemplatePatch describes a patch applied to the pod template of an operand DaemonSet
+// once it is rendered by the operator
+type PodTemplatePatch struct {
+	// Type of the patch, either a strategic merge patch or a JSON patch
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=strategic;json
+	// +kubebuilder:default=strategic
+	Type PodTemplatePatchType `json:"type,omitempty"`
+
+	// Patch in YAML or JSON format. The paths of a JSON patch are relative to the pod template,
+	// e.g. /spec/hostAliases
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
+}
+
 // SandboxWorkloadsSpec describes configuration for handling sandbox workloads (i.e. Virtual Machines)
 type SandboxWorkloadsSpec struct {
 	// Enabled indicates if the GPU Operator should manage additional operands required
@@ -317,6 +523,32 @@ type ValidatorSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
+
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // PluginValidatorSpec defines validator spec for NVIDIA Device Plugin
@@ -535,6 +767,32 @@ type DriverSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Optional: Custom repo configuration for NVIDIA Driver container
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Custom Repo Configuration For NVIDIA Driver Container"
@@ -613,6 +871,32 @@ type VGPUManagerSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// DriverManager represents configuration for NVIDIA Driver Manager initContainer
 	DriverManager DriverManagerSpec `json:"driverManager,omitempty"`
 }
@@ -669,6 +953,32 @@ type ToolkitSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Toolkit install directory on the host
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=/usr/local/nvidia
@@ -730,6 +1040,32 @@ type DevicePluginSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Optional: Configuration for the NVIDIA Device Plugin via the ConfigMap
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Configuration for the NVIDIA Device Plugin via the ConfigMap"
@@ -819,6 +1155,32 @@ type SandboxDevicePluginSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
+
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // DCGMExporterSpec defines the properties for NVIDIA DCGM Exporter deployment
@@ -873,6 +1235,32 @@ type DCGMExporterSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Optional: Custom metrics configuration for NVIDIA DCGM Exporter
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Custom Metrics Configuration For DCGM Exporter"
@@ -980,6 +1368,32 @@ type DCGMSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// HostPort represents host port that needs to be bound for DCGM engine (Default: 5555)
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Host port to bind for DCGM engine"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:number"
@@ -1037,6 +1451,32 @@ type NodeStatusExporterSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
+
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // DriverRepoConfigSpec defines custom repo configuration for NVIDIA Driver container
@@ -1151,6 +1591,32 @@ type GPUFeatureDiscoverySpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
+
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // MIGManagerSpec defines the properties for deploying NVIDIA MIG Manager
@@ -1205,6 +1671,32 @@ type MIGManagerSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Optional: Custom mig-parted configuration for NVIDIA MIG Manager container
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Custom mig-parted configuration for NVIDIA MIG Manager container"
@@ -1409,6 +1901,32 @@ type KataManagerSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
+
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // CCManagerSpec defines the properties for deploying Confidential Containers (CC) manager
@@ -1469,6 +1987,32 @@ type CCManagerSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Environment Variables"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
+
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 // VFIOManagerSpec defines the properties for deploying VFIO-PCI manager
@@ -1523,6 +2067,32 @@ type VFIOManagerSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// DriverManager represents configuration for NVIDIA Driver Manager
 	DriverManager DriverManagerSpec `json:"driverManager,omitempty"`
 }
@@ -1579,6 +2149,32 @@ type VGPUDeviceManagerSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:text"
 	Env []EnvVar `json:"env,omitempty"`
 
+	// Optional: Patch applied to the pod template of the DaemonSet once it is rendered
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template Patch"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	PodTemplatePatch *PodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
+	// Optional: Set tolerations of the component pods, added to the tolerations of spec.daemonsets
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:io.kubernetes:Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Optional: Set node affinity rules of the component pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Affinity"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:advanced,urn:alm:descriptor:com.tectonic.ui:nodeAffinity"
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Optional: Set priorityClassName of the component pods, replacing the priorityClassName of spec.daemonsets
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="PriorityClassName"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// NVIDIA vGPU devices configuration for NVIDIA vGPU Device Manager container
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="NVIDIA vGPU devices configuration for NVIDIA vGPU Device Manager container"
@@ -1648,6 +2244,40 @@ const (
 	Disabled State = "disabled"
 )
 
+// NodePoolOverride defines the configuration of components overridden for a pool of nodes
+type NodePoolOverride struct {
+	// Name of the node pool, appended to the names of the DaemonSets deployed to the node pool
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the pool. A node must not be selected by more than one node pool.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// DevicePlugin overrides the DevicePlugin component spec for the node pool
+	// +kubebuilder:validation:Optional
+	DevicePlugin *DevicePluginSpec `json:"devicePlugin,omitempty"`
+
+	// DCGMExporter overrides the DCGMExporter component spec for the node pool
+	// +kubebuilder:validation:Optional
+	DCGMExporter *DCGMExporterSpec `json:"dcgmExporter,omitempty"`
+
+	// GPUFeatureDiscovery overrides the GPUFeatureDiscovery component spec for the node pool
+	// +kubebuilder:validation:Optional
+	GPUFeatureDiscovery *GPUFeatureDiscoverySpec `json:"gfd,omitempty"`
+
+	// MIG overrides the MIG spec for the node pool
+	// +kubebuilder:validation:Optional
+	MIG *MIGSpec `json:"mig,omitempty"`
+
+	// MIGManager overrides the MIGManager component spec for the node pool
+	// +kubebuilder:validation:Optional
+	MIGManager *MIGManagerSpec `json:"migManager,omitempty"`
+}
+
 // ClusterPolicyStatus defines the observed state of ClusterPolicy
 type ClusterPolicyStatus struct {
 	// +kubebuilder:validation:Enum=ignored;ready;notReady
@@ -1657,6 +2287,35 @@ type ClusterPolicyStatus struct {
 	Namespace string `json:"namespace,omitempty"`
 	// Conditions is a list of conditions representing the ClusterPolicy's current state.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Components is a list of per-operand statuses, one entry per ClusterPolicy state
+	// +listType=map
+	// +listMapKey=name
+	Components []ComponentStatus `json:"components,omitempty"`
+}
+
+// ComponentStatus defines the observed state of a single GPU operator component (state)
+type ComponentStatus struct {
+	// Name of the state, e.g. state-driver
+	Name string `json:"name"`
+	// Enabled indicates if the state is enabled in the ClusterPolicy
+	Enabled bool `json:"enabled"`
+	// +kubebuilder:validation:Enum=ready;notReady;disabled
+	// State indicates the status of the component
+	State State `json:"state"`
+	// DesiredPods is the number of pods the component DaemonSets should be running
+	DesiredPods int32 `json:"desiredPods,omitempty"`
+	// ReadyPods is the number of pods of the component DaemonSets that are ready
+	ReadyPods int32 `json:"readyPods,omitempty"`
+	// LastTransitionTime is the last time the component transitioned from one state to another
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// NotReadyNodes lists the nodes on which pods of the component are not ready
+	NotReadyNodes []string `json:"notReadyNodes,omitempty"`
+	// Images lists the images of the component DaemonSets pinned to a digest,
+	// in the format <image>@<digest>
+	Images []string `json:"images,omitempty"`
+	// Conditions is a list of conditions representing the component's current state,
+	// e.g. whether the signatures of its images were verified
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -1693,6 +2352,54 @@ func (p *ClusterPolicy) SetStatus(s State, ns string) {
 	p.Status.Namespace = ns
 }
 
+// SetComponentStatus sets the status of a component in the ClusterPolicy instance.
+// LastTransitionTime is only updated when the state of the component changes, and
+// that of its conditions when their status changes.
+func (p *ClusterPolicy) SetComponentStatus(c ComponentStatus) {
+	for i := range p.Status.Components {
+		existing := &p.Status.Components[i]
+		if existing.Name != c.Name {
+			continue
+		}
+		if existing.State == c.State && !existing.LastTransitionTime.IsZero() {
+			c.LastTransitionTime = existing.LastTransitionTime
+		} else if c.LastTransitionTime.IsZero() {
+			c.LastTransitionTime = metav1.Now()
+		}
+		c.Conditions = mergeConditions(existing.Conditions, c.Conditions)
+		*existing = c
+		return
+	}
+	if c.LastTransitionTime.IsZero() {
+		c.LastTransitionTime = metav1.Now()
+	}
+	c.Conditions = mergeConditions(nil, c.Conditions)
+	p.Status.Components = append(p.Status.Components, c)
+}
+
+// mergeConditions returns the conditions, keeping the LastTransitionTime of the
+// previous conditions of the same type and status
+func mergeConditions(previous []metav1.Condition, conditions []metav1.Condition) []metav1.Condition {
+	var merged []metav1.Condition
+	for _, cond := range conditions {
+		if prev := meta.FindStatusCondition(previous, cond.Type); prev != nil && prev.Status == cond.Status {
+			cond.LastTransitionTime = prev.LastTransitionTime
+		}
+		meta.SetStatusCondition(&merged, cond)
+	}
+	return merged
+}
+
+// GetComponentStatus returns the status of the named component, or nil if not found
+func (p *ClusterPolicy) GetComponentStatus(name string) *ComponentStatus {
+	for i := range p.Status.Components {
+		if p.Status.Components[i].Name == name {
+			return &p.Status.Components[i]
+		}
+	}
+	return nil
+}
+
 func imagePath(repository string, image string, version string, imagePathEnvName string) (string, error) {
 	// ImagePath is obtained using following priority
 	// 1. ClusterPolicy (i.e through repository/image/path variables in CRD)
@@ -1724,6 +2431,16 @@ func imagePath(repository string, image string, version string, imagePathEnvName
 	return "", fmt.Errorf("Empty image path provided through both ClusterPolicy CR and ENV %s", imagePathEnvName)
 }
 
+// ImagePath returns the image path of the given component spec, with its prefix
+// rewritten per the image registry mirrors of the operator
+func (c *ClusterPolicySpec) ImagePath(spec interface{}) (string, error) {
+	path, err := ImagePath(spec)
+	if err != nil {
+		return "", err
+	}
+	return c.Operator.MirrorImage(path), nil
+}
+
 // ImagePath sets image path for given component type
 func ImagePath(spec interface{}) (string, error) {
 	switch v := spec.(type) {
@@ -1923,6 +2640,54 @@ func (s *SandboxDevicePluginSpec) IsEnabled() bool {
 	return *s.Enabled
 }
 
+// GetType returns the type of the patch, strategic if not specified by user
+func (p *PodTemplatePatch) GetType() PodTemplatePatchType {
+	if p.Type == "" {
+		return PodTemplatePatchStrategic
+	}
+	return p.Type
+}
+
+// GetDriftPolicy returns the drift policy of operand objects, enforce if not specified by user
+func (o *OperatorSpec) GetDriftPolicy() DriftPolicy {
+	if o.DriftPolicy == "" {
+		return DriftPolicyEnforce
+	}
+	return o.DriftPolicy
+}
+
+// GetDistribution returns the Kubernetes distribution of the cluster, auto if not specified by user
+func (o *OperatorSpec) GetDistribution() Distribution {
+	if o.Distribution == "" {
+		return DistributionAuto
+	}
+	return o.Distribution
+}
+
+// MirrorImage returns the image with its prefix rewritten per the image registry mirrors
+func (o *OperatorSpec) MirrorImage(path string) string {
+	return image.MirrorImage(path, o.ImageRegistryMirrors)
+}
+
+// IsImageVerificationEnabled returns true if the signatures of the operand images are verified
+func (o *OperatorSpec) IsImageVerificationEnabled() bool {
+	return o.ImageVerification != nil && o.ImageVerification.SecretName != ""
+}
+
+// IsPinImageDigestsEnabled returns true if the operand images are pinned to the digests of their tags.
+// The images are pinned when they are verified, otherwise a tag could be pushed again between the
+// verification of its digest and the pull of the image by the nodes.
+func (o *OperatorSpec) IsPinImageDigestsEnabled() bool {
+	if o.IsImageVerificationEnabled() {
+		return true
+	}
+	if o.PinImageDigests == nil {
+		// default is false if not specified by user
+		return false
+	}
+	return *o.PinImageDigests
+}
+
 // IsEnabled returns true if PodSecurityAdmission configuration is enabled for all gpu-operator pods
 func (p *PSASpec) IsEnabled() bool {
 	if p.Enabled == nil {
@@ -2070,3 +2835,96 @@ func (c *CCManagerSpec) IsEnabled() bool {
 	}
 	return *c.Enabled
 }
+
+// ApplyTo merges the component specs overridden by the node pool into spec.
+// Fields set in the overrides replace the ones in spec and lists are replaced
+// as a whole. Components are enabled for the whole cluster, so their enabled
+// field is not overridden.
+func (o *NodePoolOverride) ApplyTo(spec *ClusterPolicySpec) error {
+	if o.DevicePlugin != nil {
+		enabled := spec.DevicePlugin.Enabled
+		if err := mergeSpec(&spec.DevicePlugin, o.DevicePlugin); err != nil {
+			return fmt.Errorf("failed to merge devicePlugin spec of node pool %s: %w", o.Name, err)
+		}
+		spec.DevicePlugin.Enabled = enabled
+	}
+	if o.DCGMExporter != nil {
+		enabled := spec.DCGMExporter.Enabled
+		if err := mergeSpec(&spec.DCGMExporter, o.DCGMExporter); err != nil {
+			return fmt.Errorf("failed to merge dcgmExporter spec of node pool %s: %w", o.Name, err)
+		}
+		spec.DCGMExporter.Enabled = enabled
+	}
+	if o.GPUFeatureDiscovery != nil {
+		enabled := spec.GPUFeatureDiscovery.Enabled
+		if err := mergeSpec(&spec.GPUFeatureDiscovery, o.GPUFeatureDiscovery); err != nil {
+			return fmt.Errorf("failed to merge gfd spec of node pool %s: %w", o.Name, err)
+		}
+		spec.GPUFeatureDiscovery.Enabled = enabled
+	}
+	if o.MIG != nil {
+		if err := mergeSpec(&spec.MIG, o.MIG); err != nil {
+			return fmt.Errorf("failed to merge mig spec of node pool %s: %w", o.Name, err)
+		}
+	}
+	if o.MIGManager != nil {
+		enabled := spec.MIGManager.Enabled
+		if err := mergeSpec(&spec.MIGManager, o.MIGManager); err != nil {
+			return fmt.Errorf("failed to merge migManager spec of node pool %s: %w", o.Name, err)
+		}
+		spec.MIGManager.Enabled = enabled
+	}
+	return nil
+}
+
+// mergeSpec merges the fields set in override into dst, following the JSON merge patch semantics
+func mergeSpec[T any](dst *T, override *T) error {
+	dstMap, err := toJSONMap(dst)
+	if err != nil {
+		return err
+	}
+	overrideMap, err := toJSONMap(override)
+	if err != nil {
+		return err
+	}
+	mergeJSONMaps(dstMap, overrideMap)
+
+	data, err := json.Marshal(dstMap)
+	if err != nil {
+		return err
+	}
+	var merged T
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return err
+	}
+	*dst = merged
+	return nil
+}
+
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func mergeJSONMaps(dst map[string]interface{}, override map[string]interface{}) {
+	for k, v := range override {
+		overrideMap, ok := v.(map[string]interface{})
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dstMap, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dst[k] = overrideMap
+			continue
+		}
+		mergeJSONMaps(dstMap, overrideMap)
+	}
+}
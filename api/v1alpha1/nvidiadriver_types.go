@@ -149,6 +149,20 @@ type NVIDIADriverSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Kernel module configuration parameters for the NVIDIA driver"
 	KernelModuleConfig *KernelModuleConfigSpec `json:"kernelModuleConfig,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// Optional: HTTP proxy of the driver, GDS and GDRCopy containers, overriding operator.proxy of the ClusterPolicy.
+	// Ignored on OpenShift, where the cluster wide proxy is used.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="HTTP Proxy Configuration For NVIDIA Driver Container"
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Optional: ConfigMap holding the CA bundle trusted by the driver, GDS and GDRCopy containers, overriding
+	// operator.trustedCA of the ClusterPolicy. Ignored on OpenShift, where the CA bundle of the cluster wide proxy is used.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Trusted CA Bundle For NVIDIA Driver Container"
+	TrustedCA *TrustedCASpec `json:"trustedCA,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// NodeSelector specifies a selector for installation of NVIDIA driver
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -401,6 +415,34 @@ type DriverCertConfigSpec struct {
 	Name string `json:"name,omitempty"`
 }
 
+// ProxySpec defines the HTTP proxy settings of the NVIDIA Driver container
+type ProxySpec struct {
+	// +kubebuilder:validation:Optional
+	// HTTPProxy is the URL of the proxy for HTTP requests
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// HTTPSProxy is the URL of the proxy for HTTPS requests
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NoProxy is a comma-separated list of hostnames, domains and CIDRs for which the proxy is not used
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// TrustedCASpec references the ConfigMap, in the operator namespace, holding a CA bundle trusted by the NVIDIA Driver container
+type TrustedCASpec struct {
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="ConfigMap Name"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// Key is the key of the PEM encoded CA bundle in the ConfigMap, ca-bundle.crt by default
+	Key string `json:"key,omitempty"`
+}
+
 // DriverRepoConfigSpec defines custom repo configuration for NVIDIA Driver container
 type DriverRepoConfigSpec struct {
 	// +kubebuilder:validation:Optional
@@ -458,6 +500,9 @@ type NVIDIADriverStatus struct {
 	State State `json:"state"`
 	// Namespace indicates a namespace in which the operator and driver are installed
 	Namespace string `json:"namespace,omitempty"`
+	// Images lists the images of the driver DaemonSets pinned to a digest, in the format
+	// <image>@<digest>, when operator.pinImageDigests is enabled in ClusterPolicy
+	Images []string `json:"images,omitempty"`
 	// Conditions is a list of conditions representing the NVIDIADriver's current state.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -34,7 +35,6 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -43,9 +43,17 @@ import (
 
 	clusterpolicyv1 "github.com/NVIDIA/gpu-operator/api/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	operatorassets "github.com/NVIDIA/gpu-operator/assets"
 	"github.com/NVIDIA/gpu-operator/controllers"
 	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
+	"github.com/NVIDIA/gpu-operator/internal/health"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
 	"github.com/NVIDIA/gpu-operator/internal/info"
+	"github.com/NVIDIA/gpu-operator/internal/requeue"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
+	"github.com/NVIDIA/gpu-operator/internal/webhooks"
+	"github.com/NVIDIA/gpu-operator/manifests"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -68,6 +76,12 @@ func main() {
 	var enableLeaderElection bool
 	var probeAddr string
 	var renewDeadline time.Duration
+	var enableWebhooks bool
+	var assetsOverlay assets.Overlay
+	var readyWindow time.Duration
+	var reconcileTimeout time.Duration
+	requeuePolicy := requeue.DefaultPolicy()
+	var tracingOpts tracing.Options
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -79,6 +93,42 @@ func main() {
 			"Only enabled when the --leader-elect flag is set. "+
 			"If undefined, the renew deadline defaults to the controller-runtime manager's default RenewDeadline. "+
 			"By setting this option, the LeaseDuration is also set as RenewDealine + 5s.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Enable the validating admission webhooks for ClusterPolicy and NVIDIADriver. "+
+			"The webhook server serves on port 9443 and requires a TLS certificate in the default controller-runtime certificate directory.")
+	flag.StringVar(&assetsOverlay.Dir, "assets-overlay-dir", "",
+		"Directory holding operand manifest files replacing or complementing the embedded manifests. "+
+			"ClusterPolicy state files are read from <dir>/<state>/ and NVIDIADriver state files from <dir>/manifests/<state>/.")
+	flag.StringVar(&assetsOverlay.ConfigMap, "assets-overlay-configmap", "",
+		"Name of a ConfigMap in the operator namespace holding operand manifest files replacing or complementing the embedded manifests. "+
+			"ClusterPolicy state files are read from keys <state>.<file> and NVIDIADriver state files from keys manifests.<state>.<file>.")
+
+	flag.DurationVar(&readyWindow, "health-ready-window", health.DefaultReadyWindow,
+		"Duration the reconciles of a controller may keep failing before the operator is reported not ready. "+
+			"Set to 0 to only check that the informer caches are synced.")
+	flag.DurationVar(&reconcileTimeout, "health-reconcile-timeout", health.DefaultReconcileTimeout,
+		"Duration after which a running reconcile is considered wedged and the operator is reported not alive. "+
+			"Set to 0 to disable the check.")
+
+	flag.DurationVar(&requeuePolicy.InitialDelay, "requeue-initial-delay", requeue.DefaultInitialDelay,
+		"Delay of the first requeue of an object or state which is not ready, or whose reconcile failed. States which take longer to become ready may use a longer delay.")
+	flag.DurationVar(&requeuePolicy.MaxDelay, "requeue-max-delay", requeue.DefaultMaxDelay,
+		"Maximum delay between the requeues of an object or state which stays not ready, or whose reconcile keeps failing.")
+	flag.Float64Var(&requeuePolicy.Factor, "requeue-backoff-factor", requeue.DefaultFactor,
+		"Multiplier of the delay between consecutive requeues of an object or state which stays not ready, or whose reconcile keeps failing. Set to 1 to requeue at a fixed interval.")
+	flag.Float64Var(&requeuePolicy.Jitter, "requeue-jitter", requeue.DefaultJitter,
+		"Maximum fraction of a requeue delay added at random, between 0 and 1.")
+	flag.DurationVar(&requeuePolicy.NodePollInterval, "requeue-node-poll-interval", requeue.DefaultNodePollInterval,
+		"Interval at which the cluster is polled for new nodes when no node is labelled by NFD.")
+	flag.DurationVar(&requeuePolicy.UpgradeInterval, "requeue-upgrade-interval", requeue.DefaultUpgradeInterval,
+		"Interval at which the driver upgrade state is reconciled while automatic upgrades are enabled.")
+
+	flag.StringVar(&tracingOpts.Endpoint, "tracing-otlp-endpoint", "",
+		"Address (host:port) of the OTLP gRPC collector the reconcile traces are exported to. Tracing is disabled if empty.")
+	flag.BoolVar(&tracingOpts.Insecure, "tracing-otlp-insecure", false,
+		"Connect to the OTLP collector without transport security.")
+	flag.Float64Var(&tracingOpts.SampleRatio, "tracing-sample-ratio", 1,
+		"Fraction of the reconciles traced, between 0 and 1.")
 
 	opts := zap.Options{
 		StacktraceLevel: zapcore.PanicLevel,
@@ -88,6 +138,11 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if err := requeuePolicy.Validate(); err != nil {
+		setupLog.Error(err, "invalid requeue policy")
+		os.Exit(1)
+	}
+
 	ctrl.Log.Info(fmt.Sprintf("version: %s", info.GetVersionString()))
 
 	metricsOptions := metricsserver.Options{
@@ -121,11 +176,43 @@ func main() {
 	}
 
 	ctx := ctrl.SetupSignalHandler()
-	if err = (&controllers.ClusterPolicyReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ClusterPolicy"),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(ctx, mgr); err != nil {
+	tracingOpts.ServiceVersion = info.GetVersionParts()[0]
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	// the API calls of the controllers are traced as part of their reconciles
+	tracedClient := tracing.WrapClient(mgr.GetClient())
+
+	// the readiness and liveness checks of the operator are backed by the reconciles of the controllers
+	healthTracker := health.NewTracker(
+		health.WithReadyWindow(readyWindow),
+		health.WithReconcileTimeout(reconcileTimeout),
+		health.WithCacheSync(mgr.GetCache().WaitForCacheSync),
+		health.WithLeaderElection(mgr.Elected()),
+	)
+	// the controllers back off the requeues of the objects which are not ready with the same policy
+	requeueBackoff := requeue.NewBackoff(requeuePolicy)
+	// the digests and verified signatures of the operand images are shared by the ClusterPolicy
+	// and NVIDIADriver controllers
+	registryClient := registry.NewRegistryClient()
+	imageResolver := registry.NewDigestResolver(registry.RegistryDigestLookup(registryClient))
+	signatureVerifier := registry.NewSignatureVerifier(registryClient)
+
+	clusterPolicyReconciler := &controllers.ClusterPolicyReconciler{
+		Client:     tracedClient,
+		Log:        ctrl.Log.WithName("controllers").WithName("ClusterPolicy"),
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("nvidia-gpu-operator"),
+		APIReader:  mgr.GetAPIReader(),
+		Assets:     assets.NewLoader(operatorassets.FS, "").WithOverlay(assetsOverlay, mgr.GetAPIReader()),
+		Images:     imageResolver,
+		Signatures: signatureVerifier,
+		Health:     healthTracker,
+		Requeue:    requeueBackoff,
+	}
+	if err = clusterPolicyReconciler.SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterPolicy")
 		os.Exit(1)
 	}
@@ -145,10 +232,13 @@ func main() {
 	clusterUpgradeStateManager = clusterUpgradeStateManager.WithPodDeletionEnabled(gpuPodSpecFilter).WithValidationEnabled("app=nvidia-operator-validator")
 
 	if err = (&controllers.UpgradeReconciler{
-		Client:       mgr.GetClient(),
+		Client:       tracedClient,
 		Log:          upgradeLogger,
 		Scheme:       mgr.GetScheme(),
 		StateManager: clusterUpgradeStateManager,
+		ClusterFacts: clusterPolicyReconciler,
+		Health:       healthTracker,
+		Requeue:      requeueBackoff,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Upgrade")
 		os.Exit(1)
@@ -165,25 +255,48 @@ func main() {
 	}
 
 	if err = (&controllers.NVIDIADriverReconciler{
-		Client:      mgr.GetClient(),
+		Client:      tracedClient,
 		Scheme:      mgr.GetScheme(),
 		ClusterInfo: clusterInfo,
+		Recorder:    mgr.GetEventRecorderFor("nvidia-gpu-operator"),
+		APIReader:   mgr.GetAPIReader(),
+		Manifests:   assets.NewLoader(manifests.FS, "manifests").WithOverlay(assetsOverlay, mgr.GetAPIReader()),
+		Images:      imageResolver,
+		Signatures:  signatureVerifier,
+		Health:      healthTracker,
+		Requeue:     requeueBackoff,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NVIDIADriver")
 		os.Exit(1)
 	}
+
+	if enableWebhooks {
+		if err = webhooks.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhooks")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
-	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
+	if err := mgr.AddHealthzCheck("health", healthTracker.HealthzCheck); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("check", healthz.Ping); err != nil {
+	if err := mgr.AddReadyzCheck("check", healthTracker.ReadyzCheck); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctx); err != nil {
+	err = mgr.Start(ctx)
+
+	// flush the spans of the last reconciles
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if shutdownErr := shutdownTracing(shutdownCtx); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "failed to flush the traces")
+	}
+	cancel()
+
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
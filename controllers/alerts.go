@@ -0,0 +1,123 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// alertThresholdPlaceholder is replaced by the threshold of the alert in its expression and annotations
+const alertThresholdPlaceholder = "${threshold}"
+
+// defaultAlertThresholds are the thresholds of the alerts which have one, unless overridden in
+// the ClusterPolicy: the seconds since the last successful reconcile for the reconciliation
+// alerts, the number of pods or nodes otherwise
+var defaultAlertThresholds = map[string]int64{
+	"GPUOperatorReconciliationFailed":                 3600,
+	"GPUOperatorReconciliationFailedNfdLabelsMissing": 1800,
+	"GPUOperatorDriverAutoUpgradeFailures":            0,
+	"GPUOperatorDriverUpgradeStalled":                 0,
+	"GPUOperatorOperandPodsNotReady":                  0,
+	"GPUOperatorValidationFailed":                     0,
+}
+
+// getUnknownAlertRules returns the sorted names of the alerts overridden in the ClusterPolicy
+// which are not part of any of the PrometheusRules of the operator
+func getUnknownAlertRules(resources []Resources, alerts *gpuv1.AlertsSpec) []string {
+	if alerts == nil || len(alerts.Rules) == 0 {
+		return nil
+	}
+	known := map[string]bool{}
+	for i := range resources {
+		for _, group := range resources[i].PrometheusRule.Spec.Groups {
+			for _, rule := range group.Rules {
+				if rule.Alert != "" {
+					known[rule.Alert] = true
+				}
+			}
+		}
+	}
+	unknown := []string{}
+	for name := range alerts.Rules {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// transformPrometheusRuleAlerts applies the alerts configuration of the ClusterPolicy to the rules
+// of the PrometheusRule: disabled alerts are removed, and the thresholds, for durations and labels
+// of the others are set
+func transformPrometheusRuleAlerts(obj *promv1.PrometheusRule, alerts *gpuv1.AlertsSpec) {
+	groups := obj.Spec.Groups[:0]
+	for _, group := range obj.Spec.Groups {
+		rules := group.Rules[:0]
+		for _, rule := range group.Rules {
+			config := gpuv1.AlertRuleSpec{}
+			if alerts != nil {
+				config = alerts.Rules[rule.Alert]
+			}
+			if !config.IsEnabled() {
+				continue
+			}
+
+			threshold, ok := defaultAlertThresholds[rule.Alert]
+			if config.Threshold != nil {
+				threshold, ok = *config.Threshold, true
+			}
+			if ok {
+				value := strconv.FormatInt(threshold, 10)
+				rule.Expr = intstr.FromString(strings.ReplaceAll(rule.Expr.String(), alertThresholdPlaceholder, value))
+				for key, annotation := range rule.Annotations {
+					rule.Annotations[key] = strings.ReplaceAll(annotation, alertThresholdPlaceholder, value)
+				}
+			}
+			if config.For != "" {
+				rule.For = config.For
+			}
+
+			if rule.Labels == nil {
+				rule.Labels = map[string]string{}
+			}
+			if alerts != nil {
+				for key, value := range alerts.AdditionalLabels {
+					rule.Labels[key] = value
+				}
+			}
+			for key, value := range config.Labels {
+				rule.Labels[key] = value
+			}
+			rules = append(rules, rule)
+		}
+		// groups without rules are dropped
+		if len(rules) == 0 {
+			continue
+		}
+		group.Rules = rules
+		groups = append(groups, group)
+	}
+	obj.Spec.Groups = groups
+}
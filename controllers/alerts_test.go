@@ -0,0 +1,229 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/yaml"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	operatorassets "github.com/NVIDIA/gpu-operator/assets"
+)
+
+// loadTestPrometheusRule returns the embedded PrometheusRule of the operator alerts
+func loadTestPrometheusRule(t *testing.T) *promv1.PrometheusRule {
+	data, err := operatorassets.FS.ReadFile("state-operator-metrics/0400_prometheus_rule.yaml")
+	require.NoError(t, err)
+	obj := &promv1.PrometheusRule{}
+	require.NoError(t, yaml.Unmarshal(data, obj))
+	return obj
+}
+
+// getTestAlert returns the rule of the alert, nil if the PrometheusRule does not hold it
+func getTestAlert(obj *promv1.PrometheusRule, alert string) *promv1.Rule {
+	for _, group := range obj.Spec.Groups {
+		for i := range group.Rules {
+			if group.Rules[i].Alert == alert {
+				return &group.Rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+func TestTransformPrometheusRuleAlertsDefaults(t *testing.T) {
+	obj := loadTestPrometheusRule(t)
+	transformPrometheusRuleAlerts(obj, nil)
+
+	for _, alert := range []string{
+		"GPUOperatorOperandNotReady",
+		"GPUOperatorOperandPodsNotReady",
+		"GPUOperatorNVIDIADriverNotReady",
+		"GPUOperatorValidationFailed",
+		"GPUOperatorDriverUpgradeStalled",
+	} {
+		require.NotNil(t, getTestAlert(obj, alert), alert)
+	}
+
+	for _, group := range obj.Spec.Groups {
+		for _, rule := range group.Rules {
+			require.NotContains(t, rule.Expr.String(), alertThresholdPlaceholder, rule.Alert)
+			for _, annotation := range rule.Annotations {
+				require.NotContains(t, annotation, alertThresholdPlaceholder, rule.Alert)
+			}
+		}
+	}
+	require.Contains(t, getTestAlert(obj, "GPUOperatorReconciliationFailed").Expr.String(), "> 3600)")
+	require.Equal(t, promv1.Duration("15m"), getTestAlert(obj, "GPUOperatorOperandNotReady").For)
+}
+
+func TestTransformPrometheusRuleAlerts(t *testing.T) {
+	obj := loadTestPrometheusRule(t)
+	threshold := int64(7200)
+	alerts := &gpuv1.AlertsSpec{
+		Enabled:          boolTrue,
+		AdditionalLabels: map[string]string{"team": "gpu", "severity": "info"},
+		Rules: map[string]gpuv1.AlertRuleSpec{
+			"GPUOperatorOperandNotReady": {Enabled: boolFalse},
+			"GPUOperatorReconciliationFailed": {
+				Threshold: &threshold,
+			},
+			"GPUOperatorValidationFailed": {
+				For:    "5m",
+				Labels: map[string]string{"severity": "critical"},
+			},
+		},
+	}
+	transformPrometheusRuleAlerts(obj, alerts)
+
+	require.Nil(t, getTestAlert(obj, "GPUOperatorOperandNotReady"))
+
+	rule := getTestAlert(obj, "GPUOperatorReconciliationFailed")
+	require.NotNil(t, rule)
+	require.Contains(t, rule.Expr.String(), "> 7200)")
+	require.Contains(t, rule.Annotations["summary"], "7200s")
+	require.Equal(t, map[string]string{"team": "gpu", "severity": "info"}, rule.Labels)
+
+	rule = getTestAlert(obj, "GPUOperatorValidationFailed")
+	require.NotNil(t, rule)
+	require.Equal(t, promv1.Duration("5m"), rule.For)
+	require.True(t, strings.HasSuffix(strings.TrimSpace(rule.Expr.String()), "> 0"))
+	require.Equal(t, map[string]string{"team": "gpu", "severity": "critical"}, rule.Labels)
+}
+
+func TestTransformPrometheusRuleAlertsEmptyGroup(t *testing.T) {
+	obj := &promv1.PrometheusRule{
+		Spec: promv1.PrometheusRuleSpec{
+			Groups: []promv1.RuleGroup{
+				{Name: "first", Rules: []promv1.Rule{{Alert: "First"}}},
+				{Name: "second", Rules: []promv1.Rule{{Alert: "Second"}}},
+			},
+		},
+	}
+	alerts := &gpuv1.AlertsSpec{
+		Rules: map[string]gpuv1.AlertRuleSpec{"First": {Enabled: boolFalse}},
+	}
+	transformPrometheusRuleAlerts(obj, alerts)
+
+	require.Len(t, obj.Spec.Groups, 1)
+	require.Equal(t, "second", obj.Spec.Groups[0].Name)
+}
+
+func TestAlertsSpecIsEnabled(t *testing.T) {
+	var alerts *gpuv1.AlertsSpec
+	require.True(t, alerts.IsEnabled(true))
+	require.False(t, alerts.IsEnabled(false))
+
+	alerts = &gpuv1.AlertsSpec{Enabled: boolTrue}
+	require.True(t, alerts.IsEnabled(false))
+	alerts = &gpuv1.AlertsSpec{Enabled: boolFalse}
+	require.False(t, alerts.IsEnabled(true))
+}
+
+func TestGetUnknownAlertRules(t *testing.T) {
+	resources := []Resources{
+		{PrometheusRule: *loadTestPrometheusRule(t)},
+		{},
+		{PrometheusRule: promv1.PrometheusRule{
+			Spec: promv1.PrometheusRuleSpec{Groups: []promv1.RuleGroup{{Rules: []promv1.Rule{{Alert: "GPUOperatorNodeDeploymentFailed"}}}}},
+		}},
+	}
+	require.Empty(t, getUnknownAlertRules(resources, nil))
+
+	alerts := &gpuv1.AlertsSpec{
+		Rules: map[string]gpuv1.AlertRuleSpec{
+			"GPUOperatorOperandNotReady":      {Enabled: boolFalse},
+			"GPUOperatorNodeDeploymentFailed": {Enabled: boolFalse},
+		},
+	}
+	require.Empty(t, getUnknownAlertRules(resources, alerts))
+
+	alerts.Rules["GPUOperatorOperandNotReadyy"] = gpuv1.AlertRuleSpec{}
+	alerts.Rules["GPUOperatorAlert"] = gpuv1.AlertRuleSpec{}
+	require.Equal(t, []string{"GPUOperatorAlert", "GPUOperatorOperandNotReadyy"}, getUnknownAlertRules(resources, alerts))
+}
+
+// TestPrometheusRuleDisabled tests that the PrometheusRule of disabled alerts is deleted once,
+// and that unknown alerts are rejected
+func TestPrometheusRuleDisabled(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, apiextensionsv1.AddToScheme(s))
+	require.NoError(t, promv1.AddToScheme(s))
+	require.NoError(t, gpuv1.AddToScheme(s))
+
+	rule := loadTestPrometheusRule(t)
+	rule.Namespace = "test-operator"
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: PrometheusRuleCRDName}}
+	deletes := 0
+	c := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(crd, rule.DeepCopy()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deletes++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	n := ClusterPolicyController{
+		ctx: ctx,
+		singleton: &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+			Spec: gpuv1.ClusterPolicySpec{
+				Operator: gpuv1.OperatorSpec{Alerts: &gpuv1.AlertsSpec{Enabled: boolFalse}},
+			},
+		},
+		operatorNamespace: "test-operator",
+		resources:         []Resources{{PrometheusRule: *loadTestPrometheusRule(t)}},
+		rec: &ClusterPolicyReconciler{
+			Client: c,
+			Log:    ctrl.Log.WithName("test"),
+			Scheme: s,
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		state, err := PrometheusRule(n)
+		require.NoError(t, err)
+		require.Equal(t, gpuv1.Ready, state)
+	}
+	require.Equal(t, 1, deletes)
+	err := c.Get(ctx, client.ObjectKeyFromObject(rule), &promv1.PrometheusRule{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	n.singleton.Spec.Operator.Alerts.Rules = map[string]gpuv1.AlertRuleSpec{"GPUOperatorUnknown": {}}
+	state, err := PrometheusRule(n)
+	require.ErrorContains(t, err, "GPUOperatorUnknown")
+	require.Equal(t, gpuv1.NotReady, state)
+}
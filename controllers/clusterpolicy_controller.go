@@ -19,22 +19,25 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/go-logr/logr"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/util/workqueue"
+	"k8s.io/client-go/tools/record"
 
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -44,25 +47,76 @@ import (
 	"github.com/NVIDIA/k8s-operator-libs/pkg/consts"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/apply"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
 	"github.com/NVIDIA/gpu-operator/internal/conditions"
+	"github.com/NVIDIA/gpu-operator/internal/health"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
+	"github.com/NVIDIA/gpu-operator/internal/plan"
+	"github.com/NVIDIA/gpu-operator/internal/requeue"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
 )
 
 const (
-	minDelayCR                      = 100 * time.Millisecond
-	maxDelayCR                      = 3 * time.Second
 	clusterPolicyControllerIndexKey = "metadata.nvidia.clusterpolicy.controller"
 )
 
 // blank assignment to verify that ReconcileClusterPolicy implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ClusterPolicyReconciler{}
-var clusterPolicyCtrl ClusterPolicyController
+
+// blank assignment to verify that ClusterPolicyReconciler implements ClusterFacts
+var _ ClusterFacts = &ClusterPolicyReconciler{}
+
+// ClusterFacts provides the facts discovered about the cluster while reconciling the active ClusterPolicy
+type ClusterFacts interface {
+	// OperatorNamespace returns the namespace of the operator, empty until the first reconciliation
+	OperatorNamespace() string
+	// DriverToolkitEnabled returns true if the driver is deployed with the OpenShift Driver Toolkit
+	DriverToolkitEnabled() bool
+	// Metrics returns the operator metrics, nil until the first reconciliation
+	Metrics() *OperatorMetrics
+}
 
 // ClusterPolicyReconciler reconciles a ClusterPolicy object
 type ClusterPolicyReconciler struct {
 	client.Client
-	Log              logr.Logger
-	Scheme           *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// APIReader reads the Secrets without caching them, the Client is used if nil
+	APIReader client.Reader
+	// Assets loads the manifests of the states, the manifests embedded in the operator if nil
+	Assets *assets.Loader
+	// Images pins the operand images to the digests of their tags when enabled in the ClusterPolicy,
+	// the tags are resolved with the image registries if nil
+	Images *registry.DigestResolver
+	// Signatures verifies the signatures of the operand images when enabled in the ClusterPolicy,
+	// the signatures are fetched from the image registries if nil
+	Signatures *registry.SignatureVerifier
+	// Health records the reconciles for the readiness and liveness checks of the operator, if set
+	Health *health.Tracker
+	// Requeue computes the requeue delays, the delays of the default policy are used if nil
+	Requeue          *requeue.Backoff
 	conditionUpdater conditions.Updater
+
+	// mu guards the fields below, which are shared across reconciliations
+	mu sync.RWMutex
+	// states holds what is loaded once for the lifetime of the operator: the
+	// resources and controls of all states, static cluster facts and metrics
+	states *ClusterPolicyController
+	// statesStale is set when the overlay of the manifests changed, the resources
+	// and controls of the states are reloaded by the next reconciliation
+	statesStale bool
+	// active is the ClusterPolicy managed by the operator, nil if none is active yet
+	active *gpuv1.ClusterPolicy
+	// driverToolkitEnabled is set by the last reconciliation of the active ClusterPolicy
+	driverToolkitEnabled bool
+	// stateStatuses holds the last known status of each state, to record their transitions
+	stateStatuses map[string]string
+	// stateDue holds the time at which each state not ready is synced again
+	stateDue map[string]time.Time
+	// stateDueKey identifies the ClusterPolicy name and generation the due times apply to
+	stateDueKey string
 }
 
 // +kubebuilder:rbac:groups=nvidia.com,resources=*,verbs=get;list;watch;create;update;patch;delete
@@ -79,7 +133,7 @@ type ClusterPolicyReconciler struct {
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=get;list;watch
-// +kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=get;list;create;update;watch;delete
+// +kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=get;list;create;update;patch;watch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -99,12 +153,22 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	var condErr error
 	err := r.Client.Get(ctx, req.NamespacedName, instance)
 	if err != nil {
-		err = fmt.Errorf("Failed to get ClusterPolicy object: %v", err)
+		err = fmt.Errorf("Failed to get ClusterPolicy object: %w", err)
 		r.Log.Error(nil, err.Error())
-		clusterPolicyCtrl.operatorMetrics.reconciliationStatus.Set(reconciliationStatusClusterPolicyUnavailable)
+		if metrics := r.Metrics(); metrics != nil {
+			metrics.reconciliationStatus.Set(reconciliationStatusClusterPolicyUnavailable)
+		}
 		if apierrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// If the active ClusterPolicy was deleted, reset the controller so that another
+			// ClusterPolicy can be promoted; the deletion event requeues all remaining instances.
+			if active := r.getActive(); active != nil && active.Name == req.Name {
+				r.setActive(nil)
+			}
+			// drop the requeues tracked for the deleted ClusterPolicy and its states
+			r.Requeue.Forget(req.Name)
+			r.Requeue.Forget(planRequeueKey("ClusterPolicy", req.Name))
 			// Return and don't requeue
 			return reconcile.Result{}, nil
 		}
@@ -116,16 +180,62 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return reconcile.Result{}, err
 	}
 
-	// TODO: Handle deletion of the main ClusterPolicy and cycle to the next one.
-	// We already have a main Clusterpolicy
-	if clusterPolicyCtrl.singleton != nil && clusterPolicyCtrl.singleton.ObjectMeta.Name != instance.ObjectMeta.Name {
-		instance.SetStatus(gpuv1.Ignored, clusterPolicyCtrl.operatorNamespace)
-		// do not change `clusterPolicyCtrl.operatorMetrics.reconciliationStatus` here,
+	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
+		// remove the operands in order before the ClusterPolicy is deleted
+		inProgress, err := r.reconcileDelete(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if inProgress {
+			return ctrl.Result{RequeueAfter: r.Requeue.Delay(req.Name+"/delete", 0)}, nil
+		}
+		r.Requeue.Reset(req.Name + "/delete")
+		return ctrl.Result{}, nil
+	}
+
+	if active := r.getActive(); active == nil {
+		// No active ClusterPolicy yet (or the previous one was deleted),
+		// deterministically promote the oldest ClusterPolicy
+		active, err := getActiveClusterPolicy(ctx, r.Client)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if active != nil && active.Name != instance.Name {
+			r.Log.Info("ClusterPolicy is not the oldest instance, ignoring", "name", instance.Name, "active", active.Name)
+			updateCRState(ctx, r, req.NamespacedName, gpuv1.Ignored)
+			return ctrl.Result{}, nil
+		}
+	} else if active.ObjectMeta.Name != instance.ObjectMeta.Name {
+		// We already have a main Clusterpolicy
+		// do not change the reconciliation status metric here,
 		// spurious reconciliation
-		return ctrl.Result{}, err
+		updateCRState(ctx, r, req.NamespacedName, gpuv1.Ignored)
+		return ctrl.Result{}, nil
 	}
+	r.setActive(instance)
 
-	err = clusterPolicyCtrl.init(ctx, r, instance)
+	if instance.Spec.Operator.IsPinImageDigestsEnabled() && r.Images != nil {
+		// keep the digests resolved before, e.g. by a previous instance of the operator
+		for _, component := range instance.Status.Components {
+			r.Images.Add(component.Images...)
+		}
+	}
+
+	if !controllerutil.ContainsFinalizer(instance, clusterPolicyFinalizer) {
+		controllerutil.AddFinalizer(instance, clusterPolicyFinalizer)
+		if err = r.Client.Update(ctx, instance); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer to ClusterPolicy: %w", err)
+		}
+	}
+
+	if plan.Enabled(instance) {
+		// report the operand changes without applying them
+		return r.reconcilePlan(ctx, instance)
+	}
+
+	// record the conflicts with other field managers while applying the operands
+	ctx, conflicts := apply.WithConflicts(ctx)
+	clusterPolicyCtrl, err := r.newClusterPolicyController(ctx, r, instance)
 	if err != nil {
 		err = fmt.Errorf("Failed to initialize ClusterPolicy controller: %v", err)
 		r.Log.Error(nil, err.Error())
@@ -133,11 +243,12 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		if condErr != nil {
 			r.Log.V(consts.LogLevelDebug).Error(nil, condErr.Error())
 		}
-		if clusterPolicyCtrl.operatorMetrics != nil {
-			clusterPolicyCtrl.operatorMetrics.reconciliationStatus.Set(reconciliationStatusClusterPolicyUnavailable)
+		if metrics := r.Metrics(); metrics != nil {
+			metrics.reconciliationStatus.Set(reconciliationStatusClusterPolicyUnavailable)
 		}
 		return ctrl.Result{}, err
 	}
+	r.setDriverToolkitEnabled(clusterPolicyCtrl.openshift != "" && clusterPolicyCtrl.ocpDriverToolkit.enabled)
 
 	if !clusterPolicyCtrl.hasNFDLabels {
 		r.Log.Info("WARNING: NFD labels missing in the cluster, GPU nodes cannot be discovered.")
@@ -152,33 +263,86 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	clusterPolicyCtrl.operatorMetrics.reconciliationTotal.Inc()
 	overallStatus := gpuv1.Ready
 	statesNotReady := []string{}
-	for {
-		status, statusError := clusterPolicyCtrl.step()
-		if statusError != nil {
-			clusterPolicyCtrl.operatorMetrics.reconciliationStatus.Set(reconciliationStatusNotReady)
-			clusterPolicyCtrl.operatorMetrics.reconciliationFailed.Inc()
-			updateCRState(ctx, r, req.NamespacedName, gpuv1.NotReady)
-			condErr = r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ReconcileFailed, fmt.Sprintf("Failed to reconcile %s: %s", clusterPolicyCtrl.stateNames[clusterPolicyCtrl.idx], statusError.Error()))
-			if condErr != nil {
-				r.Log.V(consts.LogLevelDebug).Error(nil, condErr.Error())
+	components := []gpuv1.ComponentStatus{}
+	requeueAfter := time.Duration(0)
+	var failedState string
+	var statusError error
+	now := time.Now()
+	stateDue := r.getStateDueTimes(instance)
+	isDue := func(name string) bool {
+		return !stateDue[name].After(now)
+	}
+	for _, res := range clusterPolicyCtrl.syncStates(isDue) {
+		status := res.status
+		if res.err != nil {
+			if statusError == nil {
+				failedState, statusError = res.name, res.err
 			}
-			return ctrl.Result{RequeueAfter: time.Second * 5}, statusError
+			status = gpuv1.NotReady
+			r.Log.Error(res.err, "ClusterPolicy step failed", "state", res.name)
 		}
 
 		if status == gpuv1.NotReady {
 			overallStatus = gpuv1.NotReady
-			statesNotReady = append(statesNotReady, clusterPolicyCtrl.stateNames[clusterPolicyCtrl.idx-1])
+			statesNotReady = append(statesNotReady, res.name)
+			// each state backs off from its own interval while it stays not ready and is
+			// only synced again once due, requeue when the first of them is due
+			delay := stateDue[res.name].Sub(now)
+			if !res.deferred {
+				delay = r.Requeue.Delay(req.Name+"/"+res.name, getStateRequeueInterval(res.name))
+				r.setStateDue(res.name, now.Add(delay))
+			}
+			if requeueAfter == 0 || delay < requeueAfter {
+				requeueAfter = delay
+			}
+		} else {
+			r.Requeue.Reset(req.Name + "/" + res.name)
+			r.setStateDue(res.name, time.Time{})
+		}
+		component := clusterPolicyCtrl.getComponentStatus(res.idx, status)
+		if component.Enabled && instance.Spec.Operator.IsImageVerificationEnabled() &&
+			clusterPolicyCtrl.resources[res.idx].DaemonSet.Name != "" {
+			cond := getImagesVerifiedCondition(instance.GetComponentStatus(res.name), res.err, res.skipped || res.deferred)
+			if cond != nil {
+				component.Conditions = append(component.Conditions, *cond)
+			}
+		}
+		components = append(components, component)
+		switch {
+		case res.skipped, res.deferred:
+			// the status of a skipped or deferred state was not observed
+		case res.err != nil:
+			r.recordStateTransition(instance, res.name, stateStatusError)
+			clusterPolicyCtrl.operatorMetrics.setStateStatus(res.name, status)
+		default:
+			r.recordStateTransition(instance, res.name, string(status))
+			clusterPolicyCtrl.operatorMetrics.setStateStatus(res.name, status)
 		}
-		r.Log.Info("ClusterPolicy step completed",
-			"state:", clusterPolicyCtrl.stateNames[clusterPolicyCtrl.idx-1],
-			"status", status)
+		if res.err == nil && !res.deferred {
+			r.Log.Info("ClusterPolicy step completed",
+				"state:", res.name,
+				"status", status,
+				"skipped", res.skipped)
+		}
+	}
+	updateCRComponentStatus(ctx, r, req.NamespacedName, components)
+	condErr = r.conditionUpdater.SetConditionConflict(ctx, instance, conflicts.List())
+	if condErr != nil {
+		r.Log.V(consts.LogLevelDebug).Error(nil, condErr.Error())
+	}
 
-		if clusterPolicyCtrl.last() {
-			break
+	if statusError != nil {
+		clusterPolicyCtrl.operatorMetrics.reconciliationStatus.Set(reconciliationStatusNotReady)
+		clusterPolicyCtrl.operatorMetrics.reconciliationFailed.Inc()
+		updateCRState(ctx, r, req.NamespacedName, gpuv1.NotReady)
+		condErr = r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ReconcileFailed, fmt.Sprintf("Failed to reconcile %s: %s", failedState, statusError.Error()))
+		if condErr != nil {
+			r.Log.V(consts.LogLevelDebug).Error(nil, condErr.Error())
 		}
+		return ctrl.Result{}, statusError
 	}
 
-	// if any state is not ready, requeue for reconfile after 5 seconds
+	// if any state is not ready, requeue for reconcile once the first of them is due
 	if overallStatus != gpuv1.Ready {
 		clusterPolicyCtrl.operatorMetrics.reconciliationStatus.Set(reconciliationStatusNotReady)
 		clusterPolicyCtrl.operatorMetrics.reconciliationFailed.Inc()
@@ -190,13 +354,13 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		if condErr != nil {
 			r.Log.V(consts.LogLevelDebug).Error(nil, condErr.Error())
 		}
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	if !clusterPolicyCtrl.hasNFDLabels {
 		// no NFD-labelled node in the cluster (required dependency),
 		// watch periodically for the labels to appear
-		var requeueAfter = time.Second * 45
+		requeueAfter = r.Requeue.NodePollInterval()
 		r.Log.Info("No NFD label found, polling for new nodes.",
 			"requeueAfter", requeueAfter)
 
@@ -234,6 +398,139 @@ func (r *ClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return ctrl.Result{}, nil
 }
 
+// newClusterPolicyController returns the controller used for a single reconciliation of the
+// ClusterPolicy, initialized with the current cluster facts. The states are loaded once and
+// shared read-only by all controllers. rec is the reconciler the states use to access the cluster.
+func (r *ClusterPolicyReconciler) newClusterPolicyController(ctx context.Context, rec *ClusterPolicyReconciler, instance *gpuv1.ClusterPolicy) (ClusterPolicyController, error) {
+	n, err := r.loadStates(ctx)
+	if err != nil {
+		return n, err
+	}
+	err = n.init(ctx, rec, instance)
+	return n, err
+}
+
+// loadStates loads the states on the first call and returns a copy of the controller holding them
+func (r *ClusterPolicyReconciler) loadStates(ctx context.Context) (ClusterPolicyController, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.states == nil {
+		n := &ClusterPolicyController{ctx: ctx, rec: r}
+		if err := n.initStates(ctx); err != nil {
+			return ClusterPolicyController{}, err
+		}
+		r.states = n
+	} else if r.statesStale {
+		n := *r.states
+		n.ctx = ctx
+		if err := n.addStates(); err != nil {
+			return ClusterPolicyController{}, err
+		}
+		r.Log.Info("Manifests of the states reloaded")
+		r.states = &n
+	}
+	r.statesStale = false
+	return *r.states, nil
+}
+
+// invalidateStates makes the next reconciliation reload the manifests of the states
+func (r *ClusterPolicyReconciler) invalidateStates() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statesStale = true
+}
+
+// getActive returns the ClusterPolicy managed by the operator, nil if none is active
+func (r *ClusterPolicyReconciler) getActive() *gpuv1.ClusterPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.active
+}
+
+// setActive sets the ClusterPolicy managed by the operator. Passing nil clears the
+// facts tied to the previously active ClusterPolicy, so that another one can be promoted.
+func (r *ClusterPolicyReconciler) setActive(instance *gpuv1.ClusterPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if instance == nil {
+		r.active = nil
+		r.driverToolkitEnabled = false
+		r.stateStatuses = nil
+		return
+	}
+	if r.active == nil || r.active.UID != instance.UID {
+		// the state transitions of another ClusterPolicy do not apply
+		r.stateStatuses = nil
+	}
+	r.active = instance.DeepCopy()
+}
+
+func (r *ClusterPolicyReconciler) setDriverToolkitEnabled(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.driverToolkitEnabled = enabled
+}
+
+// secretReader returns the reader of the Secrets, which are not cached by the operator
+func (r *ClusterPolicyReconciler) secretReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// OperatorNamespace implements ClusterFacts
+func (r *ClusterPolicyReconciler) OperatorNamespace() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.states == nil {
+		return ""
+	}
+	return r.states.operatorNamespace
+}
+
+// DriverToolkitEnabled implements ClusterFacts
+func (r *ClusterPolicyReconciler) DriverToolkitEnabled() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.driverToolkitEnabled
+}
+
+// Metrics implements ClusterFacts
+func (r *ClusterPolicyReconciler) Metrics() *OperatorMetrics {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.states == nil {
+		return nil
+	}
+	return r.states.operatorMetrics
+}
+
+// getActiveClusterPolicy returns the ClusterPolicy to be reconciled by the operator,
+// which is the oldest instance not being deleted. Instances created at the same time
+// are ordered by name. nil is returned if no such ClusterPolicy exists.
+func getActiveClusterPolicy(ctx context.Context, c client.Client) (*gpuv1.ClusterPolicy, error) {
+	list := &gpuv1.ClusterPolicyList{}
+	err := c.List(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterPolicies: %w", err)
+	}
+
+	var active *gpuv1.ClusterPolicy
+	for i := range list.Items {
+		cp := &list.Items[i]
+		if !cp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if active == nil ||
+			cp.CreationTimestamp.Before(&active.CreationTimestamp) ||
+			(cp.CreationTimestamp.Equal(&active.CreationTimestamp) && cp.Name < active.Name) {
+			active = cp
+		}
+	}
+	return active, nil
+}
+
 func updateCRState(ctx context.Context, r *ClusterPolicyReconciler, namespacedName types.NamespacedName, state gpuv1.State) {
 	// Fetch latest instance and update state to avoid version mismatch
 	instance := &gpuv1.ClusterPolicy{}
@@ -246,13 +543,37 @@ func updateCRState(ctx context.Context, r *ClusterPolicyReconciler, namespacedNa
 		return
 	}
 	// Update the CR state
-	instance.SetStatus(state, clusterPolicyCtrl.operatorNamespace)
+	instance.SetStatus(state, r.OperatorNamespace())
 	err = r.Client.Status().Update(ctx, instance)
 	if err != nil {
 		r.Log.Error(err, "Failed to update ClusterPolicy status")
 	}
 }
 
+// updateCRComponentStatus merges the provided per-state statuses into the
+// ClusterPolicy status. Components not present in the list keep their last known status.
+func updateCRComponentStatus(ctx context.Context, r *ClusterPolicyReconciler, namespacedName types.NamespacedName, components []gpuv1.ComponentStatus) {
+	// Fetch latest instance and update components to avoid version mismatch
+	instance := &gpuv1.ClusterPolicy{}
+	err := r.Client.Get(ctx, namespacedName, instance)
+	if err != nil {
+		r.Log.Error(err, "Failed to get ClusterPolicy instance for status update")
+		return
+	}
+	orig := instance.DeepCopy()
+	for _, c := range components {
+		instance.SetComponentStatus(c)
+	}
+	if equality.Semantic.DeepEqual(orig.Status.Components, instance.Status.Components) {
+		// components are unchanged
+		return
+	}
+	err = r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		r.Log.Error(err, "Failed to update ClusterPolicy component status")
+	}
+}
+
 func addWatchNewGPUNode(ctx context.Context, r *ClusterPolicyReconciler, c controller.Controller, mgr ctrl.Manager) error {
 	// Define a mapping from the Node object in the event to one or more
 	// ClusterPolicy objects to Reconcile
@@ -296,20 +617,27 @@ func addWatchNewGPUNode(ctx context.Context, r *ClusterPolicyReconciler, c contr
 			migManagerLabelMissing := hasMIGCapableGPU(newLabels) && !hasMIGManagerLabel(newLabels)
 			commonOperandsLabelChanged := hasOperandsDisabled(oldLabels) != hasOperandsDisabled(newLabels)
 
-			oldGPUWorkloadConfig, _ := getWorkloadConfig(oldLabels, true)
-			newGPUWorkloadConfig, _ := getWorkloadConfig(newLabels, true)
+			oldGPUWorkloadConfig, _ := getWorkloadConfig(oldLabels, true, gpuWorkloadConfigContainer)
+			newGPUWorkloadConfig, _ := getWorkloadConfig(newLabels, true, gpuWorkloadConfigContainer)
 			gpuWorkloadConfigLabelChanged := oldGPUWorkloadConfig != newGPUWorkloadConfig
 
 			oldOSTreeLabel := oldLabels[nfdOSTreeVersionLabelKey]
 			newOSTreeLabel := newLabels[nfdOSTreeVersionLabelKey]
 			osTreeLabelChanged := oldOSTreeLabel != newOSTreeLabel
 
+			nodePoolLabelOutdated := isNodePoolLabelOutdated(r.getActive(), newLabels)
+
+			newNode, _ := e.ObjectNew.(*corev1.Node)
+			runtimeLabelOutdated := isRuntimeLabelOutdated(newNode)
+
 			needsUpdate := gpuCommonLabelMissing ||
 				gpuCommonLabelOutdated ||
 				migManagerLabelMissing ||
 				commonOperandsLabelChanged ||
 				gpuWorkloadConfigLabelChanged ||
-				osTreeLabelChanged
+				osTreeLabelChanged ||
+				nodePoolLabelOutdated ||
+				runtimeLabelOutdated
 
 			if needsUpdate {
 				r.Log.Info("Node needs an update",
@@ -320,6 +648,7 @@ func addWatchNewGPUNode(ctx context.Context, r *ClusterPolicyReconciler, c contr
 					"commonOperandsLabelChanged", commonOperandsLabelChanged,
 					"gpuWorkloadConfigLabelChanged", gpuWorkloadConfigLabelChanged,
 					"osTreeLabelChanged", osTreeLabelChanged,
+					"runtimeLabelOutdated", runtimeLabelOutdated,
 				)
 			}
 			return needsUpdate
@@ -351,7 +680,7 @@ func addWatchNewGPUNode(ctx context.Context, r *ClusterPolicyReconciler, c contr
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterPolicyReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	// Create a new controller
-	c, err := controller.New("clusterpolicy-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: 1, RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(minDelayCR, maxDelayCR)})
+	c, err := controller.New("clusterpolicy-controller", mgr, controller.Options{Reconciler: r.Health.Wrap("clusterpolicy-controller", tracing.WrapReconciler("clusterpolicy-controller", r)), MaxConcurrentReconciles: 1, RateLimiter: r.Requeue.RateLimiter("clusterpolicy-controller")})
 	if err != nil {
 		return err
 	}
@@ -359,12 +688,57 @@ func (r *ClusterPolicyReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 	// initialize condition updater
 	r.conditionUpdater = conditions.NewClusterPolicyUpdater(mgr.GetClient())
 
+	if r.Images == nil || r.Signatures == nil {
+		registryClient := registry.NewRegistryClient()
+		if r.Images == nil {
+			r.Images = registry.NewDigestResolver(registry.RegistryDigestLookup(registryClient))
+		}
+		if r.Signatures == nil {
+			r.Signatures = registry.NewSignatureVerifier(registryClient)
+		}
+	}
+
 	// Watch for changes to primary resource ClusterPolicy
-	err = c.Watch(source.Kind(mgr.GetCache(), &gpuv1.ClusterPolicy{}), &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	// Annotation changes are also watched to enable or disable the plan mode
+	err = c.Watch(source.Kind(mgr.GetCache(), &gpuv1.ClusterPolicy{}), &handler.EnqueueRequestForObject{},
+		predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))
 	if err != nil {
 		return err
 	}
 
+	// Watch for deletion of ClusterPolicy instances and requeue the remaining ones,
+	// so that a new ClusterPolicy is promoted when the active one is deleted
+	clusterPolicyDeletedMapFn := func(ctx context.Context, o client.Object) []reconcile.Request {
+		return getClusterPoliciesToReconcile(ctx, mgr.GetClient())
+	}
+	clusterPolicyDeletedPredicate := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+	err = c.Watch(source.Kind(mgr.GetCache(), &gpuv1.ClusterPolicy{}), handler.EnqueueRequestsFromMapFunc(clusterPolicyDeletedMapFn), clusterPolicyDeletedPredicate)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the overlay ConfigMap of the manifests, reload the states and
+	// requeue the ClusterPolicies
+	overlaySource, err := r.Assets.OverlaySource(mgr)
+	if err != nil {
+		return err
+	}
+	if overlaySource != nil {
+		overlayMapFn := func(ctx context.Context, o client.Object) []reconcile.Request {
+			r.invalidateStates()
+			return getClusterPoliciesToReconcile(ctx, mgr.GetClient())
+		}
+		err = c.Watch(overlaySource, handler.EnqueueRequestsFromMapFunc(overlayMapFn))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Watch for changes to Node labels and requeue the owner ClusterPolicy
 	err = addWatchNewGPUNode(ctx, r, c, mgr)
 	if err != nil {
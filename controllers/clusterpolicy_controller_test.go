@@ -0,0 +1,190 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	operatorassets "github.com/NVIDIA/gpu-operator/assets"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
+)
+
+func TestGetActiveClusterPolicy(t *testing.T) {
+	now := time.Now()
+	newClusterPolicy := func(name string, created time.Time, deleting bool) *gpuv1.ClusterPolicy {
+		cp := &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}
+		if deleting {
+			deletionTimestamp := metav1.NewTime(now)
+			cp.DeletionTimestamp = &deletionTimestamp
+			cp.Finalizers = []string{clusterPolicyFinalizer}
+		}
+		return cp
+	}
+
+	testCases := []struct {
+		description     string
+		clusterPolicies []*gpuv1.ClusterPolicy
+		expected        string
+	}{
+		{
+			description: "no cluster policies",
+			expected:    "",
+		},
+		{
+			description: "oldest cluster policy is active",
+			clusterPolicies: []*gpuv1.ClusterPolicy{
+				newClusterPolicy("b", now.Add(-time.Hour), false),
+				newClusterPolicy("a", now, false),
+			},
+			expected: "b",
+		},
+		{
+			description: "cluster policy being deleted is skipped",
+			clusterPolicies: []*gpuv1.ClusterPolicy{
+				newClusterPolicy("b", now.Add(-time.Hour), true),
+				newClusterPolicy("a", now, false),
+			},
+			expected: "a",
+		},
+		{
+			description: "same creation time is ordered by name",
+			clusterPolicies: []*gpuv1.ClusterPolicy{
+				newClusterPolicy("b", now, false),
+				newClusterPolicy("a", now, false),
+			},
+			expected: "a",
+		},
+		{
+			description: "all cluster policies being deleted",
+			clusterPolicies: []*gpuv1.ClusterPolicy{
+				newClusterPolicy("a", now, true),
+			},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			for _, cp := range tc.clusterPolicies {
+				builder = builder.WithObjects(cp)
+			}
+
+			active, err := getActiveClusterPolicy(context.Background(), builder.Build())
+			require.NoError(t, err)
+			if tc.expected == "" {
+				require.Nil(t, active)
+				return
+			}
+			require.NotNil(t, active)
+			require.Equal(t, tc.expected, active.Name)
+		})
+	}
+}
+
+func TestReconcileInactiveClusterPolicy(t *testing.T) {
+	active := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "active"}}
+	inactive := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "inactive"}}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(active, inactive).
+		WithStatusSubresource(&gpuv1.ClusterPolicy{}).
+		Build()
+
+	r := &ClusterPolicyReconciler{Client: c, Log: ctrl.Log.WithName("test")}
+	r.setActive(active)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: inactive.Name}})
+	require.NoError(t, err)
+
+	got := &gpuv1.ClusterPolicy{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: inactive.Name}, got))
+	require.Equal(t, gpuv1.Ignored, got.Status.State)
+	require.Empty(t, got.Finalizers)
+	require.Equal(t, active.Name, r.getActive().Name)
+}
+
+func TestReconcileDeletedActiveClusterPolicy(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	r := &ClusterPolicyReconciler{Client: c, Log: ctrl.Log.WithName("test")}
+	r.setActive(&gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "deleted"}})
+	r.setDriverToolkitEnabled(true)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "deleted"}})
+	require.NoError(t, err)
+	require.Nil(t, r.getActive())
+	require.False(t, r.DriverToolkitEnabled())
+	require.Nil(t, r.Metrics())
+	require.Empty(t, r.OperatorNamespace())
+}
+
+func TestLoadStatesReloadsOverlay(t *testing.T) {
+	dir := t.TempDir()
+	stateDir := filepath.Join(dir, "state-device-plugin")
+	require.NoError(t, os.MkdirAll(stateDir, 0o755))
+	writeDaemonSet := func(name string) {
+		manifest := fmt.Sprintf("apiVersion: apps/v1\nkind: DaemonSet\nmetadata:\n  name: %s\n", name)
+		require.NoError(t, os.WriteFile(filepath.Join(stateDir, "0500_daemonset.yaml"), []byte(manifest), 0o600))
+	}
+	writeDaemonSet("nvidia-device-plugin-overlay")
+
+	r := &ClusterPolicyReconciler{
+		Log:    ctrl.Log.WithName("test"),
+		Assets: assets.NewLoader(operatorassets.FS, "").WithOverlay(assets.Overlay{Dir: dir}, nil),
+	}
+	n := &ClusterPolicyController{rec: r}
+	require.NoError(t, n.addStates())
+	r.states = n
+
+	getDaemonSetName := func() string {
+		loaded, err := r.loadStates(context.Background())
+		require.NoError(t, err)
+		for i, name := range loaded.stateNames {
+			if name == "state-device-plugin" {
+				return loaded.resources[i].DaemonSet.Name
+			}
+		}
+		return ""
+	}
+	require.Equal(t, "nvidia-device-plugin-overlay", getDaemonSetName())
+
+	// the states are only reloaded once the overlay is invalidated
+	writeDaemonSet("nvidia-device-plugin-updated")
+	require.Equal(t, "nvidia-device-plugin-overlay", getDaemonSetName())
+	r.invalidateStates()
+	require.Equal(t, "nvidia-device-plugin-updated", getDaemonSetName())
+	require.Len(t, r.states.stateNames, len(clusterPolicyStates))
+}
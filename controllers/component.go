@@ -0,0 +1,114 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// componentSpec holds the fields of the component spec of an operand DaemonSet which are
+// applied on top of the rendered DaemonSet and of the common configuration of spec.daemonsets
+type componentSpec struct {
+	Tolerations       []corev1.Toleration
+	NodeAffinity      *corev1.NodeAffinity
+	PriorityClassName string
+	PodTemplatePatch  *gpuv1.PodTemplatePatch
+}
+
+// componentSpecs maps the operand DaemonSets to the component spec of the ClusterPolicy configuring
+// them. The MPS control daemon is configured by the device plugin spec, and the sandbox validator by
+// the validator spec, as they run the images of these components.
+var componentSpecs = map[string]func(spec *gpuv1.ClusterPolicySpec) *componentSpec{
+	"nvidia-driver-daemonset": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.Driver
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-vgpu-manager-daemonset": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.VGPUManager
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-vgpu-device-manager": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.VGPUDeviceManager
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-vfio-manager": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.VFIOManager
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-container-toolkit-daemonset": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.Toolkit
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-device-plugin-daemonset":          devicePluginComponentSpec,
+	"nvidia-device-plugin-mps-control-daemon": devicePluginComponentSpec,
+	"nvidia-sandbox-device-plugin-daemonset": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.SandboxDevicePlugin
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-dcgm": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.DCGM
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-dcgm-exporter": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.DCGMExporter
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-node-status-exporter": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.NodeStatusExporter
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"gpu-feature-discovery": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.GPUFeatureDiscovery
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-mig-manager": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.MIGManager
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-operator-validator": validatorComponentSpec,
+	"nvidia-sandbox-validator":  validatorComponentSpec,
+	"nvidia-kata-manager": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.KataManager
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+	"nvidia-cc-manager": func(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+		s := &spec.CCManager
+		return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+	},
+}
+
+func devicePluginComponentSpec(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+	s := &spec.DevicePlugin
+	return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+}
+
+func validatorComponentSpec(spec *gpuv1.ClusterPolicySpec) *componentSpec {
+	s := &spec.Validator
+	return &componentSpec{Tolerations: s.Tolerations, NodeAffinity: s.NodeAffinity, PriorityClassName: s.PriorityClassName, PodTemplatePatch: s.PodTemplatePatch}
+}
+
+// getComponentSpec returns the component spec of the given DaemonSet, or nil if the DaemonSet
+// is not configured by a component spec of the ClusterPolicy
+func getComponentSpec(spec *gpuv1.ClusterPolicySpec, dsName string) *componentSpec {
+	get, ok := componentSpecs[dsName]
+	if !ok {
+		return nil
+	}
+	return get(spec)
+}
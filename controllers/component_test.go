@@ -0,0 +1,154 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func TestApplyCommonDaemonsetConfigComponentScheduling(t *testing.T) {
+	commonToleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	componentToleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "monitoring", Effect: corev1.TaintEffectNoSchedule}
+	nodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "node-role", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"edge"}}},
+			}},
+		},
+	}
+	podAntiAffinity := &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "kubernetes.io/hostname"}},
+	}
+
+	newDaemonSet := func(name string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						PriorityClassName: "system-node-critical",
+						Affinity:          &corev1.Affinity{PodAntiAffinity: podAntiAffinity},
+					},
+				},
+			},
+		}
+	}
+
+	spec := &gpuv1.ClusterPolicySpec{
+		Daemonsets: gpuv1.DaemonsetsSpec{
+			PriorityClassName: "gpu-operands",
+			Tolerations:       []corev1.Toleration{commonToleration},
+		},
+		DCGMExporter: gpuv1.DCGMExporterSpec{
+			PriorityClassName: "low-priority",
+			Tolerations:       []corev1.Toleration{componentToleration},
+		},
+		NodeStatusExporter: gpuv1.NodeStatusExporterSpec{
+			NodeAffinity: nodeAffinity,
+		},
+		GPUFeatureDiscovery: gpuv1.GPUFeatureDiscoverySpec{
+			Tolerations: []corev1.Toleration{commonToleration},
+		},
+	}
+
+	testCases := []struct {
+		description         string
+		dsName              string
+		expectedPriority    string
+		expectedTolerations []corev1.Toleration
+		expectedAffinity    *corev1.Affinity
+	}{
+		{
+			description:         "common settings",
+			dsName:              "nvidia-driver-daemonset",
+			expectedPriority:    "gpu-operands",
+			expectedTolerations: []corev1.Toleration{commonToleration},
+			expectedAffinity:    &corev1.Affinity{PodAntiAffinity: podAntiAffinity},
+		},
+		{
+			description:         "component priority class replaces the common one and tolerations are added",
+			dsName:              "nvidia-dcgm-exporter",
+			expectedPriority:    "low-priority",
+			expectedTolerations: []corev1.Toleration{commonToleration, componentToleration},
+			expectedAffinity:    &corev1.Affinity{PodAntiAffinity: podAntiAffinity},
+		},
+		{
+			description:         "component tolerations already in the common ones",
+			dsName:              "gpu-feature-discovery",
+			expectedPriority:    "gpu-operands",
+			expectedTolerations: []corev1.Toleration{commonToleration},
+			expectedAffinity:    &corev1.Affinity{PodAntiAffinity: podAntiAffinity},
+		},
+		{
+			description:         "component node affinity replaces the rendered one",
+			dsName:              "nvidia-node-status-exporter",
+			expectedPriority:    "gpu-operands",
+			expectedTolerations: []corev1.Toleration{commonToleration},
+			expectedAffinity:    &corev1.Affinity{NodeAffinity: nodeAffinity, PodAntiAffinity: podAntiAffinity},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			ds := newDaemonSet(tc.dsName)
+			require.NoError(t, applyCommonDaemonsetConfig(ds, spec))
+			require.Equal(t, tc.expectedPriority, ds.Spec.Template.Spec.PriorityClassName)
+			require.Equal(t, tc.expectedTolerations, ds.Spec.Template.Spec.Tolerations)
+			require.Equal(t, tc.expectedAffinity, ds.Spec.Template.Spec.Affinity)
+		})
+	}
+
+	// excluding node pools from the DaemonSet must not modify the node affinity of the ClusterPolicy
+	ds := newDaemonSet("nvidia-node-status-exporter")
+	require.NoError(t, applyCommonDaemonsetConfig(ds, spec))
+	excludeNodePools(ds, []string{"a100"})
+	require.Len(t, ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 2)
+	require.Len(t, spec.NodeStatusExporter.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+
+	// adding the component tolerations must not modify the common tolerations of the ClusterPolicy
+	require.Equal(t, []corev1.Toleration{commonToleration}, spec.Daemonsets.Tolerations)
+}
+
+func TestGetComponentSpecAllDaemonSets(t *testing.T) {
+	files, err := filepath.Glob("../assets/*/*.yaml")
+	require.NoError(t, err)
+
+	daemonSets := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, yaml.Unmarshal(data, ds), file)
+		if ds.Kind != "DaemonSet" {
+			continue
+		}
+		daemonSets++
+		require.NotNil(t, getComponentSpec(&gpuv1.ClusterPolicySpec{}, ds.Name), "no component spec for DaemonSet %s in %s", ds.Name, file)
+	}
+	require.NotZero(t, daemonSets)
+}
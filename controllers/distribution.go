@@ -0,0 +1,154 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+const (
+	// microK8sNodeLabelKey is set by MicroK8s on the nodes of the cluster
+	microK8sNodeLabelKey = "microk8s.io/cluster"
+	// devicePluginVolumeName is the name of the device plugin directory volume of the device plugins
+	devicePluginVolumeName = "device-plugin"
+	// podResourcesVolumeName is the name of the pod-resources directory volume of dcgm-exporter
+	podResourcesVolumeName = "pod-gpu-resources"
+)
+
+// distributionProfile holds the host paths of the container runtime and of the kubelet for a Kubernetes distribution
+type distributionProfile struct {
+	// kubeletRootDir is the root directory of the kubelet, holding the pod-resources and device-plugins directories
+	kubeletRootDir string
+	// containerdConfigFile is the containerd config file updated by the container-toolkit
+	containerdConfigFile string
+	// containerdSocketFile is the socket the container-toolkit restarts containerd through
+	containerdSocketFile string
+}
+
+var distributionProfiles = map[gpuv1.Distribution]distributionProfile{
+	gpuv1.DistributionKubernetes: {
+		kubeletRootDir:       "/var/lib/kubelet",
+		containerdConfigFile: DefaultContainerdConfigFile,
+		containerdSocketFile: DefaultContainerdSocketFile,
+	},
+	gpuv1.DistributionK3s: {
+		kubeletRootDir:       "/var/lib/kubelet",
+		containerdConfigFile: "/var/lib/rancher/k3s/agent/etc/containerd/config.toml.tmpl",
+		containerdSocketFile: "/run/k3s/containerd/containerd.sock",
+	},
+	gpuv1.DistributionRKE2: {
+		kubeletRootDir:       "/var/lib/kubelet",
+		containerdConfigFile: "/var/lib/rancher/rke2/agent/etc/containerd/config.toml.tmpl",
+		containerdSocketFile: "/run/k3s/containerd/containerd.sock",
+	},
+	gpuv1.DistributionK0s: {
+		kubeletRootDir:       "/var/lib/k0s/kubelet",
+		containerdConfigFile: "/etc/k0s/containerd.d/nvidia.toml",
+		containerdSocketFile: "/run/k0s/containerd.sock",
+	},
+	gpuv1.DistributionMicroK8s: {
+		kubeletRootDir:       "/var/snap/microk8s/common/var/lib/kubelet",
+		containerdConfigFile: "/var/snap/microk8s/current/args/containerd-template.toml",
+		containerdSocketFile: "/var/snap/microk8s/common/run/containerd.sock",
+	},
+}
+
+// getDistributionProfile returns the profile of the given distribution, the upstream Kubernetes one by default
+func getDistributionProfile(distribution gpuv1.Distribution) distributionProfile {
+	if profile, ok := distributionProfiles[distribution]; ok {
+		return profile
+	}
+	return distributionProfiles[gpuv1.DistributionKubernetes]
+}
+
+// podResourcesDir returns the host directory of the kubelet pod-resources socket
+func (p distributionProfile) podResourcesDir() string {
+	return path.Join(p.kubeletRootDir, "pod-resources")
+}
+
+// devicePluginDir returns the host directory of the kubelet device plugin sockets
+func (p distributionProfile) devicePluginDir() string {
+	return path.Join(p.kubeletRootDir, "device-plugins")
+}
+
+// getNodeDistribution returns the Kubernetes distribution of a node from its kubelet version and labels
+func getNodeDistribution(node corev1.Node) gpuv1.Distribution {
+	// KubeletVersion string will look like v<x.y.z>+<distribution><n> on k3s, RKE2 and k0s
+	kubeletVersion := node.Status.NodeInfo.KubeletVersion
+	switch {
+	case strings.Contains(kubeletVersion, "+k3s"):
+		return gpuv1.DistributionK3s
+	case strings.Contains(kubeletVersion, "+rke2"):
+		return gpuv1.DistributionRKE2
+	case strings.Contains(kubeletVersion, "+k0s"):
+		return gpuv1.DistributionK0s
+	case node.Labels[microK8sNodeLabelKey] == "true":
+		return gpuv1.DistributionMicroK8s
+	}
+	return gpuv1.DistributionKubernetes
+}
+
+// getDistribution sets clusterPolicyController.distribution to the distribution of the
+// ClusterPolicy or, with auto, to the distribution detected on the GPU nodes. Clusters whose
+// GPU nodes report different distributions, as well as OpenShift, use the kubernetes profile.
+func (n *ClusterPolicyController) getDistribution() error {
+	n.distribution = n.singleton.Spec.Operator.GetDistribution()
+	if n.distribution != gpuv1.DistributionAuto {
+		return nil
+	}
+
+	n.distribution = gpuv1.DistributionKubernetes
+	if n.openshift != "" {
+		return nil
+	}
+
+	list := &corev1.NodeList{}
+	err := n.rec.Client.List(n.ctx, list, client.MatchingLabels{commonGPULabelKey: "true"})
+	if err != nil {
+		return fmt.Errorf("unable to list nodes prior to detecting the Kubernetes distribution: %w", err)
+	}
+
+	detected := map[gpuv1.Distribution]bool{}
+	for _, node := range list.Items {
+		detected[getNodeDistribution(node)] = true
+	}
+	if len(detected) > 1 {
+		n.rec.Log.Info("GPU nodes run different Kubernetes distributions, defaulting to kubernetes", "distributions", detected)
+		return nil
+	}
+	for distribution := range detected {
+		n.distribution = distribution
+	}
+	return nil
+}
+
+// setHostPathVolume sets the host path of the hostPath volume name of the pod
+func setHostPathVolume(podSpec *corev1.PodSpec, name string, hostPath string) {
+	for i, volume := range podSpec.Volumes {
+		if volume.Name == name && volume.HostPath != nil {
+			podSpec.Volumes[i].HostPath.Path = hostPath
+			return
+		}
+	}
+}
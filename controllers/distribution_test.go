@@ -0,0 +1,167 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func newDistributionTestNode(name string, kubeletVersion string, labels map[string]string) *corev1.Node {
+	nodeLabels := map[string]string{commonGPULabelKey: "true"}
+	for k, v := range labels {
+		nodeLabels[k] = v
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{KubeletVersion: kubeletVersion},
+		},
+	}
+}
+
+func TestGetNodeDistribution(t *testing.T) {
+	testCases := []struct {
+		kubeletVersion string
+		labels         map[string]string
+		distribution   gpuv1.Distribution
+	}{
+		{kubeletVersion: "v1.29.2", distribution: gpuv1.DistributionKubernetes},
+		{kubeletVersion: "v1.29.2+k3s1", distribution: gpuv1.DistributionK3s},
+		{kubeletVersion: "v1.29.2+rke2r1", distribution: gpuv1.DistributionRKE2},
+		{kubeletVersion: "v1.29.2+k0s", distribution: gpuv1.DistributionK0s},
+		{kubeletVersion: "v1.29.2", labels: map[string]string{microK8sNodeLabelKey: "true"}, distribution: gpuv1.DistributionMicroK8s},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.distribution), func(t *testing.T) {
+			node := newDistributionTestNode("node", tc.kubeletVersion, tc.labels)
+			require.Equal(t, tc.distribution, getNodeDistribution(*node))
+		})
+	}
+}
+
+func TestGetDistribution(t *testing.T) {
+	testCases := []struct {
+		description  string
+		distribution gpuv1.Distribution
+		openshift    string
+		nodes        []*corev1.Node
+		expected     gpuv1.Distribution
+	}{
+		{
+			description: "detected from the GPU nodes",
+			nodes: []*corev1.Node{
+				newDistributionTestNode("node1", "v1.29.2+k3s1", nil),
+				newDistributionTestNode("node2", "v1.29.2+k3s1", nil),
+			},
+			expected: gpuv1.DistributionK3s,
+		},
+		{
+			description: "different distributions default to kubernetes",
+			nodes: []*corev1.Node{
+				newDistributionTestNode("node1", "v1.29.2+k3s1", nil),
+				newDistributionTestNode("node2", "v1.29.2+k0s", nil),
+			},
+			expected: gpuv1.DistributionKubernetes,
+		},
+		{
+			description: "nodes without GPUs are ignored",
+			nodes: []*corev1.Node{
+				newDistributionTestNode("node1", "v1.29.2+rke2r1", nil),
+				newDistributionTestNode("node2", "v1.29.2+k0s", map[string]string{commonGPULabelKey: "false"}),
+			},
+			expected: gpuv1.DistributionRKE2,
+		},
+		{
+			description:  "set in the ClusterPolicy",
+			distribution: gpuv1.DistributionMicroK8s,
+			nodes:        []*corev1.Node{newDistributionTestNode("node1", "v1.29.2+k3s1", nil)},
+			expected:     gpuv1.DistributionMicroK8s,
+		},
+		{
+			description: "kubernetes on openshift",
+			openshift:   "4.14",
+			nodes:       []*corev1.Node{newDistributionTestNode("node1", "v1.29.2+k3s1", nil)},
+			expected:    gpuv1.DistributionKubernetes,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			objs := make([]client.Object, 0, len(tc.nodes))
+			for _, node := range tc.nodes {
+				objs = append(objs, node)
+			}
+			n := ClusterPolicyController{
+				ctx:       context.Background(),
+				openshift: tc.openshift,
+				singleton: &gpuv1.ClusterPolicy{Spec: gpuv1.ClusterPolicySpec{Operator: gpuv1.OperatorSpec{Distribution: tc.distribution}}},
+				rec: &ClusterPolicyReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+					Log:    ctrl.Log.WithName("test"),
+				},
+			}
+			require.NoError(t, n.getDistribution())
+			require.Equal(t, tc.expected, n.distribution)
+		})
+	}
+}
+
+func TestDistributionProfilePaths(t *testing.T) {
+	profile := getDistributionProfile(gpuv1.DistributionMicroK8s)
+	require.Equal(t, "/var/snap/microk8s/common/var/lib/kubelet/pod-resources", profile.podResourcesDir())
+	require.Equal(t, "/var/snap/microk8s/common/var/lib/kubelet/device-plugins", profile.devicePluginDir())
+
+	// auto is resolved before the profile is looked up, fall back to upstream Kubernetes
+	require.Equal(t, distributionProfiles[gpuv1.DistributionKubernetes], getDistributionProfile(gpuv1.DistributionAuto))
+
+	container := &corev1.Container{}
+	configFile, err := getRuntimeConfigFile(container, gpuv1.Containerd.String(), getDistributionProfile(gpuv1.DistributionK3s))
+	require.NoError(t, err)
+	require.Equal(t, "/var/lib/rancher/k3s/agent/etc/containerd/config.toml.tmpl", configFile)
+	socketFile, err := getRuntimeSocketFile(container, gpuv1.Containerd.String(), getDistributionProfile(gpuv1.DistributionK3s))
+	require.NoError(t, err)
+	require.Equal(t, "/run/k3s/containerd/containerd.sock", socketFile)
+
+	// the paths set in the toolkit env override the profile
+	container.Env = []corev1.EnvVar{{Name: "CONTAINERD_SOCKET", Value: "/run/custom/containerd.sock"}}
+	socketFile, err = getRuntimeSocketFile(container, gpuv1.Containerd.String(), getDistributionProfile(gpuv1.DistributionK3s))
+	require.NoError(t, err)
+	require.Equal(t, "/run/custom/containerd.sock", socketFile)
+
+	podSpec := &corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: podResourcesVolumeName, VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/kubelet/pod-resources"}}},
+		},
+	}
+	setHostPathVolume(podSpec, podResourcesVolumeName, profile.podResourcesDir())
+	require.Equal(t, profile.podResourcesDir(), podSpec.Volumes[0].HostPath.Path)
+	// pods without the volume are left unchanged
+	setHostPathVolume(podSpec, devicePluginVolumeName, profile.devicePluginDir())
+	require.Len(t, podSpec.Volumes, 1)
+}
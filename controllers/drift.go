@@ -0,0 +1,57 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/apply"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+// applyObject server-side applies an operand object of the ClusterPolicy, the objects
+// modified outside of the operator are reported and restored according to the drift policy
+func (n ClusterPolicyController) applyObject(ctx context.Context, obj client.Object) (apply.Result, error) {
+	handler := func(obj client.Object, kind string, fields []string) bool {
+		policy := n.singleton.Spec.Operator.GetDriftPolicy()
+		n.rec.Log.Info("Operand was modified outside of the operator", "Kind", kind, "Name", obj.GetName(),
+			"Fields", fields, "DriftPolicy", policy)
+		n.rec.recordEvent(n.singleton, corev1.EventTypeWarning, consts.OperandDriftReason,
+			"%s %s was modified outside of the operator (%s), drift policy is %s",
+			kind, client.ObjectKeyFromObject(obj), apply.FormatDrift(fields), policy)
+		return policy == gpuv1.DriftPolicyEnforce
+	}
+	return apply.Apply(apply.WithDriftHandler(ctx, handler), n.rec.Client, obj)
+}
+
+// withDriftHandler returns a context in which the driver objects of the NVIDIADriver modified
+// outside of the operator are reported and restored according to the drift policy of the ClusterPolicy
+func (r *NVIDIADriverReconciler) withDriftHandler(ctx context.Context, instance *nvidiav1alpha1.NVIDIADriver, policy gpuv1.DriftPolicy) context.Context {
+	return apply.WithDriftHandler(ctx, func(obj client.Object, kind string, fields []string) bool {
+		if r.Recorder != nil {
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, consts.OperandDriftReason,
+				"%s %s was modified outside of the operator (%s), drift policy is %s",
+				kind, client.ObjectKeyFromObject(obj), apply.FormatDrift(fields), policy)
+		}
+		return policy == gpuv1.DriftPolicyEnforce
+	})
+}
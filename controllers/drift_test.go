@@ -0,0 +1,197 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+// newTestDriftDaemonSet returns the rendered DaemonSet nvidia-test-daemonset running image
+func newTestDriftDaemonSet(namespace string, image string) *appsv1.DaemonSet {
+	labels := map[string]string{"app": "nvidia-test-daemonset"}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-test-daemonset", Namespace: namespace},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "test", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func TestCreateOrUpdateDaemonSetDrift(t *testing.T) {
+	namespace := "test-operator"
+	newDaemonSet := func(image string) *appsv1.DaemonSet {
+		return newTestDriftDaemonSet(namespace, image)
+	}
+
+	testCases := []struct {
+		description   string
+		policy        gpuv1.DriftPolicy
+		expectedImage string
+	}{
+		{
+			description:   "default policy restores the rendered spec",
+			expectedImage: "nvcr.io/nvidia/test:v1",
+		},
+		{
+			description:   "enforce policy restores the rendered spec",
+			policy:        gpuv1.DriftPolicyEnforce,
+			expectedImage: "nvcr.io/nvidia/test:v1",
+		},
+		{
+			description:   "warn policy leaves the modified DaemonSet",
+			policy:        gpuv1.DriftPolicyWarn,
+			expectedImage: "example.com/test:v2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			ctx := context.Background()
+			cp := &gpuv1.ClusterPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "cp-uid"},
+				Spec:       gpuv1.ClusterPolicySpec{Operator: gpuv1.OperatorSpec{DriftPolicy: tc.policy}},
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithInterceptorFuncs(interceptor.Funcs{Patch: fakeApplyPatch}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			n := ClusterPolicyController{
+				ctx:               ctx,
+				singleton:         cp,
+				operatorNamespace: namespace,
+				stateNames:        []string{"state-test"},
+				rec: &ClusterPolicyReconciler{
+					Client:   c,
+					Log:      ctrl.Log.WithName("test"),
+					Scheme:   scheme.Scheme,
+					Recorder: recorder,
+				},
+			}
+
+			_, err := createOrUpdateDaemonSet(newDaemonSet("nvcr.io/nvidia/test:v1"), n, nil)
+			require.NoError(t, err)
+			require.Len(t, recorder.Events, 1)
+			require.Contains(t, <-recorder.Events, consts.DaemonSetCreatedReason)
+
+			// the DaemonSet is edited outside of the operator, keeping its hash annotation
+			live := &appsv1.DaemonSet{}
+			key := client.ObjectKey{Namespace: namespace, Name: "nvidia-test-daemonset"}
+			require.NoError(t, c.Get(ctx, key, live))
+			live.Spec.Template.Spec.Containers[0].Image = "example.com/test:v2"
+			require.NoError(t, c.Update(ctx, live))
+
+			_, err = createOrUpdateDaemonSet(newDaemonSet("nvcr.io/nvidia/test:v1"), n, nil)
+			require.NoError(t, err)
+			require.Len(t, recorder.Events, 1)
+			event := <-recorder.Events
+			require.Contains(t, event, consts.OperandDriftReason)
+			require.Contains(t, event, "spec.template.spec.containers[name=test].image")
+
+			require.NoError(t, c.Get(ctx, key, live))
+			require.Equal(t, tc.expectedImage, live.Spec.Template.Spec.Containers[0].Image)
+
+			// an update of the rendered spec is applied regardless of the drift
+			_, err = createOrUpdateDaemonSet(newDaemonSet("nvcr.io/nvidia/test:v3"), n, nil)
+			require.NoError(t, err)
+			require.Len(t, recorder.Events, 1)
+			require.Contains(t, <-recorder.Events, consts.DaemonSetUpdatedReason)
+			require.NoError(t, c.Get(ctx, key, live))
+			require.Equal(t, "nvcr.io/nvidia/test:v3", live.Spec.Template.Spec.Containers[0].Image)
+		})
+	}
+}
+
+// TestCreateOrUpdateDaemonSetNotPatched tests that no update event is recorded for a DaemonSet
+// which is left as is because of a conflict with another field manager
+func TestCreateOrUpdateDaemonSetNotPatched(t *testing.T) {
+	namespace := "test-operator"
+	ctx := context.Background()
+	conflict := false
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if conflict && patch.Type() == types.ApplyPatchType {
+					return apierrors.NewApplyConflict([]metav1.StatusCause{{
+						Type:    metav1.CauseTypeFieldManagerConflict,
+						Message: `conflict with "kubectl-edit"`,
+						Field:   ".spec.template.spec.containers[name=\"test\"].image",
+					}}, "Apply failed with 1 conflict")
+				}
+				return fakeApplyPatch(ctx, c, obj, patch, opts...)
+			},
+		}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	n := ClusterPolicyController{
+		ctx: ctx,
+		singleton: &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "cp-uid"},
+		},
+		operatorNamespace: namespace,
+		stateNames:        []string{"state-test"},
+		rec: &ClusterPolicyReconciler{
+			Client:   c,
+			Log:      ctrl.Log.WithName("test"),
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		},
+	}
+
+	_, err := createOrUpdateDaemonSet(newTestDriftDaemonSet(namespace, "nvcr.io/nvidia/test:v1"), n, nil)
+	require.NoError(t, err)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, consts.DaemonSetCreatedReason)
+
+	// the updated DaemonSet is not patched on every reconcile while the conflict lasts
+	conflict = true
+	for i := 0; i < 2; i++ {
+		_, err = createOrUpdateDaemonSet(newTestDriftDaemonSet(namespace, "nvcr.io/nvidia/test:v2"), n, nil)
+		require.NoError(t, err)
+		require.Empty(t, recorder.Events)
+	}
+
+	conflict = false
+	_, err = createOrUpdateDaemonSet(newTestDriftDaemonSet(namespace, "nvcr.io/nvidia/test:v2"), n, nil)
+	require.NoError(t, err)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, consts.DaemonSetUpdatedReason)
+}
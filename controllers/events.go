@@ -0,0 +1,84 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+const (
+	// stateStatusError is the status of a state which failed to sync in the state transition Events
+	stateStatusError = "error"
+)
+
+// recordEvent records an Event on obj, if the reconciler has an event recorder
+func (r *ClusterPolicyReconciler) recordEvent(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// recordStateTransition records an Event on the ClusterPolicy when the status of a state changed
+// since the last reconciliation. The last known statuses are initialized from the component
+// status of the ClusterPolicy, so that a restart of the operator does not record them again.
+func (r *ClusterPolicyReconciler) recordStateTransition(cp *gpuv1.ClusterPolicy, state string, status string) {
+	r.mu.Lock()
+	if r.stateStatuses == nil {
+		r.stateStatuses = make(map[string]string)
+		for _, c := range cp.Status.Components {
+			r.stateStatuses[c.Name] = string(c.State)
+		}
+	}
+	previous, found := r.stateStatuses[state]
+	r.stateStatuses[state] = status
+	r.mu.Unlock()
+
+	if found && previous == status {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	if status == stateStatusError {
+		eventType = corev1.EventTypeWarning
+	}
+	message := fmt.Sprintf("State %s is %s", state, status)
+	if found {
+		message = fmt.Sprintf("State %s transitioned from %s to %s", state, previous, status)
+	}
+	r.recordEvent(cp, eventType, consts.StateTransitionReason, message)
+}
+
+// recordDaemonSetEvent records an Event on the ClusterPolicy for a DaemonSet created, updated or deleted by the operator
+func (n ClusterPolicyController) recordDaemonSetEvent(ds *appsv1.DaemonSet, reason string) {
+	var action string
+	switch reason {
+	case consts.DaemonSetCreatedReason:
+		action = "created"
+	case consts.DaemonSetUpdatedReason:
+		action = "updated"
+	case consts.DaemonSetDeletedReason:
+		action = "deleted"
+	}
+	n.rec.recordEvent(n.singleton, corev1.EventTypeNormal, reason, "DaemonSet %s/%s %s", ds.Namespace, ds.Name, action)
+}
@@ -0,0 +1,61 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func TestRecordStateTransition(t *testing.T) {
+	cp := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "cp-uid"},
+		Status: gpuv1.ClusterPolicyStatus{
+			Components: []gpuv1.ComponentStatus{{Name: "state-driver", State: gpuv1.Ready}},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &ClusterPolicyReconciler{Recorder: recorder}
+	r.setActive(cp)
+
+	// the last known status is initialized from the component status
+	r.recordStateTransition(cp, "state-driver", string(gpuv1.Ready))
+	require.Empty(t, recorder.Events)
+
+	r.recordStateTransition(cp, "state-driver", string(gpuv1.NotReady))
+	require.Equal(t, "Normal StateTransition State state-driver transitioned from ready to notReady", <-recorder.Events)
+
+	r.recordStateTransition(cp, "state-driver", stateStatusError)
+	require.Equal(t, "Warning StateTransition State state-driver transitioned from notReady to error", <-recorder.Events)
+
+	r.recordStateTransition(cp, "state-dcgm", string(gpuv1.Disabled))
+	require.Equal(t, "Normal StateTransition State state-dcgm is disabled", <-recorder.Events)
+
+	r.recordStateTransition(cp, "state-dcgm", string(gpuv1.Disabled))
+	require.Empty(t, recorder.Events)
+
+	// the transitions of another ClusterPolicy are recorded from its own component status
+	other := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "other-uid"}}
+	r.setActive(other)
+	r.recordStateTransition(other, "state-driver", string(gpuv1.NotReady))
+	require.Equal(t, "Normal StateTransition State state-driver is notReady", <-recorder.Events)
+}
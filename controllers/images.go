@@ -0,0 +1,118 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/conditions"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
+)
+
+// withRegistryCredentials returns a context whose registry requests authenticate with the
+// image pull secrets of the namespace, read with reader
+func withRegistryCredentials(ctx context.Context, reader client.Reader, namespace string, pullSecrets []corev1.LocalObjectReference) (context.Context, error) {
+	hosts, err := registry.LoadCredentials(ctx, reader, namespace, pullSecrets)
+	if err != nil {
+		return ctx, err
+	}
+	return registry.WithCredentials(ctx, hosts), nil
+}
+
+// pinDaemonSetImages pins the images of the DaemonSet to the digests of their tags,
+// if operator.pinImageDigests is enabled in the ClusterPolicy
+func pinDaemonSetImages(ctx context.Context, n ClusterPolicyController, obj *appsv1.DaemonSet) error {
+	if !n.singleton.Spec.Operator.IsPinImageDigestsEnabled() {
+		return nil
+	}
+	if n.rec.Images == nil {
+		return fmt.Errorf("no image digest resolver configured")
+	}
+	if err := n.rec.Images.PinPodSpec(ctx, &obj.Spec.Template.Spec); err != nil {
+		return fmt.Errorf("failed to pin the images of DaemonSet %s: %w", obj.Name, err)
+	}
+	return nil
+}
+
+// verifyDaemonSetImages verifies the signatures of the images of the DaemonSet with the policy
+// of the Secret referenced by operator.imageVerification in the ClusterPolicy, if set.
+// Verification failures are returned as *registry.VerificationError.
+func verifyDaemonSetImages(ctx context.Context, n ClusterPolicyController, obj *appsv1.DaemonSet) error {
+	spec := n.singleton.Spec.Operator.ImageVerification
+	if !n.singleton.Spec.Operator.IsImageVerificationEnabled() {
+		return nil
+	}
+	if n.rec.Images == nil || n.rec.Signatures == nil {
+		return fmt.Errorf("no image signature verifier configured")
+	}
+	policy, err := registry.LoadPolicy(ctx, n.rec.secretReader(), n.operatorNamespace, spec.SecretName)
+	if err != nil {
+		return &registry.VerificationError{Err: err}
+	}
+	verifier := &registry.PolicyVerifier{
+		Policy:     policy,
+		Digests:    n.rec.Images,
+		Signatures: n.rec.Signatures,
+	}
+	if err := verifier.VerifyPodSpec(ctx, &obj.Spec.Template.Spec); err != nil {
+		return fmt.Errorf("refusing to roll out DaemonSet %s: %w", obj.Name, err)
+	}
+	return nil
+}
+
+// getImagesVerifiedCondition returns the ImagesVerified condition of a component given the
+// result of its step, or the previous condition if the images of the component were not verified
+func getImagesVerifiedCondition(previous *gpuv1.ComponentStatus, err error, skipped bool) *metav1.Condition {
+	if err != nil && registry.IsVerificationError(err) {
+		cond := conditions.ImagesVerifiedCondition(err)
+		return &cond
+	}
+	if err == nil && !skipped {
+		cond := conditions.ImagesVerifiedCondition(nil)
+		return &cond
+	}
+	if previous == nil {
+		return nil
+	}
+	return meta.FindStatusCondition(previous.Conditions, conditions.ImagesVerified)
+}
+
+// getPinnedImages returns the sorted list of the images pinned to a digest in the DaemonSets
+func getPinnedImages(daemonsets ...*appsv1.DaemonSet) []string {
+	seen := map[string]bool{}
+	var images []string
+	for _, ds := range daemonsets {
+		for _, img := range registry.PinnedImages(&ds.Spec.Template.Spec) {
+			if seen[img] {
+				continue
+			}
+			seen[img] = true
+			images = append(images, img)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
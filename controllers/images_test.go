@@ -0,0 +1,241 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/regclient/regclient"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/conditions"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
+)
+
+const testImageDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestPinDaemonSetImages(t *testing.T) {
+	newDaemonSet := func() *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{{Name: "toolkit-validation", Image: "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0"}},
+						Containers:     []corev1.Container{{Name: "nvidia-device-plugin", Image: "nvcr.io/nvidia/k8s-device-plugin:v0.15.0"}},
+					},
+				},
+			},
+		}
+	}
+
+	lookups := 0
+	resolver := registry.NewDigestResolver(func(_ context.Context, _ string) (string, error) {
+		lookups++
+		return testImageDigest, nil
+	})
+
+	testCases := []struct {
+		description  string
+		pin          *bool
+		verification *gpuv1.ImageVerificationSpec
+		expected     []string
+	}{
+		{
+			description: "pinning disabled by default",
+			expected: []string{
+				"nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0",
+				"nvcr.io/nvidia/k8s-device-plugin:v0.15.0",
+			},
+		},
+		{
+			description: "pinning enabled",
+			pin:         boolTrue,
+			expected: []string{
+				"nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0@" + testImageDigest,
+				"nvcr.io/nvidia/k8s-device-plugin:v0.15.0@" + testImageDigest,
+			},
+		},
+		{
+			description:  "pinning forced by the image verification",
+			pin:          boolFalse,
+			verification: &gpuv1.ImageVerificationSpec{SecretName: "image-verification-policy"},
+			expected: []string{
+				"nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0@" + testImageDigest,
+				"nvcr.io/nvidia/k8s-device-plugin:v0.15.0@" + testImageDigest,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			n := ClusterPolicyController{
+				ctx:       context.TODO(),
+				rec:       &ClusterPolicyReconciler{Images: resolver},
+				singleton: &gpuv1.ClusterPolicy{Spec: gpuv1.ClusterPolicySpec{Operator: gpuv1.OperatorSpec{PinImageDigests: tc.pin, ImageVerification: tc.verification}}},
+			}
+			ds := newDaemonSet()
+			require.NoError(t, pinDaemonSetImages(context.TODO(), n, ds))
+			require.Equal(t, tc.expected, []string{ds.Spec.Template.Spec.InitContainers[0].Image, ds.Spec.Template.Spec.Containers[0].Image})
+
+			// pinning is stable, so is the hash of the DaemonSet
+			again := newDaemonSet()
+			require.NoError(t, pinDaemonSetImages(context.TODO(), n, again))
+			require.Equal(t, getDaemonsetHash(ds), getDaemonsetHash(again))
+		})
+	}
+	// each tag was resolved once
+	require.Equal(t, 2, lookups)
+}
+
+func TestGetPinnedImages(t *testing.T) {
+	newDaemonSet := func(images ...string) *appsv1.DaemonSet {
+		ds := &appsv1.DaemonSet{}
+		for _, img := range images {
+			ds.Spec.Template.Spec.Containers = append(ds.Spec.Template.Spec.Containers, corev1.Container{Image: img})
+		}
+		return ds
+	}
+
+	driver := "nvcr.io/nvidia/driver:550.54.15-ubuntu22.04@" + testImageDigest
+	manager := "nvcr.io/nvidia/cloud-native/k8s-driver-manager:v0.6.8@" + testImageDigest
+	images := getPinnedImages(
+		newDaemonSet(manager, driver),
+		newDaemonSet(manager, "nvcr.io/nvidia/driver:550.54.15-rhcos4.14"),
+	)
+	require.Equal(t, []string{manager, driver}, images)
+}
+
+func TestVerifyDaemonSetImages(t *testing.T) {
+	newDaemonSet := func() *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "nvidia-device-plugin", Image: "registry.invalid/nvidia/k8s-device-plugin:v0.15.0@" + testImageDigest}},
+					},
+				},
+			},
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	policySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "image-policy", Namespace: "test-operator"},
+		Data: map[string][]byte{
+			registry.PolicyKeysKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+		},
+	}
+	invalidSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid-policy", Namespace: "test-operator"},
+	}
+
+	registryClient := registry.NewRegistryClient(regclient.WithRetryLimit(1))
+	testCases := []struct {
+		description  string
+		verification *gpuv1.ImageVerificationSpec
+		errorMsg     string
+		policyError  bool
+	}{
+		{
+			description: "verification disabled by default",
+		},
+		{
+			description:  "missing policy Secret",
+			verification: &gpuv1.ImageVerificationSpec{SecretName: "missing"},
+			errorMsg:     "failed to get image verification Secret test-operator/missing",
+			policyError:  true,
+		},
+		{
+			description:  "invalid policy",
+			verification: &gpuv1.ImageVerificationSpec{SecretName: "invalid-policy"},
+			errorMsg:     "invalid image verification Secret test-operator/invalid-policy",
+			policyError:  true,
+		},
+		{
+			description:  "signature not found",
+			verification: &gpuv1.ImageVerificationSpec{SecretName: "image-policy"},
+			errorMsg:     "refusing to roll out DaemonSet nvidia-device-plugin-daemonset",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			n := ClusterPolicyController{
+				ctx: context.TODO(),
+				rec: &ClusterPolicyReconciler{
+					Client:     fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(policySecret, invalidSecret).Build(),
+					Images:     registry.NewDigestResolver(registry.RegistryDigestLookup(registryClient)),
+					Signatures: registry.NewSignatureVerifier(registryClient),
+				},
+				operatorNamespace: "test-operator",
+				singleton:         &gpuv1.ClusterPolicy{Spec: gpuv1.ClusterPolicySpec{Operator: gpuv1.OperatorSpec{ImageVerification: tc.verification}}},
+			}
+			err := verifyDaemonSetImages(context.TODO(), n, newDaemonSet())
+			if tc.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.errorMsg)
+			var verificationErr *registry.VerificationError
+			require.ErrorAs(t, err, &verificationErr)
+			require.Equal(t, tc.policyError, verificationErr.Image == "")
+
+			cond := getImagesVerifiedCondition(nil, err, false)
+			require.Equal(t, metav1.ConditionFalse, cond.Status)
+			require.Equal(t, conditions.ImageVerificationFailed, cond.Reason)
+		})
+	}
+}
+
+func TestGetImagesVerifiedCondition(t *testing.T) {
+	previous := &gpuv1.ComponentStatus{
+		Name:       "state-device-plugin",
+		Conditions: []metav1.Condition{conditions.ImagesVerifiedCondition(nil)},
+	}
+
+	cond := getImagesVerifiedCondition(previous, nil, false)
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+
+	cond = getImagesVerifiedCondition(nil, &registry.VerificationError{Image: "nvcr.io/nvidia/k8s-device-plugin:v0.15.0", Err: fmt.Errorf("no signature")}, false)
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Contains(t, cond.Message, "no signature")
+
+	// the previous condition is kept if the images were not verified
+	cond = getImagesVerifiedCondition(previous, fmt.Errorf("failed to create DaemonSet"), false)
+	require.Equal(t, &previous.Conditions[0], cond)
+	cond = getImagesVerifiedCondition(previous, nil, true)
+	require.Equal(t, &previous.Conditions[0], cond)
+	require.Nil(t, getImagesVerifiedCondition(nil, nil, true))
+}
@@ -0,0 +1,279 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	promcli "github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
+)
+
+const (
+	// nodePoolLabelKey is set by the operator on the GPU nodes selected by one of the
+	// nodePoolOverrides of the ClusterPolicy, and on the DaemonSets deployed to the node pool
+	nodePoolLabelKey = "nvidia.com/gpu-operator.node-pool"
+)
+
+// nodePoolDaemonSets maps the DaemonSets which can be deployed per node pool to the
+// function returning whether their configuration is overridden by a node pool
+var nodePoolDaemonSets = map[string]func(o *gpuv1.NodePoolOverride) bool{
+	"nvidia-device-plugin-daemonset": func(o *gpuv1.NodePoolOverride) bool {
+		return o.DevicePlugin != nil || o.MIG != nil
+	},
+	"nvidia-device-plugin-mps-control-daemon": func(o *gpuv1.NodePoolOverride) bool {
+		return o.DevicePlugin != nil || o.MIG != nil
+	},
+	"gpu-feature-discovery": func(o *gpuv1.NodePoolOverride) bool {
+		return o.GPUFeatureDiscovery != nil || o.MIG != nil
+	},
+	"nvidia-dcgm-exporter": func(o *gpuv1.NodePoolOverride) bool {
+		return o.DCGMExporter != nil
+	},
+	"nvidia-mig-manager": func(o *gpuv1.NodePoolOverride) bool {
+		return o.MIGManager != nil
+	},
+}
+
+// getNodePoolOverrides returns the node pools overriding the configuration of the given DaemonSet
+func getNodePoolOverrides(spec *gpuv1.ClusterPolicySpec, dsName string) []gpuv1.NodePoolOverride {
+	isOverridden, ok := nodePoolDaemonSets[dsName]
+	if !ok {
+		return nil
+	}
+	overrides := []gpuv1.NodePoolOverride{}
+	for i := range spec.NodePoolOverrides {
+		if isOverridden(&spec.NodePoolOverrides[i]) {
+			overrides = append(overrides, spec.NodePoolOverrides[i])
+		}
+	}
+	return overrides
+}
+
+// getNodePool returns the name of the node pool selecting a node with the given labels,
+// or an empty string if the node does not belong to any node pool
+func getNodePool(spec *gpuv1.ClusterPolicySpec, nodeLabels map[string]string) string {
+	for _, o := range spec.NodePoolOverrides {
+		if labels.SelectorFromSet(o.NodeSelector).Matches(labels.Set(nodeLabels)) {
+			return o.Name
+		}
+	}
+	return ""
+}
+
+// updateNodePoolLabel sets the node pool label of a node to the given node pool,
+// and returns true if the labels were changed
+func updateNodePoolLabel(nodeLabels map[string]string, pool string) bool {
+	return updateNodeLabel(nodeLabels, nodePoolLabelKey, pool)
+}
+
+// updateNodeLabel sets the label key of a node to the given value, or removes it if the
+// value is empty, and returns true if the labels were changed
+func updateNodeLabel(nodeLabels map[string]string, key string, value string) bool {
+	current, ok := nodeLabels[key]
+	if value == "" {
+		if !ok {
+			return false
+		}
+		delete(nodeLabels, key)
+		return true
+	}
+	if current == value {
+		return false
+	}
+	nodeLabels[key] = value
+	return true
+}
+
+// isNodePoolLabelOutdated returns true if the node pool label of a GPU node does not
+// match the node pool selecting the node in the active ClusterPolicy cp
+func isNodePoolLabelOutdated(cp *gpuv1.ClusterPolicy, nodeLabels map[string]string) bool {
+	if cp == nil || !hasCommonGPULabel(nodeLabels) {
+		return false
+	}
+	return getNodePool(&cp.Spec, nodeLabels) != nodeLabels[nodePoolLabelKey]
+}
+
+// createOrUpdateNodePoolDaemonSets deploys the DaemonSet obj once for the nodes which do not belong to
+// any of the overriding node pools, and once per node pool with the overridden configuration.
+// Each of them is further deployed once per given container runtime, see createOrUpdateRuntimeDaemonSets.
+func createOrUpdateNodePoolDaemonSets(obj *appsv1.DaemonSet, n ClusterPolicyController, overrides []gpuv1.NodePoolOverride, runtimes []gpuv1.Runtime) (gpuv1.State, error) {
+	pools := make([]string, 0, len(overrides))
+	for _, o := range overrides {
+		pools = append(pools, o.Name)
+	}
+
+	overallState, err := createOrUpdateRuntimeDaemonSets(obj.DeepCopy(), n, runtimes, func(ds *appsv1.DaemonSet) {
+		excludeNodePools(ds, pools)
+	})
+	if err != nil {
+		return gpuv1.NotReady, err
+	}
+
+	for i := range overrides {
+		pool := overrides[i].Name
+		// render the DaemonSet of the node pool from the merged spec
+		poolCtrl := n
+		poolCtrl.ctx = tracing.WithAttributes(n.ctx, tracing.NodePool(pool))
+		poolCtrl.singleton = n.singleton.DeepCopy()
+		if err := overrides[i].ApplyTo(&poolCtrl.singleton.Spec); err != nil {
+			return gpuv1.NotReady, err
+		}
+		state, err := createOrUpdateRuntimeDaemonSets(obj.DeepCopy(), poolCtrl, runtimes, func(ds *appsv1.DaemonSet) {
+			transformNodePoolDaemonSet(ds, pool)
+		})
+		if err != nil {
+			return gpuv1.NotReady, err
+		}
+		if state != gpuv1.Ready {
+			overallState = state
+		}
+	}
+	return overallState, nil
+}
+
+// excludeNodePools prevents the DaemonSet from being scheduled on the nodes of the given node pools
+func excludeNodePools(ds *appsv1.DaemonSet, pools []string) {
+	excludeNodes(ds, nodePoolLabelKey, pools)
+}
+
+// excludeNodes prevents the DaemonSet from being scheduled on the nodes whose label key is set to one of the given values
+func excludeNodes(ds *appsv1.DaemonSet, key string, values []string) {
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      key,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   values,
+	}
+
+	podSpec := &ds.Spec.Template.Spec
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+		len(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{}},
+		}
+	}
+	// node selector terms are ORed, so the requirement is added to every term
+	terms := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for i := range terms {
+		terms[i].MatchExpressions = append(terms[i].MatchExpressions, requirement)
+	}
+}
+
+// transformNodePoolDaemonSet renames the DaemonSet after the node pool and restricts it to the nodes of the pool
+func transformNodePoolDaemonSet(ds *appsv1.DaemonSet, pool string) {
+	transformPoolDaemonSet(ds, nodePoolLabelKey, pool)
+}
+
+// transformPoolDaemonSet renames the DaemonSet after the pool and restricts it to the nodes whose label key is set to the pool
+func transformPoolDaemonSet(ds *appsv1.DaemonSet, key string, pool string) {
+	ds.Name = fmt.Sprintf("%s-%s", ds.Name, pool)
+
+	if ds.Labels == nil {
+		ds.Labels = make(map[string]string)
+	}
+	ds.Labels[key] = pool
+
+	// the pods of the node pool keep the labels of the default DaemonSet, e.g. to be
+	// selected by the same Service, and are told apart by the pool label
+	if ds.Spec.Selector == nil {
+		ds.Spec.Selector = &metav1.LabelSelector{}
+	}
+	if ds.Spec.Selector.MatchLabels == nil {
+		ds.Spec.Selector.MatchLabels = make(map[string]string)
+	}
+	ds.Spec.Selector.MatchLabels[key] = pool
+	if ds.Spec.Template.Labels == nil {
+		ds.Spec.Template.Labels = make(map[string]string)
+	}
+	ds.Spec.Template.Labels[key] = pool
+
+	if ds.Spec.Template.Spec.NodeSelector == nil {
+		ds.Spec.Template.Spec.NodeSelector = make(map[string]string)
+	}
+	ds.Spec.Template.Spec.NodeSelector[key] = pool
+}
+
+// poolDaemonSetName returns the name of the DaemonSet deployed from dsName for the given
+// node pool and container runtime, either of which is empty if it is not deployed separately
+func poolDaemonSetName(dsName string, nodePool string, runtime string) string {
+	name := dsName
+	for _, pool := range []string{nodePool, runtime} {
+		if pool != "" {
+			name = fmt.Sprintf("%s-%s", name, pool)
+		}
+	}
+	return name
+}
+
+// cleanupStalePoolDaemonSets deletes the DaemonSets deployed from dsName for node pools which
+// no longer override the configuration of the DaemonSet, or for container runtimes which are
+// no longer deployed separately
+func (n ClusterPolicyController) cleanupStalePoolDaemonSets(ctx context.Context, dsName string, overrides []gpuv1.NodePoolOverride, runtimes []gpuv1.Runtime) error {
+	// the DaemonSets which are not deployed for a node pool or a runtime have no label to be matched
+	nodePools := map[string]bool{"": true}
+	for _, o := range overrides {
+		nodePools[o.Name] = true
+	}
+	runtimePools := map[string]bool{"": true}
+	for _, runtime := range runtimes {
+		runtimePools[runtime.String()] = true
+	}
+
+	list := &appsv1.DaemonSetList{}
+	err := n.rec.Client.List(ctx, list, client.InNamespace(n.operatorNamespace))
+	if err != nil {
+		return fmt.Errorf("failed to list pool DaemonSets: %w", err)
+	}
+
+	for i := range list.Items {
+		ds := &list.Items[i]
+		nodePool, runtime := ds.Labels[nodePoolLabelKey], ds.Labels[runtimeLabelKey]
+		if nodePool == "" && runtime == "" {
+			continue
+		}
+		if ds.Name != poolDaemonSetName(dsName, nodePool, runtime) || (nodePools[nodePool] && runtimePools[runtime]) || !metav1.IsControlledBy(ds, n.singleton) {
+			continue
+		}
+		n.rec.Log.Info("Deleting stale pool DaemonSet", "name", ds.Name, "nodePool", nodePool, "runtime", runtime)
+		err = n.rec.Client.Delete(ctx, ds)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pool DaemonSet %s: %w", ds.Name, err)
+		}
+		n.operatorMetrics.deleteDaemonSetPods(promcli.Labels{"daemonset": ds.Name})
+		if err == nil {
+			n.recordDaemonSetEvent(ds, consts.DaemonSetDeletedReason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// TestDevicePluginNodePoolOverrides tests that the device-plugin is deployed once per node pool
+// overriding its configuration, and that the DaemonSets of removed node pools are cleaned up
+func TestDevicePluginNodePoolOverrides(t *testing.T) {
+	ctx := context.Background()
+
+	cp := getDevicePluginTestInput("default")
+	cp.Spec.NodePoolOverrides = []gpuv1.NodePoolOverride{
+		{
+			Name:         "a100",
+			NodeSelector: map[string]string{"nvidia.com/gpu.product": "A100-SXM4-80GB"},
+			DevicePlugin: &gpuv1.DevicePluginSpec{
+				Version: "v0.13.0-ubi8",
+				Config:  &gpuv1.DevicePluginConfig{Name: "plugin-config", Default: "a100"},
+			},
+		},
+		{
+			Name:         "l4",
+			NodeSelector: map[string]string{"nvidia.com/gpu.product": "L4"},
+			DCGMExporter: &gpuv1.DCGMExporterSpec{Args: []string{"-f", "/etc/dcgm-exporter/l4.csv"}},
+		},
+	}
+	err := updateClusterPolicy(&clusterPolicyController, cp)
+	require.NoError(t, err)
+
+	addTestState(t, devicePluginState)
+	_, err = clusterPolicyController.step()
+	require.NoError(t, err)
+
+	list := &appsv1.DaemonSetList{}
+	err = clusterPolicyController.rec.Client.List(ctx, list, client.MatchingLabels{"app": "nvidia-device-plugin-daemonset"})
+	require.NoError(t, err)
+	// the l4 node pool does not override the device-plugin
+	require.Len(t, list.Items, 2)
+
+	base := list.Items[0]
+	require.Equal(t, "nvidia-device-plugin-daemonset", base.Name)
+	require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.12.0-ubi8", base.Spec.Template.Spec.Containers[0].Image)
+	terms := base.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	require.Contains(t, terms[0].MatchExpressions, corev1.NodeSelectorRequirement{
+		Key:      nodePoolLabelKey,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   []string{"a100"},
+	})
+
+	pool := list.Items[1]
+	require.Equal(t, "nvidia-device-plugin-daemonset-a100", pool.Name)
+	require.Equal(t, "a100", pool.Labels[nodePoolLabelKey])
+	require.Equal(t, "a100", pool.Spec.Selector.MatchLabels[nodePoolLabelKey])
+	require.Equal(t, "a100", pool.Spec.Template.Labels[nodePoolLabelKey])
+	require.Equal(t, "a100", pool.Spec.Template.Spec.NodeSelector[nodePoolLabelKey])
+	var devicePlugin corev1.Container
+	for _, c := range pool.Spec.Template.Spec.Containers {
+		if c.Name == "nvidia-device-plugin" {
+			devicePlugin = c
+		}
+	}
+	// the overridden fields are merged with the cluster-wide spec
+	require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.13.0-ubi8", devicePlugin.Image)
+	require.Contains(t, devicePlugin.Env, corev1.EnvVar{Name: "CONFIG_FILE", Value: "/config/config.yaml"})
+	require.Equal(t, []corev1.LocalObjectReference{{Name: "ngc-secret"}}, pool.Spec.Template.Spec.ImagePullSecrets)
+
+	// the DaemonSet of a node pool is deleted once the node pool is removed
+	cp = cp.DeepCopy()
+	cp.Spec.NodePoolOverrides = cp.Spec.NodePoolOverrides[1:]
+	err = updateClusterPolicy(&clusterPolicyController, cp)
+	require.NoError(t, err)
+	clusterPolicyController.idx--
+	_, err = clusterPolicyController.step()
+	require.NoError(t, err)
+
+	err = clusterPolicyController.rec.Client.List(ctx, list, client.MatchingLabels{"app": "nvidia-device-plugin-daemonset"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "nvidia-device-plugin-daemonset", list.Items[0].Name)
+
+	// cleanup by deleting all kubernetes objects
+	err = removeState(&clusterPolicyController, clusterPolicyController.idx-1)
+	require.NoError(t, err)
+	clusterPolicyController.idx--
+}
+
+func TestUpdateNodePoolLabel(t *testing.T) {
+	spec := &gpuv1.ClusterPolicySpec{
+		NodePoolOverrides: []gpuv1.NodePoolOverride{
+			{Name: "l4", NodeSelector: map[string]string{"nvidia.com/gpu.product": "L4"}},
+		},
+	}
+
+	labels := map[string]string{"nvidia.com/gpu.product": "L4"}
+	require.True(t, updateNodePoolLabel(labels, getNodePool(spec, labels)))
+	require.Equal(t, "l4", labels[nodePoolLabelKey])
+	require.False(t, updateNodePoolLabel(labels, getNodePool(spec, labels)))
+
+	labels["nvidia.com/gpu.product"] = "A100"
+	require.True(t, updateNodePoolLabel(labels, getNodePool(spec, labels)))
+	require.NotContains(t, labels, nodePoolLabelKey)
+}
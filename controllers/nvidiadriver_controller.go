@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"maps"
-	"time"
+	"os"
+	"slices"
+	"sync/atomic"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,7 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/util/workqueue"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -42,10 +44,18 @@ import (
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
 	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
+	"github.com/NVIDIA/gpu-operator/internal/apply"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
 	"github.com/NVIDIA/gpu-operator/internal/conditions"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/health"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
+	"github.com/NVIDIA/gpu-operator/internal/plan"
+	"github.com/NVIDIA/gpu-operator/internal/requeue"
 	"github.com/NVIDIA/gpu-operator/internal/state"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
 	"github.com/NVIDIA/gpu-operator/internal/validator"
+	"github.com/NVIDIA/gpu-operator/manifests"
 )
 
 // NVIDIADriverReconciler reconciles a NVIDIADriver object
@@ -53,10 +63,29 @@ type NVIDIADriverReconciler struct {
 	client.Client
 	Scheme      *runtime.Scheme
 	ClusterInfo clusterinfo.Interface
+	Recorder    record.EventRecorder
+	// APIReader reads the Secrets without caching them, the Client is used if nil
+	APIReader client.Reader
+	// Manifests loads the manifests of the states, the manifests embedded in the operator if nil
+	Manifests *assets.Loader
+	// Images pins the driver images to the digests of their tags when enabled in the ClusterPolicy,
+	// the tags are resolved with the image registries if nil
+	Images *registry.DigestResolver
+	// Signatures verifies the signatures of the driver images when enabled in the ClusterPolicy,
+	// the signatures are fetched from the image registries if nil
+	Signatures *registry.SignatureVerifier
+	// Health records the reconciles for the readiness and liveness checks of the operator, if set
+	Health *health.Tracker
+	// Requeue computes the requeue delays, the delays of the default policy are used if nil
+	Requeue *requeue.Backoff
 
 	stateManager          state.Manager
 	nodeSelectorValidator validator.Validator
 	conditionUpdater      conditions.Updater
+
+	// statesStale is set when the overlay of the manifests changed, the state manager is
+	// recreated with the new manifests by the next reconciliation
+	statesStale atomic.Bool
 }
 
 //+kubebuilder:rbac:groups=nvidia.com,resources=nvidiadrivers,verbs=get;list;watch;create;update;patch;delete
@@ -85,6 +114,13 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			r.Requeue.Forget(req.String())
+			r.Requeue.Forget(planRequeueKey(nvidiav1alpha1.NVIDIADriverCRDName, req.Name))
+			state.DeleteNVIDIADriverMetrics(req.Name)
+			if r.stateManager != nil {
+				r.stateManager.Forget(req.NamespacedName)
+			}
+			apply.DeleteDrift(nvidiav1alpha1.NVIDIADriverCRDName, req.Name)
 			return reconcile.Result{}, nil
 		}
 		err = fmt.Errorf("Error getting NVIDIADriver object: %w", err)
@@ -98,9 +134,9 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
-	// Get the singleton NVIDIA ClusterPolicy object in the cluster.
-	clusterPolicyList := &gpuv1.ClusterPolicyList{}
-	err = r.Client.List(ctx, clusterPolicyList)
+	// Get the active NVIDIA ClusterPolicy object in the cluster, selected as by the ClusterPolicy
+	// controller: deleted and ignored instances are skipped
+	clusterPolicy, err := getActiveClusterPolicy(ctx, r.Client)
 	if err != nil {
 		err = fmt.Errorf("Error getting ClusterPolicy list: %v", err)
 		logger.V(consts.LogLevelError).Error(nil, err.Error())
@@ -112,7 +148,7 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, fmt.Errorf("error getting ClusterPolicyList: %v", err)
 	}
 
-	if len(clusterPolicyList.Items) == 0 {
+	if clusterPolicy == nil {
 		err = fmt.Errorf("no ClusterPolicy object found in the cluster")
 		logger.V(consts.LogLevelError).Error(nil, err.Error())
 		instance.Status.State = nvidiav1alpha1.NotReady
@@ -122,7 +158,7 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 		return reconcile.Result{}, err
 	}
-	clusterPolicyInstance := clusterPolicyList.Items[0]
+	clusterPolicyInstance := *clusterPolicy
 
 	// Create a new InfoCatalog which is a generic interface for passing information to state managers
 	infoCatalog := state.NewInfoCatalog()
@@ -133,6 +169,39 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Add an entry for Clusterpolicy, which is needed to deploy the driver daemonset
 	infoCatalog.Add(state.InfoTypeClusterPolicyCR, clusterPolicyInstance)
 
+	if clusterPolicyInstance.Spec.Operator.IsPinImageDigestsEnabled() || clusterPolicyInstance.Spec.Operator.IsImageVerificationEnabled() {
+		// the tags and signatures of the images are fetched with the image pull secrets of the driver
+		ctx, err = withRegistryCredentials(ctx, r.secretReader(), os.Getenv("OPERATOR_NAMESPACE"), getDriverPullSecrets(instance))
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if clusterPolicyInstance.Spec.Operator.IsPinImageDigestsEnabled() {
+		// keep the digests resolved before, e.g. by a previous instance of the operator
+		r.Images.Add(instance.Status.Images...)
+		infoCatalog.Add(state.InfoTypeImageResolver, r.Images)
+	}
+
+	if clusterPolicyInstance.Spec.Operator.IsImageVerificationEnabled() {
+		policy, err := registry.LoadPolicy(ctx, r.secretReader(), os.Getenv("OPERATOR_NAMESPACE"),
+			clusterPolicyInstance.Spec.Operator.ImageVerification.SecretName)
+		if err != nil {
+			logger.V(consts.LogLevelError).Error(nil, err.Error())
+			instance.Status.State = nvidiav1alpha1.NotReady
+			condErr = r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ImageVerificationFailed, err.Error())
+			if condErr != nil {
+				logger.V(consts.LogLevelDebug).Error(nil, condErr.Error())
+			}
+			return reconcile.Result{RequeueAfter: r.Requeue.Delay(req.String(), 0)}, nil
+		}
+		infoCatalog.Add(state.InfoTypeImageVerifier, &registry.PolicyVerifier{
+			Policy:     policy,
+			Digests:    r.Images,
+			Signatures: r.Signatures,
+		})
+	}
+
 	// Verify the nodeSelector configured for this NVIDIADriver instance does
 	// not conflict with any other instances. This ensures only one driver
 	// is deployed per GPU node.
@@ -146,8 +215,10 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, nil
 	}
 
-	if instance.Spec.UsePrecompiledDrivers() && (instance.Spec.IsGDSEnabled() || instance.Spec.IsGDRCopyEnabled()) {
-		err = fmt.Errorf("GPUDirect Storage driver (nvidia-fs) and/or GDRCopy driver is not supported along with pre-compiled NVIDIA drivers")
+	// Validate the spec constraints which are also enforced by the admission webhook,
+	// in case the webhook is not deployed.
+	if errs := validator.ValidateNVIDIADriverSpec(&instance.Spec); len(errs) != 0 {
+		err = fmt.Errorf("invalid NVIDIADriver spec: %w", errs.ToAggregate())
 		logger.V(consts.LogLevelError).Error(nil, err.Error())
 		instance.Status.State = nvidiav1alpha1.NotReady
 		condErr = r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ReconcileFailed, err.Error())
@@ -157,22 +228,32 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, nil
 	}
 
-	if instance.Spec.IsGDSEnabled() && instance.Spec.IsOpenKernelModulesRequired() && !instance.Spec.IsOpenKernelModulesEnabled() {
-		err = fmt.Errorf("GPUDirect Storage driver '%s' is only supported with NVIDIA OpenRM drivers. Please set 'useOpenKernelModules=true' to enable OpenRM mode", instance.Spec.GPUDirectStorage.Version)
-		logger.V(consts.LogLevelError).Error(nil, err.Error())
-		instance.Status.State = nvidiav1alpha1.NotReady
-		condErr = r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ReconcileFailed, err.Error())
-		if condErr != nil {
-			logger.V(consts.LogLevelDebug).Error(nil, condErr.Error())
+	if plan.Enabled(instance) {
+		// report the driver changes without applying them
+		return r.reconcilePlan(ctx, instance, infoCatalog)
+	}
+
+	if r.statesStale.Swap(false) {
+		stateManager, err := state.NewManager(ctx, nvidiav1alpha1.NVIDIADriverCRDName, r.Client, r.Scheme, r.Recorder, r.manifestsLoader())
+		if err != nil {
+			r.statesStale.Store(true)
+			return reconcile.Result{}, fmt.Errorf("error reloading the manifests of the states: %w", err)
 		}
-		return reconcile.Result{}, nil
+		logger.V(consts.LogLevelInfo).Info("Manifests of the states reloaded")
+		r.stateManager = stateManager
 	}
 
-	// Sync state and update status
-	managerStatus := r.stateManager.SyncState(ctx, instance, infoCatalog)
+	// Sync state and update status, recording the conflicts with other field managers
+	syncCtx, conflicts := apply.WithConflicts(ctx)
+	syncCtx = r.withDriftHandler(syncCtx, instance, clusterPolicyInstance.Spec.Operator.GetDriftPolicy())
+	managerStatus := r.stateManager.SyncState(syncCtx, instance, infoCatalog)
+	condErr = r.conditionUpdater.SetConditionConflict(ctx, instance, conflicts.List())
+	if condErr != nil {
+		logger.V(consts.LogLevelDebug).Error(nil, condErr.Error())
+	}
 
 	// update CR status
-	err = r.updateCrStatus(ctx, instance, managerStatus)
+	err = r.updateCrStatus(ctx, instance, managerStatus, clusterPolicyInstance.Spec.Operator.IsPinImageDigestsEnabled())
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -183,7 +264,11 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		for _, result := range managerStatus.StatesStatus {
 			if result.Status != state.SyncStateReady && result.ErrInfo != nil {
 				errorInfo = result.ErrInfo
-				condErr = r.conditionUpdater.SetConditionsError(ctx, instance, conditions.ReconcileFailed, fmt.Sprintf("Error syncing state %s: %v", result.StateName, errorInfo.Error()))
+				reason := conditions.ReconcileFailed
+				if registry.IsVerificationError(errorInfo) {
+					reason = conditions.ImageVerificationFailed
+				}
+				condErr = r.conditionUpdater.SetConditionsError(ctx, instance, reason, fmt.Sprintf("Error syncing state %s: %v", result.StateName, errorInfo.Error()))
 				if condErr != nil {
 					logger.V(consts.LogLevelDebug).Error(nil, condErr.Error())
 				}
@@ -197,8 +282,9 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				logger.V(consts.LogLevelDebug).Error(nil, condErr.Error())
 			}
 		}
-		return reconcile.Result{RequeueAfter: time.Second * 5}, nil
+		return reconcile.Result{RequeueAfter: r.Requeue.Delay(req.String(), 0)}, nil
 	}
+	r.Requeue.Reset(req.String())
 
 	if condErr = r.conditionUpdater.SetConditionsReady(ctx, instance, "Reconciled", "All resources have been successfully reconciled"); condErr != nil {
 		return ctrl.Result{}, condErr
@@ -207,9 +293,24 @@ func (r *NVIDIADriverReconciler) Reconcile(ctx context.Context, req ctrl.Request
 }
 
 func (r *NVIDIADriverReconciler) updateCrStatus(
-	ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, status state.Results) error {
+	ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, status state.Results, pinImages bool) error {
 	reqLogger := log.FromContext(ctx)
 
+	var images []string
+	if pinImages {
+		list := &appsv1.DaemonSetList{}
+		err := r.Client.List(ctx, list, client.MatchingFields{consts.NVIDIADriverControllerIndexKey: cr.Name})
+		if err != nil {
+			reqLogger.Error(err, "Failed to list driver DaemonSets for status update")
+			return err
+		}
+		daemonsets := make([]*appsv1.DaemonSet, 0, len(list.Items))
+		for i := range list.Items {
+			daemonsets = append(daemonsets, &list.Items[i])
+		}
+		images = getPinnedImages(daemonsets...)
+	}
+
 	// Fetch latest instance and update state to avoid version mismatch
 	instance := &nvidiav1alpha1.NVIDIADriver{}
 	err := r.Client.Get(ctx, types.NamespacedName{Name: cr.Name}, instance)
@@ -218,11 +319,12 @@ func (r *NVIDIADriverReconciler) updateCrStatus(
 		return err
 	}
 
-	// Update global State
-	if instance.Status.State == nvidiav1alpha1.State(status.Status) {
+	// Update global State and pinned images
+	if instance.Status.State == nvidiav1alpha1.State(status.Status) && slices.Equal(instance.Status.Images, images) {
 		return nil
 	}
 	instance.Status.State = nvidiav1alpha1.State(status.Status)
+	instance.Status.Images = images
 
 	// send status update request to k8s API
 	reqLogger.V(consts.LogLevelInfo).Info("Updating CR Status", "Status", instance.Status)
@@ -234,18 +336,65 @@ func (r *NVIDIADriverReconciler) updateCrStatus(
 	return nil
 }
 
+// manifestsLoader returns the loader of the manifests of the states
+func (r *NVIDIADriverReconciler) manifestsLoader() *assets.Loader {
+	if r.Manifests != nil {
+		return r.Manifests
+	}
+	return assets.NewLoader(manifests.FS, "")
+}
+
+// secretReader returns the reader of the Secrets, which are not cached by the operator
+func (r *NVIDIADriverReconciler) secretReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// getDriverPullSecrets returns the image pull secrets of the driver DaemonSet of the NVIDIADriver
+func getDriverPullSecrets(instance *nvidiav1alpha1.NVIDIADriver) []corev1.LocalObjectReference {
+	names := append(slices.Clone(instance.Spec.ImagePullSecrets), instance.Spec.Manager.ImagePullSecrets...)
+	if instance.Spec.GPUDirectStorage != nil {
+		names = append(names, instance.Spec.GPUDirectStorage.ImagePullSecrets...)
+	}
+	if instance.Spec.GDRCopy != nil {
+		names = append(names, instance.Spec.GDRCopy.ImagePullSecrets...)
+	}
+	var secrets []corev1.LocalObjectReference
+	for _, name := range names {
+		if !containsSecret(secrets, name) {
+			secrets = append(secrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+	return secrets
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *NVIDIADriverReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	// Create state manager
 	stateManager, err := state.NewManager(
+		ctx,
 		nvidiav1alpha1.NVIDIADriverCRDName,
 		mgr.GetClient(),
-		mgr.GetScheme())
+		mgr.GetScheme(),
+		r.Recorder,
+		r.manifestsLoader())
 	if err != nil {
 		return fmt.Errorf("error creating state manager: %v", err)
 	}
 	r.stateManager = stateManager
 
+	if r.Images == nil || r.Signatures == nil {
+		registryClient := registry.NewRegistryClient()
+		if r.Images == nil {
+			r.Images = registry.NewDigestResolver(registry.RegistryDigestLookup(registryClient))
+		}
+		if r.Signatures == nil {
+			r.Signatures = registry.NewSignatureVerifier(registryClient)
+		}
+	}
+
 	// initialize validators
 	r.nodeSelectorValidator = validator.NewNodeSelectorValidator(r.Client)
 
@@ -254,16 +403,18 @@ func (r *NVIDIADriverReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 
 	// Create a new NVIDIADriver controller
 	c, err := controller.New("nvidia-driver-controller", mgr, controller.Options{
-		Reconciler:              r,
+		Reconciler:              r.Health.Wrap("nvidia-driver-controller", tracing.WrapReconciler("nvidia-driver-controller", r)),
 		MaxConcurrentReconciles: 1,
-		RateLimiter:             workqueue.NewItemExponentialFailureRateLimiter(minDelayCR, maxDelayCR),
+		RateLimiter:             r.Requeue.RateLimiter("nvidia-driver-controller"),
 	})
 	if err != nil {
 		return err
 	}
 
 	// Watch for changes to the primary resource NVIDIaDriver
-	err = c.Watch(source.Kind(mgr.GetCache(), &nvidiav1alpha1.NVIDIADriver{}), &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	// Annotation changes are also watched to enable or disable the plan mode
+	err = c.Watch(source.Kind(mgr.GetCache(), &nvidiav1alpha1.NVIDIADriver{}), &handler.EnqueueRequestForObject{},
+		predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))
 	if err != nil {
 		return err
 	}
@@ -304,6 +455,23 @@ func (r *NVIDIADriverReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		return err
 	}
 
+	// Watch for changes to the overlay ConfigMap of the manifests, recreate the state manager
+	// and enqueue a reconcile request for all NVIDIADriver instances
+	overlaySource, err := r.manifestsLoader().OverlaySource(mgr)
+	if err != nil {
+		return err
+	}
+	if overlaySource != nil {
+		overlayMapFn := func(ctx context.Context, a client.Object) []reconcile.Request {
+			r.statesStale.Store(true)
+			return mapFn(ctx, a)
+		}
+		err = c.Watch(overlaySource, handler.EnqueueRequestsFromMapFunc(overlayMapFn))
+		if err != nil {
+			return err
+		}
+	}
+
 	nodePredicate := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			labels := e.Object.GetLabels()
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -35,7 +36,6 @@ import (
 	"github.com/mitchellh/hashstructure"
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	apiimagev1 "github.com/openshift/api/image/v1"
-	secv1 "github.com/openshift/api/security/v1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"golang.org/x/mod/semver"
 	appsv1 "k8s.io/api/apps/v1"
@@ -53,6 +53,8 @@ import (
 	"sigs.k8s.io/yaml"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/apply"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
 
 const (
@@ -132,6 +134,8 @@ const (
 	MOFEDEnabledEnvName = "MOFED_ENABLED"
 	// ServiceMonitorCRDName is the name of the CRD defining the ServiceMonitor kind
 	ServiceMonitorCRDName = "servicemonitors.monitoring.coreos.com"
+	// PrometheusRuleCRDName is the name of the CRD defining the PrometheusRule kind
+	PrometheusRuleCRDName = "prometheusrules.monitoring.coreos.com"
 	// DefaultToolkitInstallDir is the default toolkit installation directory on the host
 	DefaultToolkitInstallDir = "/usr/local/nvidia"
 	// ToolkitInstallDirEnvName is the name of the toolkit container env for configuring where NVIDIA Container Toolkit is installed
@@ -201,6 +205,16 @@ var CertConfigPathMap = map[string]string{
 	"rhel":   "/etc/pki/ca-trust/extracted/pem",
 }
 
+// TrustedCABundleFileMap indicates the OS specific file names of the trusted CA bundle in the
+// directory of CertConfigPathMap. update-ca-certificates only picks up .crt files on Ubuntu, while
+// the bundle extracted from the trust store is replaced on RHEL based systems.
+var TrustedCABundleFileMap = map[string]string{
+	"centos": TrustedCACertificate,
+	"ubuntu": "gpu-operator-trusted-ca.crt",
+	"rhcos":  TrustedCACertificate,
+	"rhel":   TrustedCACertificate,
+}
+
 func newHostPathType(pathType corev1.HostPathType) *corev1.HostPathType {
 	hostPathType := new(corev1.HostPathType)
 	*hostPathType = pathType
@@ -296,13 +310,8 @@ func ServiceAccount(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.rec.Client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, skipping update")
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -331,18 +340,8 @@ func Role(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.rec.Client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.rec.Client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 
@@ -382,18 +381,8 @@ func RoleBinding(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.rec.Client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.rec.Client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 
@@ -423,18 +412,8 @@ func ClusterRole(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.rec.Client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.rec.Client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 
@@ -468,18 +447,8 @@ func ClusterRoleBinding(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.rec.Client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.rec.Client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return gpuv1.Ready, nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 
@@ -544,18 +513,9 @@ func createConfigMap(n ClusterPolicyController, configMapIdx int) (gpuv1.State,
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.rec.Client.Create(ctx, obj); err != nil {
-		if !apierrors.IsAlreadyExists(err) {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-
-		logger.Info("Found Resource, updating...")
-		err = n.rec.Client.Update(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't update", "Error", err)
-			return gpuv1.NotReady, err
-		}
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
+		return gpuv1.NotReady, err
 	}
 
 	return gpuv1.Ready, nil
@@ -722,6 +682,13 @@ func preProcessDaemonSet(obj *appsv1.DaemonSet, n ClusterPolicyController) error
 	// apply custom Labels and Annotations to the podSpec if any
 	applyCommonDaemonsetMetadata(obj, &n.singleton.Spec.Daemonsets)
 
+	// apply the user provided pod template patch last, so that it can override any rendered field
+	err = applyPodTemplatePatch(obj, &n.singleton.Spec)
+	if err != nil {
+		logger.Error(err, "Failed to apply pod template patch", "resource", obj.Name)
+		return err
+	}
+
 	return nil
 }
 
@@ -770,9 +737,46 @@ func applyCommonDaemonsetConfig(obj *appsv1.DaemonSet, config *gpuv1.ClusterPoli
 	if len(config.Daemonsets.Tolerations) > 0 {
 		obj.Spec.Template.Spec.Tolerations = config.Daemonsets.Tolerations
 	}
+
+	applyComponentSchedulingConfig(obj, getComponentSpec(config, obj.Name))
 	return nil
 }
 
+// applyComponentSchedulingConfig applies the scheduling settings of the component spec of the Daemonset.
+// They take precedence over the common settings of spec.daemonsets: the priorityClassName of the component
+// replaces the common one, the tolerations of the component are added to the common ones, and the node
+// affinity of the component replaces the node affinity of the rendered Daemonset. Node pools are excluded
+// from the node affinity afterwards.
+func applyComponentSchedulingConfig(obj *appsv1.DaemonSet, component *componentSpec) {
+	if component == nil {
+		return
+	}
+	podSpec := &obj.Spec.Template.Spec
+
+	if component.PriorityClassName != "" {
+		podSpec.PriorityClassName = component.PriorityClassName
+	}
+
+	if len(component.Tolerations) > 0 {
+		// the common tolerations are shared with the ClusterPolicy, append to a copy
+		tolerations := slices.Clone(podSpec.Tolerations)
+		for i := range component.Tolerations {
+			toleration := &component.Tolerations[i]
+			if !slices.ContainsFunc(tolerations, func(t corev1.Toleration) bool { return t.MatchToleration(toleration) }) {
+				tolerations = append(tolerations, *toleration)
+			}
+		}
+		podSpec.Tolerations = tolerations
+	}
+
+	if component.NodeAffinity != nil {
+		if podSpec.Affinity == nil {
+			podSpec.Affinity = &corev1.Affinity{}
+		}
+		podSpec.Affinity.NodeAffinity = component.NodeAffinity.DeepCopy()
+	}
+}
+
 // TransformGPUDiscoveryPlugin transforms GPU discovery daemonset with required config as per ClusterPolicy
 func TransformGPUDiscoveryPlugin(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n ClusterPolicyController) error {
 	// update validation container
@@ -782,7 +786,7 @@ func TransformGPUDiscoveryPlugin(obj *appsv1.DaemonSet, config *gpuv1.ClusterPol
 	}
 
 	// update image
-	img, err := gpuv1.ImagePath(&config.GPUFeatureDiscovery)
+	img, err := config.ImagePath(&config.GPUFeatureDiscovery)
 	if err != nil {
 		return err
 	}
@@ -868,7 +872,7 @@ func TransformDriver(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n C
 	}
 
 	// update driver-manager initContainer
-	err = transformDriverManagerInitContainer(obj, &config.Driver.Manager, config.Driver.GPUDirectRDMA)
+	err = transformDriverManagerInitContainer(obj, config, &config.Driver.Manager, config.Driver.GPUDirectRDMA)
 	if err != nil {
 		return err
 	}
@@ -903,6 +907,12 @@ func TransformDriver(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n C
 		return fmt.Errorf("ERROR: failed to transform the Driver Toolkit Container: %s", err)
 	}
 
+	// apply the proxy settings and trusted CA bundle on clusters other than OpenShift
+	err = applyProxyConfig(n, &obj.Spec.Template.Spec, "nvidia-driver-ctr", "nvidia-fs-ctr", "nvidia-gdrcopy-ctr")
+	if err != nil {
+		return err
+	}
+
 	// updates for per kernel version pods using pre-compiled drivers
 	if config.Driver.UsePrecompiledDrivers() {
 		err = transformPrecompiledDriverDaemonset(obj, config, n)
@@ -916,7 +926,7 @@ func TransformDriver(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n C
 // TransformVGPUManager transforms NVIDIA vGPU Manager daemonset with required config as per ClusterPolicy
 func TransformVGPUManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n ClusterPolicyController) error {
 	// update k8s-driver-manager initContainer
-	err := transformDriverManagerInitContainer(obj, &config.VGPUManager.DriverManager, nil)
+	err := transformDriverManagerInitContainer(obj, config, &config.VGPUManager.DriverManager, nil)
 	if err != nil {
 		return fmt.Errorf("failed to transform k8s-driver-manager initContainer for vGPU Manager: %v", err)
 	}
@@ -999,6 +1009,81 @@ func applyOCPProxySpec(n ClusterPolicyController, podSpec *corev1.PodSpec) error
 	return nil
 }
 
+// applyProxyConfig applies the proxy settings and the trusted CA bundle of the ClusterPolicy to the
+// named containers of podSpec, the same way as the cluster wide proxy on OpenShift. Proxy env
+// variables already set on a container are kept. The CA bundle is mounted to the trust store of the
+// OS of the nodes. The settings are ignored on OpenShift.
+func applyProxyConfig(n ClusterPolicyController, podSpec *corev1.PodSpec, containerNames ...string) error {
+	operator := &n.singleton.Spec.Operator
+	if n.openshift != "" || (operator.Proxy == nil && operator.TrustedCA == nil) {
+		return nil
+	}
+
+	var trustedCADir, trustedCAFile string
+	if operator.TrustedCA != nil && operator.TrustedCA.Name != "" {
+		var err error
+		trustedCADir, trustedCAFile, err = getTrustedCABundlePath(n)
+		if err != nil {
+			return fmt.Errorf("ERROR: failed to get destination directory for the trusted CA bundle: %w", err)
+		}
+	}
+
+	var proxyEnv []corev1.EnvVar
+	if operator.Proxy != nil {
+		proxyEnv = getProxyEnv(&apiconfigv1.Proxy{
+			Spec: apiconfigv1.ProxySpec{
+				HTTPProxy:  operator.Proxy.HTTPProxy,
+				HTTPSProxy: operator.Proxy.HTTPSProxy,
+				NoProxy:    operator.Proxy.NoProxy,
+			},
+		})
+	}
+
+	mounted := false
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if !slices.Contains(containerNames, container.Name) {
+			continue
+		}
+		for _, env := range proxyEnv {
+			if getContainerEnv(container, env.Name) == "" {
+				setContainerEnv(container, env.Name, env.Value)
+			}
+		}
+		if trustedCADir == "" {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts,
+			corev1.VolumeMount{
+				Name:      TrustedCAConfigMapName,
+				ReadOnly:  true,
+				MountPath: trustedCADir,
+			})
+		mounted = true
+	}
+	if !mounted {
+		return nil
+	}
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: TrustedCAConfigMapName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: operator.TrustedCA.Name,
+					},
+					Items: []corev1.KeyToPath{
+						{
+							Key:  operator.TrustedCA.GetKey(),
+							Path: trustedCAFile,
+						},
+					},
+				},
+			},
+		})
+	return nil
+}
+
 // getOrCreateTrustedCAConfigMap creates or returns an existing Trusted CA Bundle ConfigMap.
 func getOrCreateTrustedCAConfigMap(n ClusterPolicyController, name string) (*corev1.ConfigMap, error) {
 	ctx := n.ctx
@@ -1088,7 +1173,7 @@ func TransformToolkit(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n
 		return err
 	}
 	// update image
-	image, err := gpuv1.ImagePath(&config.Toolkit)
+	image, err := config.ImagePath(&config.Toolkit)
 	if err != nil {
 		return err
 	}
@@ -1156,7 +1241,7 @@ func TransformToolkit(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n
 	}
 
 	// setup mounts for runtime config file
-	runtimeConfigFile, err := getRuntimeConfigFile(&(obj.Spec.Template.Spec.Containers[0]), runtime)
+	runtimeConfigFile, err := getRuntimeConfigFile(&(obj.Spec.Template.Spec.Containers[0]), runtime, getDistributionProfile(n.distribution))
 	if err != nil {
 		return fmt.Errorf("error getting path to runtime config file: %v", err)
 	}
@@ -1182,7 +1267,7 @@ func TransformToolkit(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n
 	obj.Spec.Template.Spec.Volumes = append(obj.Spec.Template.Spec.Volumes, configVol)
 
 	// setup mounts for runtime socket file
-	runtimeSocketFile, err := getRuntimeSocketFile(&(obj.Spec.Template.Spec.Containers[0]), runtime)
+	runtimeSocketFile, err := getRuntimeSocketFile(&(obj.Spec.Template.Spec.Containers[0]), runtime, getDistributionProfile(n.distribution))
 	if err != nil {
 		return fmt.Errorf("error getting path to runtime socket: %v", err)
 	}
@@ -1213,7 +1298,9 @@ func TransformToolkit(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n
 			}
 		}
 	}
-	return nil
+
+	// apply the proxy settings and trusted CA bundle on clusters other than OpenShift
+	return applyProxyConfig(n, &obj.Spec.Template.Spec, obj.Spec.Template.Spec.Containers[0].Name)
 }
 
 // TransformDevicePlugin transforms k8s-device-plugin daemonset with required config as per ClusterPolicy
@@ -1225,7 +1312,7 @@ func TransformDevicePlugin(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpe
 	}
 
 	// update image
-	image, err := gpuv1.ImagePath(&config.DevicePlugin)
+	image, err := config.ImagePath(&config.DevicePlugin)
 	if err != nil {
 		return err
 	}
@@ -1272,6 +1359,9 @@ func TransformDevicePlugin(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpe
 	// set RuntimeClass for supported runtimes
 	setRuntimeClass(&obj.Spec.Template.Spec, n.runtime, config.Operator.RuntimeClass)
 
+	// mount the device plugin directory of the kubelet of the Kubernetes distribution
+	setHostPathVolume(&obj.Spec.Template.Spec, devicePluginVolumeName, getDistributionProfile(n.distribution).devicePluginDir())
+
 	// update env required for MIG support
 	applyMIGConfiguration(&(obj.Spec.Template.Spec.Containers[0]), config.MIG.Strategy)
 
@@ -1308,7 +1398,7 @@ func TransformMPSControlDaemon(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolic
 		return err
 	}
 
-	image, err := gpuv1.ImagePath(&config.DevicePlugin)
+	image, err := config.ImagePath(&config.DevicePlugin)
 	if err != nil {
 		return err
 	}
@@ -1386,7 +1476,7 @@ func TransformSandboxDevicePlugin(obj *appsv1.DaemonSet, config *gpuv1.ClusterPo
 		return err
 	}
 	// update image
-	image, err := gpuv1.ImagePath(&config.SandboxDevicePlugin)
+	image, err := config.ImagePath(&config.SandboxDevicePlugin)
 	if err != nil {
 		return err
 	}
@@ -1416,6 +1506,8 @@ func TransformSandboxDevicePlugin(obj *appsv1.DaemonSet, config *gpuv1.ClusterPo
 			setContainerEnv(&(obj.Spec.Template.Spec.Containers[0]), env.Name, env.Value)
 		}
 	}
+	// mount the device plugin directory of the kubelet of the Kubernetes distribution
+	setHostPathVolume(&obj.Spec.Template.Spec, devicePluginVolumeName, getDistributionProfile(n.distribution).devicePluginDir())
 	return nil
 }
 
@@ -1428,7 +1520,7 @@ func TransformDCGMExporter(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpe
 	}
 
 	// update image
-	image, err := gpuv1.ImagePath(&config.DCGMExporter)
+	image, err := config.ImagePath(&config.DCGMExporter)
 	if err != nil {
 		return err
 	}
@@ -1480,6 +1572,9 @@ func TransformDCGMExporter(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpe
 	// set RuntimeClass for supported runtimes
 	setRuntimeClass(&obj.Spec.Template.Spec, n.runtime, config.Operator.RuntimeClass)
 
+	// mount the pod-resources directory of the kubelet of the Kubernetes distribution
+	setHostPathVolume(&obj.Spec.Template.Spec, podResourcesVolumeName, getDistributionProfile(n.distribution).podResourcesDir())
+
 	// mount configmap for custom metrics if provided by user
 	if config.DCGMExporter.MetricsConfig != nil && config.DCGMExporter.MetricsConfig.Name != "" {
 		metricsConfigVolMount := corev1.VolumeMount{Name: "metrics-config", ReadOnly: true, MountPath: MetricsConfigMountPath, SubPath: MetricsConfigFileName}
@@ -1515,7 +1610,7 @@ func TransformDCGMExporter(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpe
 	}
 
 	// Add initContainer for OCP to set proper SELinux context on /var/lib/kubelet/pod-resources
-	initImage, err := gpuv1.ImagePath(&config.Operator.InitContainer)
+	initImage, err := config.ImagePath(&config.Operator.InitContainer)
 	if err != nil {
 		return err
 	}
@@ -1539,7 +1634,7 @@ func TransformDCGMExporter(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpe
 	// Disable all constraints on the configurations required by NVIDIA container toolkit
 	setContainerEnv(&initContainer, NvidiaDisableRequireEnvName, "true")
 
-	volMountSockName, volMountSockPath := "pod-gpu-resources", "/var/lib/kubelet/pod-resources"
+	volMountSockName, volMountSockPath := podResourcesVolumeName, "/var/lib/kubelet/pod-resources"
 	volMountSock := corev1.VolumeMount{Name: volMountSockName, MountPath: volMountSockPath}
 	initContainer.VolumeMounts = append(initContainer.VolumeMounts, volMountSock)
 
@@ -1564,7 +1659,7 @@ func TransformDCGM(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n Clu
 		return err
 	}
 	// update image
-	image, err := gpuv1.ImagePath(&config.DCGM)
+	image, err := config.ImagePath(&config.DCGM)
 	if err != nil {
 		return err
 	}
@@ -1619,7 +1714,7 @@ func TransformMIGManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec,
 	}
 
 	// update image
-	image, err := gpuv1.ImagePath(&config.MIGManager)
+	image, err := config.ImagePath(&config.MIGManager)
 	if err != nil {
 		return err
 	}
@@ -1696,7 +1791,7 @@ func TransformMIGManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec,
 // TransformKataManager transforms Kata Manager daemonset with required config as per ClusterPolicy
 func TransformKataManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n ClusterPolicyController) error {
 	// update image
-	image, err := gpuv1.ImagePath(&config.KataManager)
+	image, err := config.ImagePath(&config.KataManager)
 	if err != nil {
 		return err
 	}
@@ -1750,7 +1845,7 @@ func TransformKataManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec
 
 	// setup mounts for runtime config file
 	runtime := n.runtime.String()
-	runtimeConfigFile, err := getRuntimeConfigFile(&(obj.Spec.Template.Spec.Containers[0]), runtime)
+	runtimeConfigFile, err := getRuntimeConfigFile(&(obj.Spec.Template.Spec.Containers[0]), runtime, getDistributionProfile(n.distribution))
 	if err != nil {
 		return fmt.Errorf("error getting path to runtime config file: %v", err)
 	}
@@ -1765,7 +1860,7 @@ func TransformKataManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec
 	obj.Spec.Template.Spec.Volumes = append(obj.Spec.Template.Spec.Volumes, configVol)
 
 	// setup mounts for runtime socket file
-	runtimeSocketFile, err := getRuntimeSocketFile(&(obj.Spec.Template.Spec.Containers[0]), runtime)
+	runtimeSocketFile, err := getRuntimeSocketFile(&(obj.Spec.Template.Spec.Containers[0]), runtime, getDistributionProfile(n.distribution))
 	if err != nil {
 		return fmt.Errorf("error getting path to runtime socket: %v", err)
 	}
@@ -1798,13 +1893,13 @@ func TransformKataManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec
 // TransformVFIOManager transforms VFIO-PCI Manager daemonset with required config as per ClusterPolicy
 func TransformVFIOManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n ClusterPolicyController) error {
 	// update k8s-driver-manager initContainer
-	err := transformDriverManagerInitContainer(obj, &config.VFIOManager.DriverManager, nil)
+	err := transformDriverManagerInitContainer(obj, config, &config.VFIOManager.DriverManager, nil)
 	if err != nil {
 		return fmt.Errorf("failed to transform k8s-driver-manager initContainer for VFIO Manager: %v", err)
 	}
 
 	// update image
-	image, err := gpuv1.ImagePath(&config.VFIOManager)
+	image, err := config.ImagePath(&config.VFIOManager)
 	if err != nil {
 		return err
 	}
@@ -1845,7 +1940,7 @@ func TransformVFIOManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec
 // TransformCCManager transforms CC Manager daemonset with required config as per ClusterPolicy
 func TransformCCManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n ClusterPolicyController) error {
 	// update image
-	image, err := gpuv1.ImagePath(&config.CCManager)
+	image, err := config.ImagePath(&config.CCManager)
 	if err != nil {
 		return err
 	}
@@ -1896,7 +1991,7 @@ func TransformVGPUDeviceManager(obj *appsv1.DaemonSet, config *gpuv1.ClusterPoli
 	}
 
 	// update image
-	image, err := gpuv1.ImagePath(&config.VGPUDeviceManager)
+	image, err := config.ImagePath(&config.VGPUDeviceManager)
 	if err != nil {
 		return err
 	}
@@ -1977,7 +2072,7 @@ func TransformValidator(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec,
 	}
 
 	for _, component := range components {
-		if err := TransformValidatorComponent(config, &obj.Spec.Template.Spec, component); err != nil {
+		if err := TransformValidatorComponent(config, &obj.Spec.Template.Spec, component, n); err != nil {
 			validatorErr = errors.Join(validatorErr, err)
 		}
 	}
@@ -2006,7 +2101,7 @@ func TransformSandboxValidator(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolic
 	}
 
 	for _, component := range components {
-		if err := TransformValidatorComponent(config, &obj.Spec.Template.Spec, component); err != nil {
+		if err := TransformValidatorComponent(config, &obj.Spec.Template.Spec, component, n); err != nil {
 			validatorErr = errors.Join(validatorErr, err)
 		}
 	}
@@ -2021,7 +2116,7 @@ func TransformSandboxValidator(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolic
 // TransformValidatorShared applies general transformations to the validator daemonset with required config as per ClusterPolicy
 func TransformValidatorShared(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, n ClusterPolicyController) error {
 	// update image
-	image, err := gpuv1.ImagePath(&config.Validator)
+	image, err := config.ImagePath(&config.Validator)
 	if err != nil {
 		return err
 	}
@@ -2055,14 +2150,14 @@ func TransformValidatorShared(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicy
 }
 
 // TransformValidatorComponent applies changes to given validator component
-func TransformValidatorComponent(config *gpuv1.ClusterPolicySpec, podSpec *corev1.PodSpec, component string) error {
+func TransformValidatorComponent(config *gpuv1.ClusterPolicySpec, podSpec *corev1.PodSpec, component string, n ClusterPolicyController) error {
 	for i, initContainer := range podSpec.InitContainers {
 		// skip if not component validation initContainer
 		if !strings.Contains(initContainer.Name, fmt.Sprintf("%s-validation", component)) {
 			continue
 		}
 		// update validation image
-		image, err := gpuv1.ImagePath(&config.Validator)
+		image, err := config.ImagePath(&config.Validator)
 		if err != nil {
 			return err
 		}
@@ -2143,7 +2238,7 @@ func TransformValidatorComponent(config *gpuv1.ClusterPolicySpec, podSpec *corev
 			}
 		case "vfio-pci":
 			// set/append environment variables for vfio-pci-validation container
-			setContainerEnv(&(podSpec.InitContainers[i]), "DEFAULT_GPU_WORKLOAD_CONFIG", defaultGPUWorkloadConfig)
+			setContainerEnv(&(podSpec.InitContainers[i]), "DEFAULT_GPU_WORKLOAD_CONFIG", n.defaultGPUWorkloadConfig)
 			if len(config.Validator.VFIOPCI.Env) > 0 {
 				for _, env := range config.Validator.VFIOPCI.Env {
 					setContainerEnv(&(podSpec.InitContainers[i]), env.Name, env.Value)
@@ -2151,7 +2246,7 @@ func TransformValidatorComponent(config *gpuv1.ClusterPolicySpec, podSpec *corev
 			}
 		case "vgpu-manager":
 			// set/append environment variables for vgpu-manager-validation container
-			setContainerEnv(&(podSpec.InitContainers[i]), "DEFAULT_GPU_WORKLOAD_CONFIG", defaultGPUWorkloadConfig)
+			setContainerEnv(&(podSpec.InitContainers[i]), "DEFAULT_GPU_WORKLOAD_CONFIG", n.defaultGPUWorkloadConfig)
 			if len(config.Validator.VGPUManager.Env) > 0 {
 				for _, env := range config.Validator.VGPUManager.Env {
 					setContainerEnv(&(podSpec.InitContainers[i]), env.Name, env.Value)
@@ -2159,7 +2254,7 @@ func TransformValidatorComponent(config *gpuv1.ClusterPolicySpec, podSpec *corev
 			}
 		case "vgpu-devices":
 			// set/append environment variables for vgpu-devices-validation container
-			setContainerEnv(&(podSpec.InitContainers[i]), "DEFAULT_GPU_WORKLOAD_CONFIG", defaultGPUWorkloadConfig)
+			setContainerEnv(&(podSpec.InitContainers[i]), "DEFAULT_GPU_WORKLOAD_CONFIG", n.defaultGPUWorkloadConfig)
 			if len(config.Validator.VGPUDevices.Env) > 0 {
 				for _, env := range config.Validator.VGPUDevices.Env {
 					setContainerEnv(&(podSpec.InitContainers[i]), env.Name, env.Value)
@@ -2181,7 +2276,7 @@ func TransformNodeStatusExporter(obj *appsv1.DaemonSet, config *gpuv1.ClusterPol
 	}
 
 	// update image
-	image, err := gpuv1.ImagePath(&config.NodeStatusExporter)
+	image, err := config.ImagePath(&config.NodeStatusExporter)
 	if err != nil {
 		return err
 	}
@@ -2219,8 +2314,8 @@ func TransformNodeStatusExporter(obj *appsv1.DaemonSet, config *gpuv1.ClusterPol
 	return nil
 }
 
-// get runtime(docker, containerd) config file path based on toolkit container env or default
-func getRuntimeConfigFile(c *corev1.Container, runtime string) (string, error) {
+// get runtime(docker, containerd) config file path based on toolkit container env or the default of the distribution profile
+func getRuntimeConfigFile(c *corev1.Container, runtime string, profile distributionProfile) (string, error) {
 	var runtimeConfigFile string
 	switch runtime {
 	case gpuv1.Docker.String():
@@ -2229,7 +2324,7 @@ func getRuntimeConfigFile(c *corev1.Container, runtime string) (string, error) {
 			runtimeConfigFile = value
 		}
 	case gpuv1.Containerd.String():
-		runtimeConfigFile = DefaultContainerdConfigFile
+		runtimeConfigFile = profile.containerdConfigFile
 		if value := getContainerEnv(c, "CONTAINERD_CONFIG"); value != "" {
 			runtimeConfigFile = value
 		}
@@ -2245,8 +2340,8 @@ func getRuntimeConfigFile(c *corev1.Container, runtime string) (string, error) {
 	return runtimeConfigFile, nil
 }
 
-// get runtime(docker, containerd) socket file path based on toolkit container env or default
-func getRuntimeSocketFile(c *corev1.Container, runtime string) (string, error) {
+// get runtime(docker, containerd) socket file path based on toolkit container env or the default of the distribution profile
+func getRuntimeSocketFile(c *corev1.Container, runtime string, profile distributionProfile) (string, error) {
 	var runtimeSocketFile string
 	switch runtime {
 	case gpuv1.Docker.String():
@@ -2255,7 +2350,7 @@ func getRuntimeSocketFile(c *corev1.Container, runtime string) (string, error) {
 			runtimeSocketFile = getContainerEnv(c, "DOCKER_SOCKET")
 		}
 	case gpuv1.Containerd.String():
-		runtimeSocketFile = DefaultContainerdSocketFile
+		runtimeSocketFile = profile.containerdSocketFile
 		if getContainerEnv(c, "CONTAINERD_SOCKET") != "" {
 			runtimeSocketFile = getContainerEnv(c, "CONTAINERD_SOCKET")
 		}
@@ -2434,7 +2529,7 @@ func transformConfigManagerInitContainer(obj *appsv1.DaemonSet, config *gpuv1.Cl
 		// config-manager-init container is not added to the spec, this is a no-op
 		return nil
 	}
-	configManagerImage, err := gpuv1.ImagePath(&config.DevicePlugin)
+	configManagerImage, err := config.ImagePath(&config.DevicePlugin)
 	if err != nil {
 		return err
 	}
@@ -2463,7 +2558,7 @@ func transformConfigManagerSidecarContainer(obj *appsv1.DaemonSet, config *gpuv1
 		// config-manager-init container is not added to the spec, this is a no-op
 		return nil
 	}
-	configManagerImage, err := gpuv1.ImagePath(&config.DevicePlugin)
+	configManagerImage, err := config.ImagePath(&config.DevicePlugin)
 	if err != nil {
 		return err
 	}
@@ -2480,7 +2575,7 @@ func transformConfigManagerSidecarContainer(obj *appsv1.DaemonSet, config *gpuv1
 	return nil
 }
 
-func transformDriverManagerInitContainer(obj *appsv1.DaemonSet, driverManagerSpec *gpuv1.DriverManagerSpec, rdmaSpec *gpuv1.GPUDirectRDMASpec) error {
+func transformDriverManagerInitContainer(obj *appsv1.DaemonSet, config *gpuv1.ClusterPolicySpec, driverManagerSpec *gpuv1.DriverManagerSpec, rdmaSpec *gpuv1.GPUDirectRDMASpec) error {
 	var container *corev1.Container
 	for i, initCtr := range obj.Spec.Template.Spec.InitContainers {
 		if initCtr.Name == "k8s-driver-manager" {
@@ -2493,7 +2588,7 @@ func transformDriverManagerInitContainer(obj *appsv1.DaemonSet, driverManagerSpe
 		return fmt.Errorf("failed to find k8s-driver-manager initContainer in spec")
 	}
 
-	managerImage, err := gpuv1.ImagePath(driverManagerSpec)
+	managerImage, err := config.ImagePath(driverManagerSpec)
 	if err != nil {
 		return err
 	}
@@ -2831,7 +2926,7 @@ func transformOpenShiftDriverToolkitContainer(obj *appsv1.DaemonSet, config *gpu
 
 	image := n.ocpDriverToolkit.rhcosDriverToolkitImages[n.ocpDriverToolkit.currentRhcosVersion]
 	if image != "" {
-		driverToolkitContainer.Image = image
+		driverToolkitContainer.Image = config.Operator.MirrorImage(image)
 		n.rec.Log.Info("DriverToolkit", "image", driverToolkitContainer.Image)
 	} else {
 		/* RHCOS tag missing in the Driver-Toolkit imagestream, setup fallback */
@@ -2944,7 +3039,7 @@ func resolveDriverTag(n ClusterPolicyController, driverSpec interface{}) (string
 		// append os-tag to the provided driver version
 		image = fmt.Sprintf("%s-%s", image, osTag)
 	}
-	return image, nil
+	return n.singleton.Spec.Operator.MirrorImage(image), nil
 }
 
 // getRepoConfigPath returns the standard OS specific path for repository configuration files
@@ -2975,6 +3070,40 @@ func getCertConfigPath() (string, error) {
 	return "", fmt.Errorf("distribution not supported")
 }
 
+// getTrustedCABundlePath returns the directory and file name of the trusted CA bundle specific
+// to the OS of the GPU nodes
+func getTrustedCABundlePath(n ClusterPolicyController) (string, string, error) {
+	os, err := getGPUNodeOSReleaseID(n)
+	if err != nil {
+		return "", "", err
+	}
+
+	if path, ok := CertConfigPathMap[os]; ok {
+		return path, TrustedCABundleFileMap[os], nil
+	}
+	return "", "", fmt.Errorf("distribution not supported")
+}
+
+// getGPUNodeOSReleaseID returns the OS of the GPU nodes as labeled by NFD. All the GPU nodes
+// are assumed to run the same OS, as in kernelFullVersion.
+func getGPUNodeOSReleaseID(n ClusterPolicyController) (string, error) {
+	list := &corev1.NodeList{}
+	err := n.rec.Client.List(n.ctx, list, client.MatchingLabels{"nvidia.com/gpu.present": "true"})
+	if err != nil {
+		return "", fmt.Errorf("failed to list the GPU nodes: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return "", fmt.Errorf("no GPU nodes found to get the OS from")
+	}
+
+	osName, ok := list.Items[0].Labels[nfdOSReleaseIDLabelKey]
+	if !ok {
+		err := apierrors.NewNotFound(schema.GroupResource{Group: "Node", Resource: "Label"}, nfdOSReleaseIDLabelKey)
+		return "", fmt.Errorf("failed to get the OS of GPU node %s, is NFD installed in the cluster? %w", list.Items[0].Name, err)
+	}
+	return osName, nil
+}
+
 // getSubscriptionPathsToVolumeSources returns the MountPathToVolumeSource map containing all
 // OS-specific subscription/entitlement paths that need to be mounted in the container.
 func getSubscriptionPathsToVolumeSources() (MountPathToVolumeSource, error) {
@@ -3393,7 +3522,7 @@ func transformValidationInitContainer(obj *appsv1.DaemonSet, config *gpuv1.Clust
 		}
 
 		// update validation image
-		image, err := gpuv1.ImagePath(&config.Validator)
+		image, err := config.ImagePath(&config.Validator)
 		if err != nil {
 			return err
 		}
@@ -3459,6 +3588,8 @@ func isDaemonSetReady(name string, n ClusterPolicyController) gpuv1.State {
 	err := n.rec.Client.Get(ctx, types.NamespacedName{Namespace: n.operatorNamespace, Name: name}, ds)
 	if err != nil {
 		n.rec.Log.Error(err, "could not get daemonset", "name", name)
+	} else if n.operatorMetrics != nil {
+		n.operatorMetrics.setDaemonSetPods(n.stateNames[n.idx], ds)
 	}
 
 	if ds.Status.DesiredNumberScheduled == 0 {
@@ -3614,18 +3745,8 @@ func Deployment(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	if err := n.rec.Client.Create(ctx, obj); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			logger.Info("Found Resource, updating...")
-			err = n.rec.Client.Update(ctx, obj)
-			if err != nil {
-				logger.Info("Couldn't update", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			return isDeploymentReady(obj.Name, n), nil
-		}
-
-		logger.Info("Couldn't create", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 
@@ -3738,7 +3859,9 @@ func (n ClusterPolicyController) cleanupStalePrecompiledDaemonsets(ctx context.C
 		if err != nil {
 			n.rec.Log.Info("ERROR: Could not get delete DaemonSet",
 				"Name", name, "Error", err)
+			continue
 		}
+		n.recordDaemonSetEvent(&list.Items[idx], consts.DaemonSetDeletedReason)
 	}
 	return nil
 }
@@ -3910,6 +4033,7 @@ func (n ClusterPolicyController) ocpCleanupStaleDriverToolkitDaemonSets(ctx cont
 				"Name", name, "Error", err)
 			return err
 		}
+		n.recordDaemonSetEvent(&list.Items[idx], consts.DaemonSetDeletedReason)
 	}
 	return nil
 }
@@ -4053,7 +4177,9 @@ func (n ClusterPolicyController) cleanupDriverDaemonsets(ctx context.Context, se
 			n.rec.Log.Error(err, "Could not get delete DaemonSet",
 				"Name", dsList.Items[idx].ObjectMeta.Name)
 			lastErr = err
+			continue
 		}
+		n.recordDaemonSetEvent(&dsList.Items[idx], consts.DaemonSetDeletedReason)
 	}
 
 	// return the last error that occurred, if any
@@ -4094,6 +4220,12 @@ func DaemonSet(n ClusterPolicyController) (gpuv1.State, error) {
 			logger.Info("Couldn't delete", "Error", err)
 			return gpuv1.NotReady, err
 		}
+		if err == nil {
+			n.recordDaemonSetEvent(obj, consts.DaemonSetDeletedReason)
+		}
+		if err := n.cleanupStalePoolDaemonSets(ctx, obj.Name, nil, nil); err != nil {
+			return gpuv1.NotReady, err
+		}
 		return gpuv1.Disabled, nil
 	}
 
@@ -4157,12 +4289,58 @@ func DaemonSet(n ClusterPolicyController) (gpuv1.State, error) {
 		}
 	}
 
+	// DaemonSets overridden by node pools are deployed once per node pool, and DaemonSets
+	// configured for the container runtime once per runtime of the GPU nodes, in each node pool
+	overrides := getNodePoolOverrides(&n.singleton.Spec, obj.Name)
+	runtimes := getRuntimePools(n, obj.Name)
+	if err := n.cleanupStalePoolDaemonSets(ctx, obj.Name, overrides, runtimes); err != nil {
+		return gpuv1.NotReady, err
+	}
+	if len(overrides) != 0 {
+		return createOrUpdateNodePoolDaemonSets(obj, n, overrides, runtimes)
+	}
+	return createOrUpdateRuntimeDaemonSets(obj, n, runtimes, nil)
+}
+
+// createOrUpdateDaemonSet pre-processes the DaemonSet obj as per the ClusterPolicy and creates
+// or updates it. The optional nodePoolTransform is applied once the DaemonSet is pre-processed.
+func createOrUpdateDaemonSet(obj *appsv1.DaemonSet, n ClusterPolicyController, nodePoolTransform func(*appsv1.DaemonSet)) (gpuv1.State, error) {
+	ctx := n.ctx
+
 	err := preProcessDaemonSet(obj, n)
 	if err != nil {
-		logger.Info("Could not pre-process", "Error", err)
+		n.rec.Log.Info("Could not pre-process", "DaemonSet", obj.Name, "Error", err)
+		return gpuv1.NotReady, err
+	}
+
+	if nodePoolTransform != nil {
+		nodePoolTransform(obj)
+	}
+
+	// the tags and signatures of the images are fetched with the image pull secrets of the DaemonSet
+	registryCtx := ctx
+	if n.singleton.Spec.Operator.IsPinImageDigestsEnabled() || n.singleton.Spec.Operator.IsImageVerificationEnabled() {
+		registryCtx, err = withRegistryCredentials(ctx, n.rec.secretReader(), n.operatorNamespace, obj.Spec.Template.Spec.ImagePullSecrets)
+		if err != nil {
+			n.rec.Log.Info("Could not load the image pull secrets", "DaemonSet", obj.Name, "Error", err)
+			return gpuv1.NotReady, err
+		}
+	}
+
+	err = pinDaemonSetImages(registryCtx, n, obj)
+	if err != nil {
+		n.rec.Log.Info("Could not pin images", "DaemonSet", obj.Name, "Error", err)
 		return gpuv1.NotReady, err
 	}
 
+	err = verifyDaemonSetImages(registryCtx, n, obj)
+	if err != nil {
+		n.rec.Log.Info("Could not verify images", "DaemonSet", obj.Name, "Error", err)
+		return gpuv1.NotReady, err
+	}
+
+	logger := n.rec.Log.WithValues("DaemonSet", obj.Name, "Namespace", obj.Namespace)
+
 	if err := controllerutil.SetControllerReference(n.singleton, obj, n.rec.Scheme); err != nil {
 		logger.Info("SetControllerReference failed", "Error", err)
 		return gpuv1.NotReady, err
@@ -4175,6 +4353,9 @@ func DaemonSet(n ClusterPolicyController) (gpuv1.State, error) {
 	for labelKey, labelValue := range n.singleton.Spec.Daemonsets.Labels {
 		obj.Labels[labelKey] = labelValue
 	}
+	// precompiled, driver-toolkit, node pool and runtime DaemonSets are suffixed, the
+	// state label identifies all the DaemonSets of the state
+	obj.Labels[stateLabelKey] = n.stateNames[n.idx]
 
 	// Daemonsets will always have at least one annotation applied, so allocate if necessary
 	if obj.Annotations == nil {
@@ -4185,41 +4366,19 @@ func DaemonSet(n ClusterPolicyController) (gpuv1.State, error) {
 		obj.Annotations[annoKey] = annoValue
 	}
 
-	found := &appsv1.DaemonSet{}
-	err = n.rec.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("DaemonSet not found, creating",
-			"Name", obj.Name,
-		)
-		// generate hash for the spec to create
-		hashStr := getDaemonsetHash(obj)
-		// add annotation to the Daemonset with hash value during creation
-		obj.Annotations[NvidiaAnnotationHashKey] = hashStr
-		err = n.rec.Client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create DaemonSet",
-				"Name", obj.Name,
-				"Error", err,
-			)
-			return gpuv1.NotReady, err
-		}
-		return isDaemonSetReady(obj.Name, n), nil
-	} else if err != nil {
-		logger.Info("Failed to get DaemonSet from client",
-			"Name", obj.Name,
-			"Error", err.Error())
+	// the hash of the rendered DaemonSet allows the driver states to detect spec changes
+	obj.Annotations[NvidiaAnnotationHashKey] = getDaemonsetHash(obj)
+
+	result, err := n.applyObject(ctx, obj)
+	if err != nil {
+		logger.Info("Couldn't apply DaemonSet", "Name", obj.Name, "Error", err)
 		return gpuv1.NotReady, err
 	}
-
-	changed := isDaemonsetSpecChanged(found, obj)
-	if changed {
-		logger.Info("DaemonSet is different, updating", "name", obj.ObjectMeta.Name)
-		err = n.rec.Client.Update(ctx, obj)
-		if err != nil {
-			return gpuv1.NotReady, err
-		}
-	} else {
-		logger.Info("DaemonSet identical, skipping update", "name", obj.ObjectMeta.Name)
+	switch result {
+	case apply.Created:
+		n.recordDaemonSetEvent(obj, consts.DaemonSetCreatedReason)
+	case apply.Updated:
+		n.recordDaemonSetEvent(obj, consts.DaemonSetUpdatedReason)
 	}
 	return isDaemonSetReady(obj.Name, n), nil
 }
@@ -4236,39 +4395,6 @@ func getDaemonsetHash(daemonset *appsv1.DaemonSet) string {
 	return fmt.Sprint(hasher.Sum32())
 }
 
-// isDaemonsetSpecChanged returns true if the spec has changed between existing one
-// and new Daemonset spec compared by hash.
-func isDaemonsetSpecChanged(current *appsv1.DaemonSet, new *appsv1.DaemonSet) bool {
-	if current == nil && new != nil {
-		return true
-	}
-	if current.Annotations == nil || new.Annotations == nil {
-		panic("appsv1.DaemonSet.Annotations must be allocated prior to calling isDaemonsetSpecChanged()")
-	}
-
-	hashStr := getDaemonsetHash(new)
-	foundHashAnnotation := false
-
-	for annotation, value := range current.Annotations {
-		if annotation == NvidiaAnnotationHashKey {
-			if value != hashStr {
-				// update annotation to be added to Daemonset as per new spec and indicate spec update is required
-				new.Annotations[NvidiaAnnotationHashKey] = hashStr
-				return true
-			}
-			foundHashAnnotation = true
-			break
-		}
-	}
-
-	if !foundHashAnnotation {
-		// update annotation to be added to Daemonset as per new spec and indicate spec update is required
-		new.Annotations[NvidiaAnnotationHashKey] = hashStr
-		return true
-	}
-	return false
-}
-
 // The operator starts two pods in different stages to validate
 // the correct working of the DaemonSets (driver and dp). Therefore
 // the operator waits until the Pod completes and checks the error status
@@ -4333,26 +4459,8 @@ func SecurityContextConstraints(n ClusterPolicyController) (gpuv1.State, error)
 		return gpuv1.NotReady, err
 	}
 
-	found := &secv1.SecurityContextConstraints{}
-	err := n.rec.Client.Get(ctx, types.NamespacedName{Namespace: "", Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.rec.Client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.rec.Client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -4382,27 +4490,8 @@ func Service(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	found := &corev1.Service{}
-	err := n.rec.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.rec.Client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-	obj.Spec.ClusterIP = found.Spec.ClusterIP
-
-	err = n.rec.Client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -4507,26 +4596,8 @@ func ServiceMonitor(n ClusterPolicyController) (gpuv1.State, error) {
 		return gpuv1.NotReady, err
 	}
 
-	found := &promv1.ServiceMonitor{}
-	err = n.rec.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.rec.Client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.rec.Client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -4554,26 +4625,8 @@ func transformRuntimeClassLegacy(n ClusterPolicyController, spec nodev1.RuntimeC
 		return gpuv1.NotReady, err
 	}
 
-	found := &nodev1beta1.RuntimeClass{}
-	err := n.rec.Client.Get(ctx, types.NamespacedName{Namespace: "", Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.rec.Client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.rec.Client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -4601,26 +4654,8 @@ func transformRuntimeClass(n ClusterPolicyController, spec nodev1.RuntimeClass)
 		return gpuv1.NotReady, err
 	}
 
-	found := &nodev1.RuntimeClass{}
-	err := n.rec.Client.Get(ctx, types.NamespacedName{Namespace: "", Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.rec.Client.Create(ctx, obj)
-		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
-			return gpuv1.NotReady, err
-		}
-		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
-	}
-
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
-
-	err = n.rec.Client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -4703,26 +4738,8 @@ func transformKataRuntimeClasses(n ClusterPolicyController) (gpuv1.State, error)
 			return gpuv1.NotReady, err
 		}
 
-		found := &nodev1.RuntimeClass{}
-		err := n.rec.Client.Get(ctx, types.NamespacedName{Namespace: "", Name: obj.Name}, found)
-		if err != nil && apierrors.IsNotFound(err) {
-			logger.Info("Not found, creating...")
-			err = n.rec.Client.Create(ctx, &obj)
-			if err != nil {
-				logger.Info("Couldn't create", "Error", err)
-				return gpuv1.NotReady, err
-			}
-			continue
-		} else if err != nil {
-			return gpuv1.NotReady, err
-		}
-
-		logger.Info("Found Resource, updating...")
-		obj.ResourceVersion = found.ResourceVersion
-
-		err = n.rec.Client.Update(ctx, &obj)
-		if err != nil {
-			logger.Info("Couldn't update", "Error", err)
+		if _, err := n.applyObject(ctx, &obj); err != nil {
+			logger.Info("Couldn't apply", "Error", err)
 			return gpuv1.NotReady, err
 		}
 	}
@@ -4775,30 +4792,56 @@ func PrometheusRule(n ClusterPolicyController) (gpuv1.State, error) {
 
 	logger := n.rec.Log.WithValues("PrometheusRule", obj.Name)
 
-	if err := controllerutil.SetControllerReference(n.singleton, obj, n.rec.Scheme); err != nil {
+	// Check if PrometheusRule is a valid kind
+	prometheusRuleCRDExists, err := crdExists(n, PrometheusRuleCRDName)
+	if err != nil {
 		return gpuv1.NotReady, err
 	}
 
-	found := &promv1.PrometheusRule{}
-	err := n.rec.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, found)
-	if err != nil && apierrors.IsNotFound(err) {
-		logger.Info("Not found, creating...")
-		err = n.rec.Client.Create(ctx, obj)
+	// the overridden alerts must be part of one of the PrometheusRules, the error is reported
+	// in the ClusterPolicy conditions
+	alerts := n.singleton.Spec.Operator.Alerts
+	if unknown := getUnknownAlertRules(n.resources, alerts); len(unknown) != 0 {
+		return gpuv1.NotReady, fmt.Errorf("unknown alerts in spec.operator.alerts.rules: %s", strings.Join(unknown, ", "))
+	}
+
+	// the alerts are enabled by default on OpenShift, where the Prometheus Operator is part of the cluster
+	if !alerts.IsEnabled(n.openshift != "") {
+		if !prometheusRuleCRDExists {
+			return gpuv1.Ready, nil
+		}
+		// the PrometheusRule is looked up in the cache not to send a Delete on every reconcile
+		found := &promv1.PrometheusRule{}
+		err := n.rec.Client.Get(ctx, client.ObjectKeyFromObject(obj), found)
+		if apierrors.IsNotFound(err) {
+			return gpuv1.Ready, nil
+		}
 		if err != nil {
-			logger.Info("Couldn't create", "Error", err)
+			logger.Info("Couldn't get", "Error", err)
+			return gpuv1.NotReady, err
+		}
+		err = n.rec.Client.Delete(ctx, found)
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Info("Couldn't delete", "Error", err)
 			return gpuv1.NotReady, err
 		}
 		return gpuv1.Ready, nil
-	} else if err != nil {
-		return gpuv1.NotReady, err
 	}
 
-	logger.Info("Found Resource, updating...")
-	obj.ResourceVersion = found.ResourceVersion
+	// if PrometheusRule CRD is missing, assume prometheus is not setup and ignore CR creation
+	if !prometheusRuleCRDExists {
+		logger.Info("PrometheusRule CRD is missing, ignoring creation of the alerts")
+		return gpuv1.Ready, nil
+	}
 
-	err = n.rec.Client.Update(ctx, obj)
-	if err != nil {
-		logger.Info("Couldn't update", "Error", err)
+	transformPrometheusRuleAlerts(obj, alerts)
+
+	if err := controllerutil.SetControllerReference(n.singleton, obj, n.rec.Scheme); err != nil {
+		return gpuv1.NotReady, err
+	}
+
+	if _, err := n.applyObject(ctx, obj); err != nil {
+		logger.Info("Couldn't apply", "Error", err)
 		return gpuv1.NotReady, err
 	}
 	return gpuv1.Ready, nil
@@ -43,20 +43,23 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	operatorassets "github.com/NVIDIA/gpu-operator/assets"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
 )
 
 const (
-	clusterPolicyPath             = "config/samples/v1_clusterpolicy.yaml"
-	clusterPolicyName             = "gpu-cluster-policy"
-	driverAssetsPath              = "assets/state-driver/"
-	vGPUManagerAssetsPath         = "assets/state-vgpu-manager/"
-	sandboxDevicePluginAssetsPath = "assets/state-sandbox-device-plugin"
-	devicePluginAssetsPath        = "assets/state-device-plugin/"
-	nfdNvidiaPCILabelKey          = "feature.node.kubernetes.io/pci-10de.present"
-	upgradedKernel                = "5.4.135-generic"
+	clusterPolicyPath        = "config/samples/v1_clusterpolicy.yaml"
+	clusterPolicyName        = "gpu-cluster-policy"
+	driverState              = "state-driver"
+	vGPUManagerState         = "state-vgpu-manager"
+	sandboxDevicePluginState = "state-sandbox-device-plugin"
+	devicePluginState        = "state-device-plugin"
+	nfdNvidiaPCILabelKey     = "feature.node.kubernetes.io/pci-10de.present"
+	upgradedKernel           = "5.4.135-generic"
 )
 
 type testConfig struct {
@@ -175,8 +178,10 @@ func setup() error {
 	}
 
 	// Get a sample ClusterPolicy manifest
-	manifests := getAssetsFrom(&clusterPolicyController, filepath.Join(cfg.root, clusterPolicyPath), "")
-	clusterPolicyManifest := manifests[0]
+	clusterPolicyManifest, err := os.ReadFile(filepath.Join(cfg.root, clusterPolicyPath))
+	if err != nil {
+		return fmt.Errorf("failed to read sample ClusterPolicy manifest: %v", err)
+	}
 	ser := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme,
 		json.SerializerOptions{Yaml: true, Pretty: false, Strict: false})
 	_, _, err = ser.Decode(clusterPolicyManifest, nil, &clusterPolicy)
@@ -236,10 +241,31 @@ func setup() error {
 }
 
 // newCluster creates a mock kubernetes cluster and returns the corresponding client object
+// fakeApplyPatch emulates server-side apply on top of the fake client, which
+// only supports apply patches against objects that already exist.
+func fakeApplyPatch(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Patch(ctx, obj, patch, opts...)
+	}
+	live := obj.DeepCopyObject().(client.Object)
+	err := c.Get(ctx, client.ObjectKeyFromObject(obj), live)
+	if apierrors.IsNotFound(err) {
+		return c.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(live.GetResourceVersion())
+	return c.Update(ctx, obj)
+}
+
 func newCluster(nodes int, s *runtime.Scheme) (client.Client, error) {
 	ctx := context.Background()
 	// Build fake client
-	cl := fake.NewClientBuilder().WithScheme(s).Build()
+	cl := fake.NewClientBuilder().
+		WithScheme(s).
+		WithInterceptorFuncs(interceptor.Funcs{Patch: fakeApplyPatch}).
+		Build()
 
 	for i := 0; i < nodes; i++ {
 		ready := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
@@ -268,6 +294,14 @@ func newCluster(nodes int, s *runtime.Scheme) (client.Client, error) {
 	return cl, nil
 }
 
+// addTestState adds the embedded manifests of state to the ClusterPolicyController
+func addTestState(t *testing.T, state string) {
+	err := addState(&clusterPolicyController, assets.NewLoader(operatorassets.FS, ""), state)
+	if err != nil {
+		t.Fatalf("error adding state %s: %v", state, err)
+	}
+}
+
 // updateClusterPolicy updates an existing ClusterPolicy instance
 func updateClusterPolicy(n *ClusterPolicyController, cp *gpuv1.ClusterPolicy) error {
 	n.singleton = cp
@@ -313,9 +347,15 @@ func testDaemonsetCommon(t *testing.T, cp *gpuv1.ClusterPolicy, component string
 	ctx := context.Background()
 
 	var spec commonDaemonsetSpec
-	var dsLabel, mainCtrName, manifestFile, mainCtrImage string
+	var dsLabel, mainCtrName, state, mainCtrImage string
 	var err error
 
+	// update cluster policy, the expected images depend on it
+	err = updateClusterPolicy(&clusterPolicyController, cp)
+	if err != nil {
+		t.Fatalf("error in test setup: %v", err)
+	}
+
 	// TODO: add cases for all components
 	switch component {
 	case "Driver":
@@ -332,7 +372,7 @@ func testDaemonsetCommon(t *testing.T, cp *gpuv1.ClusterPolicy, component string
 		}
 		dsLabel = "nvidia-driver-daemonset"
 		mainCtrName = "nvidia-driver"
-		manifestFile = filepath.Join(cfg.root, driverAssetsPath)
+		state = driverState
 		mainCtrImage, err = resolveDriverTag(clusterPolicyController, &cp.Spec.Driver)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get mainCtrImage for driver: %v", err)
@@ -350,8 +390,8 @@ func testDaemonsetCommon(t *testing.T, cp *gpuv1.ClusterPolicy, component string
 		}
 		dsLabel = "nvidia-device-plugin-daemonset"
 		mainCtrName = "nvidia-device-plugin"
-		manifestFile = filepath.Join(cfg.root, devicePluginAssetsPath)
-		mainCtrImage, err = gpuv1.ImagePath(&cp.Spec.DevicePlugin)
+		state = devicePluginState
+		mainCtrImage, err = cp.Spec.ImagePath(&cp.Spec.DevicePlugin)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get mainCtrImage for device-plugin: %v", err)
 		}
@@ -368,7 +408,7 @@ func testDaemonsetCommon(t *testing.T, cp *gpuv1.ClusterPolicy, component string
 		}
 		dsLabel = "nvidia-vgpu-manager-daemonset"
 		mainCtrName = "nvidia-vgpu-manager-ctr"
-		manifestFile = filepath.Join(cfg.root, vGPUManagerAssetsPath)
+		state = vGPUManagerState
 		mainCtrImage, err = resolveDriverTag(clusterPolicyController, &cp.Spec.VGPUManager)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get mainCtrImage for driver: %v", err)
@@ -386,8 +426,8 @@ func testDaemonsetCommon(t *testing.T, cp *gpuv1.ClusterPolicy, component string
 		}
 		dsLabel = "nvidia-sandbox-device-plugin-daemonset"
 		mainCtrName = "nvidia-sandbox-device-plugin-ctr"
-		manifestFile = filepath.Join(cfg.root, sandboxDevicePluginAssetsPath)
-		mainCtrImage, err = gpuv1.ImagePath(&cp.Spec.SandboxDevicePlugin)
+		state = sandboxDevicePluginState
+		mainCtrImage, err = cp.Spec.ImagePath(&cp.Spec.SandboxDevicePlugin)
 		if err != nil {
 			return nil, fmt.Errorf("unable to get mainCtrImage for sandbox-device-plugin: %v", err)
 		}
@@ -395,14 +435,8 @@ func testDaemonsetCommon(t *testing.T, cp *gpuv1.ClusterPolicy, component string
 		return nil, fmt.Errorf("invalid component for testDaemonsetCommon(): %s", component)
 	}
 
-	// update cluster policy
-	err = updateClusterPolicy(&clusterPolicyController, cp)
-	if err != nil {
-		t.Fatalf("error in test setup: %v", err)
-	}
-
 	// add manifests
-	addState(&clusterPolicyController, manifestFile)
+	addTestState(t, state)
 
 	// create resources
 	_, err = clusterPolicyController.step()
@@ -605,6 +639,8 @@ func getDevicePluginTestInput(testCase string) *gpuv1.ClusterPolicy {
 		// Do nothing
 	case "custom-config":
 		cp.Spec.DevicePlugin.Config = &gpuv1.DevicePluginConfig{Name: "plugin-config", Default: "default"}
+	case "registry-mirrors":
+		cp.Spec.Operator.ImageRegistryMirrors = map[string]string{"nvcr.io/nvidia": "registry.example.com/nvidia"}
 	default:
 		return nil
 	}
@@ -634,6 +670,9 @@ func getDevicePluginTestOutput(testCase string) map[string]interface{} {
 		output["env"] = map[string]string{
 			"CONFIG_FILE": "/config/config.yaml",
 		}
+	case "registry-mirrors":
+		output["devicePluginImage"] = "registry.example.com/nvidia/k8s-device-plugin:v0.12.0-ubi8"
+		output["env"] = map[string]string{}
 	default:
 		return nil
 	}
@@ -659,6 +698,11 @@ func TestDevicePlugin(t *testing.T) {
 			getDevicePluginTestInput("custom-config"),
 			getDevicePluginTestOutput("custom-config"),
 		},
+		{
+			"RegistryMirrors",
+			getDevicePluginTestInput("registry-mirrors"),
+			getDevicePluginTestOutput("registry-mirrors"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -818,9 +862,8 @@ func TestVGPUManager(t *testing.T) {
 }
 
 func TestVGPUManagerAssets(t *testing.T) {
-	manifestPath := filepath.Join(cfg.root, vGPUManagerAssetsPath)
 	// add manifests
-	addState(&clusterPolicyController, manifestPath)
+	addTestState(t, vGPUManagerState)
 
 	// create resources
 	_, err := clusterPolicyController.step()
@@ -923,9 +966,8 @@ func TestSandboxDevicePlugin(t *testing.T) {
 }
 
 func TestSandboxDevicePluginAssets(t *testing.T) {
-	manifestPath := filepath.Join(cfg.root, sandboxDevicePluginAssetsPath)
 	// add manifests
-	addState(&clusterPolicyController, manifestPath)
+	addTestState(t, sandboxDevicePluginState)
 
 	// create resources
 	_, err := clusterPolicyController.step()
@@ -1001,3 +1043,134 @@ func TestIsOpenKernelModulesRequired(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyProxyConfig(t *testing.T) {
+	newPodSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "nvidia-driver-ctr", Env: []corev1.EnvVar{{Name: "NO_PROXY", Value: "localhost"}}},
+				{Name: "nvidia-fs-ctr"},
+				{Name: "nvidia-peermem-ctr"},
+			},
+		}
+	}
+	operator := gpuv1.OperatorSpec{
+		Proxy:     &gpuv1.ProxySpec{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: ".cluster.local"},
+		TrustedCA: &gpuv1.TrustedCASpec{Name: "cluster-ca"},
+	}
+
+	testCases := []struct {
+		description   string
+		openshift     string
+		osRelease     string
+		operator      gpuv1.OperatorSpec
+		applied       bool
+		trustedCADir  string
+		trustedCAFile string
+	}{
+		{
+			description: "no proxy configured",
+		},
+		{
+			description:   "proxy and trusted CA on ubuntu",
+			osRelease:     "ubuntu",
+			operator:      operator,
+			applied:       true,
+			trustedCADir:  "/usr/local/share/ca-certificates",
+			trustedCAFile: "gpu-operator-trusted-ca.crt",
+		},
+		{
+			description:   "proxy and trusted CA on rhel",
+			osRelease:     "rhel",
+			operator:      operator,
+			applied:       true,
+			trustedCADir:  TrustedCABundleMountDir,
+			trustedCAFile: TrustedCACertificate,
+		},
+		{
+			description: "ignored on openshift",
+			openshift:   "4.14",
+			operator:    operator,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			// the trust store is the one of the OS of the GPU nodes, not of the node of the operator
+			nodes := []client.Object{
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-node", Labels: map[string]string{
+					"nvidia.com/gpu.present": "true",
+					nfdOSReleaseIDLabelKey:   tc.osRelease,
+				}}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cpu-node", Labels: map[string]string{
+					nfdOSReleaseIDLabelKey: "unknown",
+				}}},
+			}
+			n := ClusterPolicyController{
+				ctx:       context.Background(),
+				openshift: tc.openshift,
+				singleton: &gpuv1.ClusterPolicy{Spec: gpuv1.ClusterPolicySpec{Operator: tc.operator}},
+				rec: &ClusterPolicyReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(nodes...).Build(),
+				},
+			}
+			podSpec := newPodSpec()
+			require.NoError(t, applyProxyConfig(n, podSpec, "nvidia-driver-ctr", "nvidia-fs-ctr"))
+			if !tc.applied {
+				require.Equal(t, newPodSpec(), podSpec)
+				return
+			}
+
+			// the env variables set on the container are kept
+			require.Equal(t, []corev1.EnvVar{
+				{Name: "NO_PROXY", Value: "localhost"},
+				{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"},
+				{Name: "https_proxy", Value: "http://proxy.example.com:3128"},
+				{Name: "no_proxy", Value: ".cluster.local"},
+			}, podSpec.Containers[0].Env)
+			require.Len(t, podSpec.Containers[1].Env, 4)
+			require.Empty(t, podSpec.Containers[2].Env)
+
+			mount := corev1.VolumeMount{Name: TrustedCAConfigMapName, ReadOnly: true, MountPath: tc.trustedCADir}
+			require.Equal(t, []corev1.VolumeMount{mount}, podSpec.Containers[0].VolumeMounts)
+			require.Equal(t, []corev1.VolumeMount{mount}, podSpec.Containers[1].VolumeMounts)
+			require.Empty(t, podSpec.Containers[2].VolumeMounts)
+			require.Len(t, podSpec.Volumes, 1)
+			require.Equal(t, "cluster-ca", podSpec.Volumes[0].ConfigMap.Name)
+			require.Equal(t, []corev1.KeyToPath{{Key: TrustedCABundleFileName, Path: tc.trustedCAFile}}, podSpec.Volumes[0].ConfigMap.Items)
+		})
+	}
+}
+
+func TestGetTrustedCABundlePathErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		labels      map[string]string
+	}{
+		{
+			description: "no GPU nodes",
+		},
+		{
+			description: "GPU node not labeled by NFD",
+			labels:      map[string]string{"nvidia.com/gpu.present": "true"},
+		},
+		{
+			description: "unsupported OS",
+			labels:      map[string]string{"nvidia.com/gpu.present": "true", nfdOSReleaseIDLabelKey: "unknown"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: tc.labels}}
+			n := ClusterPolicyController{
+				ctx: context.Background(),
+				rec: &ClusterPolicyReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(node).Build(),
+				},
+			}
+			_, _, err := getTrustedCABundlePath(n)
+			require.Error(t, err)
+		})
+	}
+}
@@ -18,10 +18,14 @@ package controllers
 
 import (
 	"fmt"
+	"time"
 
 	promcli "github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 )
 
 // OperatorMetrics defines the Prometheus metrics exposed for the
@@ -47,6 +51,12 @@ type OperatorMetrics struct {
 	upgradesFailed           promcli.Gauge
 	upgradesAvailable        promcli.Gauge
 	upgradesPending          promcli.Gauge
+
+	stateReady           *promcli.GaugeVec
+	stateSyncDuration    *promcli.HistogramVec
+	daemonSetDesiredPods *promcli.GaugeVec
+	daemonSetReadyPods   *promcli.GaugeVec
+	daemonSetUpdatedPods *promcli.GaugeVec
 }
 
 const (
@@ -172,6 +182,43 @@ func initOperatorMetrics(n *ClusterPolicyController) *OperatorMetrics {
 				Help: "Total number of nodes on which the gpu operator pod upgrades are pending",
 			},
 		),
+
+		stateReady: promcli.NewGaugeVec(
+			promcli.GaugeOpts{
+				Name: "gpu_operator_state_ready",
+				Help: "1 if the operands of the state are ready, 0 otherwise. Disabled states are not reported",
+			},
+			[]string{"state"},
+		),
+		stateSyncDuration: promcli.NewHistogramVec(
+			promcli.HistogramOpts{
+				Name:    "gpu_operator_state_sync_duration_seconds",
+				Help:    "Duration of the syncs of the state during the ClusterPolicy reconciles",
+				Buckets: promcli.ExponentialBuckets(0.01, 2, 14),
+			},
+			[]string{"state"},
+		),
+		daemonSetDesiredPods: promcli.NewGaugeVec(
+			promcli.GaugeOpts{
+				Name: "gpu_operator_daemonset_desired_pods",
+				Help: "Number of nodes the operand DaemonSet should run a pod on",
+			},
+			[]string{"state", "daemonset"},
+		),
+		daemonSetReadyPods: promcli.NewGaugeVec(
+			promcli.GaugeOpts{
+				Name: "gpu_operator_daemonset_ready_pods",
+				Help: "Number of nodes running a ready pod of the operand DaemonSet",
+			},
+			[]string{"state", "daemonset"},
+		),
+		daemonSetUpdatedPods: promcli.NewGaugeVec(
+			promcli.GaugeOpts{
+				Name: "gpu_operator_daemonset_updated_pods",
+				Help: "Number of nodes running a pod of the latest revision of the operand DaemonSet",
+			},
+			[]string{"state", "daemonset"},
+		),
 	}
 
 	metrics.Registry.MustRegister(
@@ -195,7 +242,58 @@ func initOperatorMetrics(n *ClusterPolicyController) *OperatorMetrics {
 		m.upgradesAvailable,
 		m.upgradesFailed,
 		m.upgradesPending,
+
+		m.stateReady,
+		m.stateSyncDuration,
+		m.daemonSetDesiredPods,
+		m.daemonSetReadyPods,
+		m.daemonSetUpdatedPods,
 	)
 
 	return m
 }
+
+// setStateStatus reports the status of the state, the metrics of a disabled state are removed
+func (m *OperatorMetrics) setStateStatus(state string, status gpuv1.State) {
+	if m == nil {
+		return
+	}
+	if status == gpuv1.Disabled {
+		m.stateReady.DeleteLabelValues(state)
+		m.deleteDaemonSetPods(promcli.Labels{"state": state})
+		return
+	}
+	value := 0.0
+	if status == gpuv1.Ready {
+		value = 1
+	}
+	m.stateReady.WithLabelValues(state).Set(value)
+}
+
+// observeStateSync records the duration of a sync of the state
+func (m *OperatorMetrics) observeStateSync(state string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.stateSyncDuration.WithLabelValues(state).Observe(duration.Seconds())
+}
+
+// setDaemonSetPods reports the pod counts of the DaemonSet of the state
+func (m *OperatorMetrics) setDaemonSetPods(state string, ds *appsv1.DaemonSet) {
+	if m == nil {
+		return
+	}
+	m.daemonSetDesiredPods.WithLabelValues(state, ds.Name).Set(float64(ds.Status.DesiredNumberScheduled))
+	m.daemonSetReadyPods.WithLabelValues(state, ds.Name).Set(float64(ds.Status.NumberReady))
+	m.daemonSetUpdatedPods.WithLabelValues(state, ds.Name).Set(float64(ds.Status.UpdatedNumberScheduled))
+}
+
+// deleteDaemonSetPods removes the pod counts of the DaemonSets matching the labels
+func (m *OperatorMetrics) deleteDaemonSetPods(labels promcli.Labels) {
+	if m == nil {
+		return
+	}
+	m.daemonSetDesiredPods.DeletePartialMatch(labels)
+	m.daemonSetReadyPods.DeletePartialMatch(labels)
+	m.daemonSetUpdatedPods.DeletePartialMatch(labels)
+}
@@ -0,0 +1,115 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+
+	promcli "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// newTestOperatorMetrics returns the per-state metrics without registering them
+func newTestOperatorMetrics() *OperatorMetrics {
+	newGaugeVec := func(name string, labels ...string) *promcli.GaugeVec {
+		return promcli.NewGaugeVec(promcli.GaugeOpts{Name: name}, labels)
+	}
+	return &OperatorMetrics{
+		stateReady:           newGaugeVec("state_ready", "state"),
+		stateSyncDuration:    promcli.NewHistogramVec(promcli.HistogramOpts{Name: "state_sync_duration_seconds"}, []string{"state"}),
+		daemonSetDesiredPods: newGaugeVec("daemonset_desired_pods", "state", "daemonset"),
+		daemonSetReadyPods:   newGaugeVec("daemonset_ready_pods", "state", "daemonset"),
+		daemonSetUpdatedPods: newGaugeVec("daemonset_updated_pods", "state", "daemonset"),
+	}
+}
+
+func gaugeValue(t *testing.T, vec *promcli.GaugeVec, labels ...string) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, vec.WithLabelValues(labels...).Write(m))
+	return m.GetGauge().GetValue()
+}
+
+func seriesCount(c promcli.Collector) int {
+	ch := make(chan promcli.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+	count := 0
+	for range ch {
+		count++
+	}
+	return count
+}
+
+func TestOperatorMetricsStateStatus(t *testing.T) {
+	m := newTestOperatorMetrics()
+
+	m.setStateStatus("state-dcgm-exporter", gpuv1.NotReady)
+	require.Equal(t, 1, seriesCount(m.stateReady))
+	require.Equal(t, 0.0, gaugeValue(t, m.stateReady, "state-dcgm-exporter"))
+
+	m.setStateStatus("state-dcgm-exporter", gpuv1.Ready)
+	require.Equal(t, 1.0, gaugeValue(t, m.stateReady, "state-dcgm-exporter"))
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-dcgm-exporter"},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 3,
+			NumberReady:            2,
+			UpdatedNumberScheduled: 1,
+		},
+	}
+	m.setDaemonSetPods("state-dcgm-exporter", ds)
+	require.Equal(t, 3.0, gaugeValue(t, m.daemonSetDesiredPods, "state-dcgm-exporter", ds.Name))
+	require.Equal(t, 2.0, gaugeValue(t, m.daemonSetReadyPods, "state-dcgm-exporter", ds.Name))
+	require.Equal(t, 1.0, gaugeValue(t, m.daemonSetUpdatedPods, "state-dcgm-exporter", ds.Name))
+
+	// disabling the state removes its series
+	m.setStateStatus("state-dcgm-exporter", gpuv1.Disabled)
+	require.Equal(t, 0, seriesCount(m.stateReady))
+	require.Equal(t, 0, seriesCount(m.daemonSetDesiredPods))
+	require.Equal(t, 0, seriesCount(m.daemonSetReadyPods))
+	require.Equal(t, 0, seriesCount(m.daemonSetUpdatedPods))
+}
+
+func TestOperatorMetricsDeleteDaemonSetPods(t *testing.T) {
+	m := newTestOperatorMetrics()
+
+	for _, name := range []string{"nvidia-container-toolkit-daemonset-containerd", "nvidia-container-toolkit-daemonset-crio"} {
+		m.setDaemonSetPods("state-container-toolkit", &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	require.Equal(t, 2, seriesCount(m.daemonSetReadyPods))
+
+	m.deleteDaemonSetPods(promcli.Labels{"daemonset": "nvidia-container-toolkit-daemonset-crio"})
+	require.Equal(t, 1, seriesCount(m.daemonSetReadyPods))
+}
+
+func TestOperatorMetricsNil(t *testing.T) {
+	var m *OperatorMetrics
+	require.NotPanics(t, func() {
+		m.setStateStatus("state-driver", gpuv1.Ready)
+		m.observeStateSync("state-driver", 0)
+		m.setDaemonSetPods("state-driver", &appsv1.DaemonSet{})
+		m.deleteDaemonSetPods(promcli.Labels{"state": "state-driver"})
+	})
+}
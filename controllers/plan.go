@@ -0,0 +1,142 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/plan"
+	"github.com/NVIDIA/gpu-operator/internal/requeue"
+	"github.com/NVIDIA/gpu-operator/internal/state"
+)
+
+// reconcilePlan renders and transforms the objects of all states of the ClusterPolicy,
+// compares them against the live objects and publishes the resulting changes to a
+// ConfigMap in the operator namespace, without applying them.
+func (r *ClusterPolicyReconciler) reconcilePlan(ctx context.Context, instance *gpuv1.ClusterPolicy) (ctrl.Result, error) {
+	planClient := plan.NewClient(r.Client)
+	planReconciler := r.newPlanReconciler(planClient)
+
+	n, err := r.newClusterPolicyController(ctx, planReconciler, instance)
+	if err != nil {
+		err = fmt.Errorf("failed to initialize ClusterPolicy controller: %w", err)
+	} else {
+		for {
+			_, err = n.step()
+			if err != nil {
+				err = fmt.Errorf("failed to plan %s: %w", n.stateNames[n.idx], err)
+				break
+			}
+			if n.last() {
+				break
+			}
+		}
+	}
+
+	p := planClient.Plan()
+	p.ObservedGeneration = instance.Generation
+	if err != nil {
+		p.Error = err.Error()
+	}
+
+	operatorNamespace := r.OperatorNamespace()
+	if operatorNamespace == "" {
+		return ctrl.Result{}, fmt.Errorf("OPERATOR_NAMESPACE environment variable not set, cannot publish plan")
+	}
+	err = plan.Publish(ctx, r.Client, r.Scheme, instance, operatorNamespace, p)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Log.Info("Plan mode enabled, operand changes published and not applied",
+		"ConfigMap", plan.ConfigMapName("ClusterPolicy", instance.Name), "changes", len(p.Changes))
+	return requeuePlan(r.Requeue, planRequeueKey("ClusterPolicy", instance.Name), p), nil
+}
+
+// newPlanReconciler returns a reconciler which renders the states of the ClusterPolicy as r
+// does, pinning and verifying the operand images alike, but records the changes to planClient
+func (r *ClusterPolicyReconciler) newPlanReconciler(planClient *plan.Client) *ClusterPolicyReconciler {
+	return &ClusterPolicyReconciler{
+		Client:           planClient,
+		Log:              r.Log,
+		Scheme:           r.Scheme,
+		APIReader:        r.APIReader,
+		Assets:           r.Assets,
+		Images:           r.Images,
+		Signatures:       r.Signatures,
+		Requeue:          r.Requeue,
+		conditionUpdater: r.conditionUpdater,
+	}
+}
+
+// reconcilePlan syncs the states of the NVIDIADriver against a client which records the
+// changes instead of applying them, and publishes them to a ConfigMap in the operator namespace.
+func (r *NVIDIADriverReconciler) reconcilePlan(ctx context.Context, instance *nvidiav1alpha1.NVIDIADriver, infoCatalog state.InfoCatalog) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	planClient := plan.NewClient(r.Client)
+	stateManager, err := state.NewManager(ctx, nvidiav1alpha1.NVIDIADriverCRDName, planClient, r.Scheme, nil, r.manifestsLoader())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error creating state manager: %w", err)
+	}
+
+	managerStatus := stateManager.SyncState(ctx, instance, infoCatalog)
+
+	p := planClient.Plan()
+	p.ObservedGeneration = instance.Generation
+	for _, result := range managerStatus.StatesStatus {
+		if result.ErrInfo != nil {
+			p.Error = fmt.Sprintf("failed to plan %s: %v", result.StateName, result.ErrInfo)
+			break
+		}
+	}
+
+	operatorNamespace := os.Getenv("OPERATOR_NAMESPACE")
+	if operatorNamespace == "" {
+		return ctrl.Result{}, fmt.Errorf("OPERATOR_NAMESPACE environment variable not set, cannot publish plan")
+	}
+	err = plan.Publish(ctx, r.Client, r.Scheme, instance, operatorNamespace, p)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.V(consts.LogLevelInfo).Info("Plan mode enabled, driver changes published and not applied",
+		"ConfigMap", plan.ConfigMapName(nvidiav1alpha1.NVIDIADriverCRDName, instance.Name), "changes", len(p.Changes))
+	return requeuePlan(r.Requeue, planRequeueKey(nvidiav1alpha1.NVIDIADriverCRDName, instance.Name), p), nil
+}
+
+// requeuePlan requeues a custom resource in plan mode while its plan has pending actions, so
+// that the published plan follows the changes of the live objects. The interval backs off
+// while the plan stays pending.
+func requeuePlan(backoff *requeue.Backoff, key string, p plan.Plan) ctrl.Result {
+	if !p.Pending() {
+		backoff.Reset(key)
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: backoff.Delay(key, 0)}
+}
+
+// planRequeueKey returns the key of the plan requeues of the custom resource name of the given kind
+func planRequeueKey(kind string, name string) string {
+	return "plan/" + kind + "/" + name
+}
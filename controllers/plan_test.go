@@ -0,0 +1,145 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
+	"github.com/NVIDIA/gpu-operator/internal/plan"
+	"github.com/NVIDIA/gpu-operator/internal/requeue"
+)
+
+func TestRequeuePlan(t *testing.T) {
+	policy := requeue.DefaultPolicy()
+	policy.Jitter = 0
+	backoff := requeue.NewBackoff(policy)
+	pending := plan.Plan{Changes: []plan.Change{{Action: plan.ActionUpdate, Kind: "DaemonSet", Name: "nvidia-driver-daemonset"}}}
+
+	// pending plans are requeued with a growing interval
+	first := requeuePlan(backoff, planRequeueKey("ClusterPolicy", "cluster-policy"), pending)
+	require.Equal(t, policy.InitialDelay, first.RequeueAfter)
+	second := requeuePlan(backoff, planRequeueKey("ClusterPolicy", "cluster-policy"), pending)
+	require.Greater(t, second.RequeueAfter, first.RequeueAfter)
+
+	// plans which could not be completed are requeued
+	failed := requeuePlan(backoff, planRequeueKey("NVIDIADriver", "default"), plan.Plan{Error: "failed to plan state-driver"})
+	require.Equal(t, policy.InitialDelay, failed.RequeueAfter)
+
+	// an empty plan is not requeued and resets the interval
+	require.Zero(t, requeuePlan(backoff, planRequeueKey("ClusterPolicy", "cluster-policy"), plan.Plan{}).RequeueAfter)
+	require.Equal(t, first, requeuePlan(backoff, planRequeueKey("ClusterPolicy", "cluster-policy"), pending))
+}
+
+func TestForgetPlanRequeueOfDeletedCR(t *testing.T) {
+	policy := requeue.DefaultPolicy()
+	policy.Jitter = 0
+	backoff := requeue.NewBackoff(policy)
+	pending := plan.Plan{Changes: []plan.Change{{Action: plan.ActionUpdate, Kind: "DaemonSet", Name: "nvidia-driver-daemonset"}}}
+	s := runtime.NewScheme()
+	require.NoError(t, gpuv1.AddToScheme(s))
+	require.NoError(t, nvidiav1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+
+	testCases := []struct {
+		description string
+		key         string
+		reconciler  reconcile.Reconciler
+		request     types.NamespacedName
+	}{
+		{
+			description: "ClusterPolicy",
+			key:         planRequeueKey("ClusterPolicy", "cluster-policy"),
+			reconciler:  &ClusterPolicyReconciler{Client: c, Log: ctrl.Log.WithName("test"), Requeue: backoff},
+			request:     types.NamespacedName{Name: "cluster-policy"},
+		},
+		{
+			description: "NVIDIADriver",
+			key:         planRequeueKey(nvidiav1alpha1.NVIDIADriverCRDName, "default"),
+			reconciler:  &NVIDIADriverReconciler{Client: c, Requeue: backoff},
+			request:     types.NamespacedName{Name: "default"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			requeuePlan(backoff, tc.key, pending)
+			require.Greater(t, requeuePlan(backoff, tc.key, pending).RequeueAfter, policy.InitialDelay)
+
+			// the deleted custom resource starts over from the initial delay once recreated
+			_, err := tc.reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: tc.request})
+			require.NoError(t, err)
+			require.Equal(t, policy.InitialDelay, requeuePlan(backoff, tc.key, pending).RequeueAfter)
+		})
+	}
+}
+
+func TestPlanPinsImageDigests(t *testing.T) {
+	namespace := "test-operator"
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	lookups := 0
+	r := &ClusterPolicyReconciler{
+		Client: c,
+		Log:    ctrl.Log.WithName("test"),
+		Scheme: scheme.Scheme,
+		Images: registry.NewDigestResolver(func(_ context.Context, _ string) (string, error) {
+			lookups++
+			return testImageDigest, nil
+		}),
+		Requeue: requeue.NewBackoff(requeue.DefaultPolicy()),
+	}
+
+	planClient := plan.NewClient(c)
+	n := ClusterPolicyController{
+		ctx: context.Background(),
+		singleton: &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "cp-uid"},
+			Spec:       gpuv1.ClusterPolicySpec{Operator: gpuv1.OperatorSpec{PinImageDigests: boolTrue}},
+		},
+		operatorNamespace: namespace,
+		stateNames:        []string{"state-test"},
+		rec:               r.newPlanReconciler(planClient),
+	}
+
+	obj := newTestDriftDaemonSet(namespace, "nvcr.io/nvidia/test:v1")
+	_, err := createOrUpdateDaemonSet(obj, n, nil)
+	require.NoError(t, err)
+
+	// the planned DaemonSet runs the pinned images, as it would once applied
+	require.Equal(t, 1, lookups)
+	require.Equal(t, "nvcr.io/nvidia/test:v1@"+testImageDigest, obj.Spec.Template.Spec.Containers[0].Image)
+	require.Equal(t, []plan.Change{{Action: plan.ActionCreate, Kind: "DaemonSet", Namespace: namespace, Name: obj.Name}}, planClient.Plan().Changes)
+
+	// the DaemonSet is not created
+	err = c.Get(context.Background(), client.ObjectKeyFromObject(obj), &appsv1.DaemonSet{})
+	require.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,54 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/patch"
+)
+
+// applyPodTemplatePatch applies the pod template patch of the component spec of the DaemonSet, if any.
+// The patched pod template must still be selected by the DaemonSet selector, which is immutable.
+func applyPodTemplatePatch(obj *appsv1.DaemonSet, spec *gpuv1.ClusterPolicySpec) error {
+	component := getComponentSpec(spec, obj.Name)
+	if component == nil || component.PodTemplatePatch == nil {
+		return nil
+	}
+	p := component.PodTemplatePatch
+
+	template := obj.Spec.Template.DeepCopy()
+	if err := patch.PodTemplate(template, p); err != nil {
+		return fmt.Errorf("failed to apply podTemplatePatch to DaemonSet %s: %w", obj.Name, err)
+	}
+	if obj.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(obj.Spec.Selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector of DaemonSet %s: %w", obj.Name, err)
+		}
+		if !selector.Matches(labels.Set(template.Labels)) {
+			return fmt.Errorf("podTemplatePatch of DaemonSet %s changes the pod labels selected by the DaemonSet", obj.Name)
+		}
+	}
+	obj.Spec.Template = *template
+	return nil
+}
@@ -0,0 +1,109 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func TestApplyPodTemplatePatch(t *testing.T) {
+	newDaemonSet := func() *appsv1.DaemonSet {
+		labels := map[string]string{"app": "nvidia-device-plugin-daemonset"}
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset"},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "nvidia-device-plugin", Image: "nvcr.io/nvidia/k8s-device-plugin:v0.15.0"}},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		description string
+		patch       *gpuv1.PodTemplatePatch
+		validate    func(t *testing.T, ds *appsv1.DaemonSet)
+		err         bool
+	}{
+		{
+			description: "no patch",
+			validate: func(t *testing.T, ds *appsv1.DaemonSet) {
+				require.Equal(t, newDaemonSet(), ds)
+			},
+		},
+		{
+			description: "strategic merge patch adds volumes",
+			patch: &gpuv1.PodTemplatePatch{
+				Patch: `
+spec:
+  volumes:
+  - name: extra
+    hostPath:
+      path: /etc/extra
+  containers:
+  - name: nvidia-device-plugin
+    volumeMounts:
+    - name: extra
+      mountPath: /etc/extra
+`,
+			},
+			validate: func(t *testing.T, ds *appsv1.DaemonSet) {
+				require.Len(t, ds.Spec.Template.Spec.Volumes, 1)
+				require.Len(t, ds.Spec.Template.Spec.Containers, 1)
+				require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.15.0", ds.Spec.Template.Spec.Containers[0].Image)
+				require.Equal(t, "/etc/extra", ds.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath)
+				require.NotEqual(t, getDaemonsetHash(newDaemonSet()), getDaemonsetHash(ds))
+			},
+		},
+		{
+			description: "patch changing the selected pod labels",
+			patch: &gpuv1.PodTemplatePatch{
+				Type:  gpuv1.PodTemplatePatchJSON,
+				Patch: `[{"op": "replace", "path": "/metadata/labels/app", "value": "other"}]`,
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec := &gpuv1.ClusterPolicySpec{
+				DevicePlugin: gpuv1.DevicePluginSpec{PodTemplatePatch: tc.patch},
+			}
+			ds := newDaemonSet()
+			err := applyPodTemplatePatch(ds, spec)
+			if tc.err {
+				require.Error(t, err)
+				require.Equal(t, newDaemonSet(), ds)
+				return
+			}
+			require.NoError(t, err)
+			tc.validate(t, ds)
+		})
+	}
+}
@@ -17,10 +17,8 @@
 package controllers
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"regexp"
-	"sort"
 	"strings"
 
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -32,8 +30,11 @@ import (
 
 	secv1 "github.com/openshift/api/security/v1"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/NVIDIA/gpu-operator/internal/assets"
 )
 
 const (
@@ -63,48 +64,24 @@ type Resources struct {
 	PrometheusRule             promv1.PrometheusRule
 }
 
-func filePathWalkDir(n *ClusterPolicyController, root string) ([]string, error) {
-	var files []string
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			n.rec.Log.V(1).Info("error in filepath.Walk on %s: %v", root, err)
-			return nil
-		}
-		if !info.IsDir() {
-			files = append(files, path)
-		}
-		return nil
-	})
-	return files, err
-}
-
-func getAssetsFrom(n *ClusterPolicyController, path string, openshiftVersion string) []assetsFromFile {
+// getAssetsFrom returns the manifests of files, without the OpenShift specific ones if the cluster is not OpenShift
+func getAssetsFrom(files []assets.File, openshiftVersion string) []assetsFromFile {
 	manifests := []assetsFromFile{}
-	files, err := filePathWalkDir(n, path)
-	if err != nil {
-		panic(err)
-	}
-	sort.Strings(files)
 	for _, file := range files {
-		if strings.Contains(file, "openshift") && openshiftVersion == "" {
+		if strings.Contains(file.Name, "openshift") && openshiftVersion == "" {
 			continue
 		}
-
-		buffer, err := os.ReadFile(file)
-		if err != nil {
-			panic(err)
-		}
-		manifests = append(manifests, buffer)
+		manifests = append(manifests, file.Data)
 	}
 	return manifests
 }
 
-func addResourcesControls(n *ClusterPolicyController, path string) (Resources, controlFunc) {
+func addResourcesControls(n *ClusterPolicyController, state string, files []assets.File) (Resources, controlFunc, error) {
 	res := Resources{}
 	ctrl := controlFunc{}
 
-	n.rec.Log.Info("Getting assets from: ", "path:", path)
-	manifests := getAssetsFrom(n, path, n.openshift)
+	n.rec.Log.Info("Getting assets of", "state", state)
+	manifests := getAssetsFrom(files, n.openshift)
 
 	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme,
 		scheme.Scheme, json.SerializerOptions{Yaml: true, Pretty: false, Strict: false})
@@ -113,84 +90,76 @@ func addResourcesControls(n *ClusterPolicyController, path string) (Resources, c
 	for _, m := range manifests {
 		kind := reg.FindString(string(m))
 		slce := strings.Split(kind, ":")
+		if len(slce) < 2 {
+			return res, ctrl, fmt.Errorf("no kind found in a manifest of %s", state)
+		}
 		kind = strings.TrimSpace(slce[1])
 
-		n.rec.Log.V(1).Info("Looking for ", "Kind", kind, "in path:", path)
+		n.rec.Log.V(1).Info("Looking for ", "Kind", kind, "in state:", state)
 
+		var err error
+		decode := func(obj runtime.Object) error {
+			_, _, err := s.Decode(m, nil, obj)
+			return err
+		}
 		switch kind {
 		case "ServiceAccount":
-			_, _, err := s.Decode(m, nil, &res.ServiceAccount)
-			panicIfError(err)
+			err = decode(&res.ServiceAccount)
 			ctrl = append(ctrl, ServiceAccount)
 		case "Role":
-			_, _, err := s.Decode(m, nil, &res.Role)
-			panicIfError(err)
+			err = decode(&res.Role)
 			ctrl = append(ctrl, Role)
 		case "RoleBinding":
-			_, _, err := s.Decode(m, nil, &res.RoleBinding)
-			panicIfError(err)
+			err = decode(&res.RoleBinding)
 			ctrl = append(ctrl, RoleBinding)
 		case "ClusterRole":
-			_, _, err := s.Decode(m, nil, &res.ClusterRole)
-			panicIfError(err)
+			err = decode(&res.ClusterRole)
 			ctrl = append(ctrl, ClusterRole)
 		case "ClusterRoleBinding":
-			_, _, err := s.Decode(m, nil, &res.ClusterRoleBinding)
-			panicIfError(err)
+			err = decode(&res.ClusterRoleBinding)
 			ctrl = append(ctrl, ClusterRoleBinding)
 		case "ConfigMap":
 			cm := corev1.ConfigMap{}
-			_, _, err := s.Decode(m, nil, &cm)
-			panicIfError(err)
+			err = decode(&cm)
 			res.ConfigMaps = append(res.ConfigMaps, cm)
 			// only add the ctrl function when the first ConfigMap is added for this component
 			if len(res.ConfigMaps) == 1 {
 				ctrl = append(ctrl, ConfigMaps)
 			}
 		case "DaemonSet":
-			_, _, err := s.Decode(m, nil, &res.DaemonSet)
-			panicIfError(err)
+			err = decode(&res.DaemonSet)
 			ctrl = append(ctrl, DaemonSet)
 		case "Deployment":
-			_, _, err := s.Decode(m, nil, &res.Deployment)
-			panicIfError(err)
+			err = decode(&res.Deployment)
 			ctrl = append(ctrl, Deployment)
 		case "Service":
-			_, _, err := s.Decode(m, nil, &res.Service)
-			panicIfError(err)
+			err = decode(&res.Service)
 			ctrl = append(ctrl, Service)
 		case "ServiceMonitor":
-			_, _, err := s.Decode(m, nil, &res.ServiceMonitor)
-			panicIfError(err)
+			err = decode(&res.ServiceMonitor)
 			ctrl = append(ctrl, ServiceMonitor)
 		case "SecurityContextConstraints":
-			_, _, err := s.Decode(m, nil, &res.SecurityContextConstraints)
-			panicIfError(err)
+			err = decode(&res.SecurityContextConstraints)
 			ctrl = append(ctrl, SecurityContextConstraints)
 		case "RuntimeClass":
 			rt := nodev1.RuntimeClass{}
-			_, _, err := s.Decode(m, nil, &rt)
-			panicIfError(err)
+			err = decode(&rt)
 			res.RuntimeClasses = append(res.RuntimeClasses, rt)
 			// only add the ctrl function when the first RuntimeClass is added
 			if len(res.RuntimeClasses) == 1 {
 				ctrl = append(ctrl, RuntimeClasses)
 			}
 		case "PrometheusRule":
-			_, _, err := s.Decode(m, nil, &res.PrometheusRule)
-			panicIfError(err)
+			err = decode(&res.PrometheusRule)
 			ctrl = append(ctrl, PrometheusRule)
 		default:
 			n.rec.Log.Info("Unknown Resource", "Manifest", m, "Kind", kind)
 		}
+		if err != nil {
+			return res, ctrl, fmt.Errorf("failed to decode %s manifest of %s: %w", kind, state, err)
+		}
 
 	}
 
-	return res, ctrl
-}
-
-func panicIfError(err error) {
-	if err != nil {
-		panic(err)
-	}
+	return res, ctrl, nil
 }
@@ -0,0 +1,114 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
+)
+
+const (
+	// runtimeLabelKey is set by the operator on the GPU nodes to the container runtime detected on
+	// the node, and on the DaemonSets deployed to the nodes of a given container runtime
+	runtimeLabelKey = "nvidia.com/gpu-operator.container-runtime"
+)
+
+// runtimeDaemonSets are the DaemonSets configured for the container runtime of the nodes,
+// which are deployed once per container runtime in clusters running more than one
+var runtimeDaemonSets = map[string]bool{
+	"nvidia-container-toolkit-daemonset": true,
+	"nvidia-operator-validator":          true,
+}
+
+// updateRuntimeLabel sets the container runtime label of a node to the given runtime,
+// and returns true if the labels were changed
+func updateRuntimeLabel(nodeLabels map[string]string, runtime gpuv1.Runtime) bool {
+	return updateNodeLabel(nodeLabels, runtimeLabelKey, runtime.String())
+}
+
+// isRuntimeLabelOutdated returns true if the container runtime label of a GPU node does not
+// match the container runtime reported in the node status
+func isRuntimeLabelOutdated(node *corev1.Node) bool {
+	if node == nil || !hasCommonGPULabel(node.Labels) {
+		return false
+	}
+	runtime, _ := getRuntimeString(*node)
+	return node.Labels[runtimeLabelKey] != runtime.String()
+}
+
+// getRuntimePools returns the container runtimes, other than the default runtime of the cluster,
+// which the DaemonSet dsName is deployed for separately
+func getRuntimePools(n ClusterPolicyController, dsName string) []gpuv1.Runtime {
+	if !runtimeDaemonSets[dsName] {
+		return nil
+	}
+	runtimes := []gpuv1.Runtime{}
+	for _, runtime := range n.runtimes {
+		if runtime != n.runtime {
+			runtimes = append(runtimes, runtime)
+		}
+	}
+	return runtimes
+}
+
+// createOrUpdateRuntimeDaemonSets deploys the DaemonSet obj configured for the default container runtime
+// on all the nodes but the ones running one of the given runtimes, and once per given runtime.
+// The optional poolTransform, e.g. of a node pool, is applied to each of them beforehand.
+func createOrUpdateRuntimeDaemonSets(obj *appsv1.DaemonSet, n ClusterPolicyController, runtimes []gpuv1.Runtime, poolTransform func(*appsv1.DaemonSet)) (gpuv1.State, error) {
+	if len(runtimes) == 0 {
+		return createOrUpdateDaemonSet(obj, n, poolTransform)
+	}
+
+	pools := make([]string, 0, len(runtimes))
+	for _, runtime := range runtimes {
+		pools = append(pools, runtime.String())
+	}
+
+	overallState, err := createOrUpdateDaemonSet(obj.DeepCopy(), n, func(ds *appsv1.DaemonSet) {
+		if poolTransform != nil {
+			poolTransform(ds)
+		}
+		excludeNodes(ds, runtimeLabelKey, pools)
+	})
+	if err != nil {
+		return gpuv1.NotReady, err
+	}
+
+	for _, runtime := range runtimes {
+		// render the DaemonSet of the runtime pool for its container runtime
+		pool := runtime.String()
+		poolCtrl := n
+		poolCtrl.ctx = tracing.WithAttributes(n.ctx, tracing.Runtime(pool))
+		poolCtrl.runtime = runtime
+		state, err := createOrUpdateDaemonSet(obj.DeepCopy(), poolCtrl, func(ds *appsv1.DaemonSet) {
+			if poolTransform != nil {
+				poolTransform(ds)
+			}
+			transformPoolDaemonSet(ds, runtimeLabelKey, pool)
+		})
+		if err != nil {
+			return gpuv1.NotReady, err
+		}
+		if state != gpuv1.Ready {
+			overallState = state
+		}
+	}
+	return overallState, nil
+}
@@ -0,0 +1,219 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// TestToolkitRuntimePools tests that the container-toolkit is deployed once per container runtime
+// of the GPU nodes, and that the DaemonSets of runtimes no longer detected are cleaned up
+func TestToolkitRuntimePools(t *testing.T) {
+	ctx := context.Background()
+
+	cp := getDevicePluginTestInput("default")
+	cp.Spec.Toolkit.Repository = "nvcr.io/nvidia/k8s"
+	cp.Spec.Toolkit.Image = "container-toolkit"
+	cp.Spec.Toolkit.Version = "v1.14.0-ubi8"
+	err := updateClusterPolicy(&clusterPolicyController, cp)
+	require.NoError(t, err)
+
+	runtime, runtimes := clusterPolicyController.runtime, clusterPolicyController.runtimes
+	defer func() {
+		clusterPolicyController.runtime, clusterPolicyController.runtimes = runtime, runtimes
+	}()
+	clusterPolicyController.runtime = gpuv1.Containerd
+	clusterPolicyController.runtimes = []gpuv1.Runtime{gpuv1.Containerd, gpuv1.CRIO}
+
+	addTestState(t, "state-container-toolkit")
+	_, err = clusterPolicyController.step()
+	require.NoError(t, err)
+
+	list := &appsv1.DaemonSetList{}
+	err = clusterPolicyController.rec.Client.List(ctx, list, client.MatchingLabels{"app": "nvidia-container-toolkit-daemonset"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+
+	base := list.Items[0]
+	require.Equal(t, "nvidia-container-toolkit-daemonset", base.Name)
+	require.Contains(t, base.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "RUNTIME", Value: "containerd"})
+	require.Contains(t, base.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "CONTAINERD_SOCKET", Value: "/runtime/sock-dir/containerd.sock"})
+	terms := base.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	require.Contains(t, terms[0].MatchExpressions, corev1.NodeSelectorRequirement{
+		Key:      runtimeLabelKey,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   []string{"crio"},
+	})
+
+	pool := list.Items[1]
+	require.Equal(t, "nvidia-container-toolkit-daemonset-crio", pool.Name)
+	require.Equal(t, "crio", pool.Labels[runtimeLabelKey])
+	require.Equal(t, "crio", pool.Spec.Selector.MatchLabels[runtimeLabelKey])
+	require.Equal(t, "crio", pool.Spec.Template.Spec.NodeSelector[runtimeLabelKey])
+	toolkit := pool.Spec.Template.Spec.Containers[0]
+	require.Contains(t, toolkit.Env, corev1.EnvVar{Name: "RUNTIME", Value: "crio"})
+	require.Contains(t, toolkit.Env, corev1.EnvVar{Name: "CRIO_CONFIG", Value: "/runtime/config-dir/99-nvidia.conf"})
+	for _, env := range toolkit.Env {
+		require.NotEqual(t, "CONTAINERD_RUNTIME_CLASS", env.Name)
+	}
+	require.Contains(t, toolkit.VolumeMounts, corev1.VolumeMount{Name: "crio-config", MountPath: DefaultRuntimeConfigTargetDir})
+
+	// the DaemonSet of a container runtime is deleted once no GPU node runs it anymore
+	clusterPolicyController.runtimes = []gpuv1.Runtime{gpuv1.Containerd}
+	clusterPolicyController.idx--
+	_, err = clusterPolicyController.step()
+	require.NoError(t, err)
+
+	err = clusterPolicyController.rec.Client.List(ctx, list, client.MatchingLabels{"app": "nvidia-container-toolkit-daemonset"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "nvidia-container-toolkit-daemonset", list.Items[0].Name)
+	require.Nil(t, list.Items[0].Spec.Template.Spec.Affinity)
+
+	// cleanup by deleting all kubernetes objects
+	err = removeState(&clusterPolicyController, clusterPolicyController.idx-1)
+	require.NoError(t, err)
+	clusterPolicyController.idx--
+}
+
+// TestNodePoolRuntimePools tests that a DaemonSet overridden by node pools and configured for the
+// container runtime is deployed once per runtime in each node pool, and that the DaemonSets of
+// removed node pools and runtimes are cleaned up
+func TestNodePoolRuntimePools(t *testing.T) {
+	ctx := context.Background()
+
+	// the device-plugin is configured per runtime for the purpose of the test
+	runtimeDaemonSets["nvidia-device-plugin-daemonset"] = true
+	defer delete(runtimeDaemonSets, "nvidia-device-plugin-daemonset")
+
+	cp := getDevicePluginTestInput("default")
+	cp.Spec.NodePoolOverrides = []gpuv1.NodePoolOverride{
+		{
+			Name:         "a100",
+			NodeSelector: map[string]string{"nvidia.com/gpu.product": "A100-SXM4-80GB"},
+			DevicePlugin: &gpuv1.DevicePluginSpec{Version: "v0.13.0-ubi8"},
+		},
+	}
+	err := updateClusterPolicy(&clusterPolicyController, cp)
+	require.NoError(t, err)
+
+	runtime, runtimes := clusterPolicyController.runtime, clusterPolicyController.runtimes
+	defer func() {
+		clusterPolicyController.runtime, clusterPolicyController.runtimes = runtime, runtimes
+	}()
+	clusterPolicyController.runtime = gpuv1.Containerd
+	clusterPolicyController.runtimes = []gpuv1.Runtime{gpuv1.Containerd, gpuv1.CRIO}
+
+	addTestState(t, devicePluginState)
+	_, err = clusterPolicyController.step()
+	require.NoError(t, err)
+
+	list := &appsv1.DaemonSetList{}
+	err = clusterPolicyController.rec.Client.List(ctx, list, client.MatchingLabels{"app": "nvidia-device-plugin-daemonset"})
+	require.NoError(t, err)
+	daemonSets := map[string]appsv1.DaemonSet{}
+	for _, ds := range list.Items {
+		daemonSets[ds.Name] = ds
+	}
+	require.Len(t, daemonSets, 4)
+
+	base := daemonSets["nvidia-device-plugin-daemonset"]
+	terms := base.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	require.Equal(t, []corev1.NodeSelectorRequirement{
+		{Key: nodePoolLabelKey, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a100"}},
+		{Key: runtimeLabelKey, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"crio"}},
+	}, terms[0].MatchExpressions[len(terms[0].MatchExpressions)-2:])
+	require.Equal(t, "nvidia", *base.Spec.Template.Spec.RuntimeClassName)
+
+	crio := daemonSets["nvidia-device-plugin-daemonset-crio"]
+	require.Equal(t, "crio", crio.Spec.Template.Spec.NodeSelector[runtimeLabelKey])
+	require.NotContains(t, crio.Labels, nodePoolLabelKey)
+	require.Nil(t, crio.Spec.Template.Spec.RuntimeClassName)
+
+	pool := daemonSets["nvidia-device-plugin-daemonset-a100"]
+	require.Equal(t, "a100", pool.Spec.Template.Spec.NodeSelector[nodePoolLabelKey])
+	require.NotContains(t, pool.Spec.Template.Spec.NodeSelector, runtimeLabelKey)
+	require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.13.0-ubi8", pool.Spec.Template.Spec.Containers[0].Image)
+
+	poolCrio := daemonSets["nvidia-device-plugin-daemonset-a100-crio"]
+	require.Equal(t, map[string]string{nodePoolLabelKey: "a100", runtimeLabelKey: "crio"}, map[string]string{
+		nodePoolLabelKey: poolCrio.Spec.Template.Spec.NodeSelector[nodePoolLabelKey],
+		runtimeLabelKey:  poolCrio.Spec.Template.Spec.NodeSelector[runtimeLabelKey],
+	})
+	require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.13.0-ubi8", poolCrio.Spec.Template.Spec.Containers[0].Image)
+	require.Nil(t, poolCrio.Spec.Template.Spec.RuntimeClassName)
+
+	// the DaemonSets of a container runtime are deleted in every node pool once no GPU node runs it anymore
+	clusterPolicyController.runtimes = []gpuv1.Runtime{gpuv1.Containerd}
+	clusterPolicyController.idx--
+	_, err = clusterPolicyController.step()
+	require.NoError(t, err)
+
+	err = clusterPolicyController.rec.Client.List(ctx, list, client.MatchingLabels{"app": "nvidia-device-plugin-daemonset"})
+	require.NoError(t, err)
+	names := []string{}
+	for _, ds := range list.Items {
+		names = append(names, ds.Name)
+	}
+	require.ElementsMatch(t, []string{"nvidia-device-plugin-daemonset", "nvidia-device-plugin-daemonset-a100"}, names)
+
+	// cleanup by deleting all kubernetes objects
+	err = removeState(&clusterPolicyController, clusterPolicyController.idx-1)
+	require.NoError(t, err)
+	clusterPolicyController.idx--
+}
+
+func TestIsRuntimeLabelOutdated(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{commonGPULabelKey: "true"}},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{ContainerRuntimeVersion: "cri-o://1.28.1"},
+		},
+	}
+	require.True(t, isRuntimeLabelOutdated(node))
+
+	require.True(t, updateRuntimeLabel(node.Labels, gpuv1.CRIO))
+	require.Equal(t, "crio", node.Labels[runtimeLabelKey])
+	require.False(t, isRuntimeLabelOutdated(node))
+	require.False(t, updateRuntimeLabel(node.Labels, gpuv1.CRIO))
+
+	node.Status.NodeInfo.ContainerRuntimeVersion = "containerd://1.7.2"
+	require.True(t, isRuntimeLabelOutdated(node))
+
+	// the label is removed from nodes running an unrecognized runtime
+	node.Status.NodeInfo.ContainerRuntimeVersion = "unknown://1.0"
+	require.True(t, isRuntimeLabelOutdated(node))
+	require.True(t, updateRuntimeLabel(node.Labels, ""))
+	require.NotContains(t, node.Labels, runtimeLabelKey)
+	require.False(t, isRuntimeLabelOutdated(node))
+
+	// the label of nodes without GPUs is not tracked
+	node.Labels[commonGPULabelKey] = "false"
+	node.Status.NodeInfo.ContainerRuntimeVersion = "containerd://1.7.2"
+	require.False(t, isRuntimeLabelOutdated(node))
+}
@@ -0,0 +1,201 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+const (
+	// defaultStateRequeueInterval is the requeue interval used for states not ready
+	defaultStateRequeueInterval = 5 * time.Second
+)
+
+// stateRequeueIntervals overrides the requeue interval of states which take
+// longer to become ready, e.g. driver installation which may need to compile
+// kernel modules.
+var stateRequeueIntervals = map[string]time.Duration{
+	"state-driver":       15 * time.Second,
+	"state-vgpu-manager": 15 * time.Second,
+}
+
+// stateResult is the outcome of syncing a single state
+type stateResult struct {
+	idx    int
+	name   string
+	status gpuv1.State
+	err    error
+	// skipped is set when the state was not synced as one of its dependencies failed
+	skipped bool
+	// deferred is set when the state was not synced as it is not due yet
+	deferred bool
+}
+
+// getStateRequeueInterval returns the interval after which a state which is not ready is synced again
+func getStateRequeueInterval(name string) time.Duration {
+	if interval, ok := stateRequeueIntervals[name]; ok {
+		return interval
+	}
+	return defaultStateRequeueInterval
+}
+
+// getStateDependencies returns the indices of the loaded states the state at the given index depends on
+func (n ClusterPolicyController) getStateDependencies(idx int) []int {
+	deps := []int{}
+	if idx >= len(n.dependencies) {
+		return deps
+	}
+	for _, dep := range n.dependencies[idx] {
+		for i, name := range n.stateNames {
+			if name == dep && i != idx {
+				deps = append(deps, i)
+			}
+		}
+	}
+	return deps
+}
+
+// syncStates syncs all states, starting each one as soon as the states it depends on
+// are synced. States which depend on a failed state are skipped, states which are not
+// due are deferred and reported not ready without being synced, all states are due if
+// due is nil. The results are returned in the order the states were added.
+func (n *ClusterPolicyController) syncStates(due func(name string) bool) []stateResult {
+	total := len(n.stateNames)
+	results := make([]stateResult, total)
+
+	pending := make([]int, total)
+	dependents := make([][]int, total)
+	for idx := range n.stateNames {
+		deps := n.getStateDependencies(idx)
+		pending[idx] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], idx)
+		}
+	}
+
+	done := make(chan stateResult)
+	running := 0
+	started := make([]bool, total)
+	var start func(idx int)
+	start = func(idx int) {
+		if started[idx] {
+			return
+		}
+		started[idx] = true
+		if due != nil && !due(n.stateNames[idx]) {
+			// a deferred state is still not ready, which does not block its dependents
+			results[idx] = stateResult{idx: idx, name: n.stateNames[idx], status: gpuv1.NotReady, deferred: true}
+			for _, dependent := range dependents[idx] {
+				pending[dependent]--
+				if pending[dependent] == 0 && !results[dependent].skipped {
+					start(dependent)
+				}
+			}
+			return
+		}
+		running++
+		go func() {
+			status, err := n.syncState(idx)
+			done <- stateResult{idx: idx, name: n.stateNames[idx], status: status, err: err}
+		}()
+	}
+	// skip marks a state and all the states depending on it as skipped
+	var skip func(idx int, reason string)
+	skip = func(idx int, reason string) {
+		if results[idx].skipped {
+			return
+		}
+		results[idx] = stateResult{
+			idx:     idx,
+			name:    n.stateNames[idx],
+			status:  gpuv1.NotReady,
+			skipped: true,
+		}
+		n.rec.Log.Info("Skipping state, dependency failed", "state", n.stateNames[idx], "dependency", reason)
+		for _, dependent := range dependents[idx] {
+			skip(dependent, n.stateNames[idx])
+		}
+	}
+
+	for idx := range n.stateNames {
+		if pending[idx] == 0 {
+			start(idx)
+		}
+	}
+	for running > 0 {
+		res := <-done
+		running--
+		results[res.idx] = res
+		for _, dependent := range dependents[res.idx] {
+			if res.err != nil {
+				skip(dependent, res.name)
+				continue
+			}
+			pending[dependent]--
+			if pending[dependent] == 0 && !results[dependent].skipped {
+				start(dependent)
+			}
+		}
+	}
+
+	// states left unvisited are part of a dependency cycle
+	for idx := range results {
+		if results[idx].name == "" {
+			results[idx] = stateResult{
+				idx:    idx,
+				name:   n.stateNames[idx],
+				status: gpuv1.NotReady,
+				err:    fmt.Errorf("dependency cycle detected for state %s", n.stateNames[idx]),
+			}
+		}
+	}
+
+	n.idx = total
+	return results
+}
+
+// getStateDueTimes returns the times at which the states not ready of the ClusterPolicy are
+// synced again. The due times are dropped when the ClusterPolicy changes, so that all the
+// states are synced with its new spec.
+func (r *ClusterPolicyReconciler) getStateDueTimes(instance *gpuv1.ClusterPolicy) map[string]time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := fmt.Sprintf("%s/%d", instance.Name, instance.Generation)
+	if r.stateDueKey != key {
+		r.stateDue = map[string]time.Time{}
+		r.stateDueKey = key
+	}
+	return maps.Clone(r.stateDue)
+}
+
+// setStateDue records the time at which a state is synced again, a zero time makes the state due
+func (r *ClusterPolicyReconciler) setStateDue(name string, due time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if due.IsZero() {
+		delete(r.stateDue, name)
+		return
+	}
+	if r.stateDue == nil {
+		r.stateDue = map[string]time.Time{}
+	}
+	r.stateDue[name] = due
+}
@@ -0,0 +1,234 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// stateRecorder records the order in which fake states are started and finished
+type stateRecorder struct {
+	sync.Mutex
+	started  map[string]int
+	finished map[string]int
+	seq      int
+}
+
+func (r *stateRecorder) mark(m map[string]int, name string) {
+	r.Lock()
+	defer r.Unlock()
+	r.seq++
+	m[name] = r.seq
+}
+
+func newStateGraphTestController(names []string, syncFn func(name string) (gpuv1.State, error)) ClusterPolicyController {
+	n := ClusterPolicyController{
+		ctx:       context.Background(),
+		singleton: &gpuv1.ClusterPolicy{},
+		rec:       &ClusterPolicyReconciler{Log: ctrl.Log.WithName("test")},
+	}
+	dependencies := map[string][]string{}
+	for _, state := range clusterPolicyStates {
+		dependencies[state.name] = state.dependsOn
+	}
+	for _, name := range names {
+		name := name
+		n.stateNames = append(n.stateNames, name)
+		n.dependencies = append(n.dependencies, dependencies[name])
+		n.resources = append(n.resources, Resources{})
+		n.controls = append(n.controls, controlFunc{
+			func(ClusterPolicyController) (gpuv1.State, error) {
+				return syncFn(name)
+			},
+		})
+	}
+	return n
+}
+
+func TestSyncStatesOrder(t *testing.T) {
+	names := []string{
+		"pre-requisites",
+		"state-operator-metrics",
+		"state-driver",
+		"state-container-toolkit",
+		"state-operator-validation",
+		"state-device-plugin",
+		"state-mps-control-daemon",
+		"state-dcgm",
+		"state-dcgm-exporter",
+		"gpu-feature-discovery",
+	}
+	rec := &stateRecorder{started: map[string]int{}, finished: map[string]int{}}
+
+	// pre-requisites and state-operator-metrics have no dependencies, each of them
+	// only completes once the other one is started, which requires concurrent syncs
+	barrier := map[string]chan struct{}{
+		"pre-requisites":         make(chan struct{}),
+		"state-operator-metrics": make(chan struct{}),
+	}
+	peer := map[string]string{
+		"pre-requisites":         "state-operator-metrics",
+		"state-operator-metrics": "pre-requisites",
+	}
+
+	n := newStateGraphTestController(names, func(name string) (gpuv1.State, error) {
+		rec.mark(rec.started, name)
+		if ch, ok := barrier[name]; ok {
+			close(ch)
+			select {
+			case <-barrier[peer[name]]:
+			case <-time.After(5 * time.Second):
+				return gpuv1.NotReady, fmt.Errorf("state %s was not synced concurrently with %s", peer[name], name)
+			}
+		}
+		rec.mark(rec.finished, name)
+		if name == "state-dcgm" {
+			return gpuv1.NotReady, nil
+		}
+		return gpuv1.Ready, nil
+	})
+
+	results := n.syncStates(nil)
+	require.Len(t, results, len(names))
+	require.True(t, n.last())
+
+	for i, res := range results {
+		require.Equal(t, i, res.idx)
+		require.Equal(t, names[i], res.name)
+		require.NoError(t, res.err)
+		require.False(t, res.skipped)
+		for _, dep := range n.dependencies[i] {
+			if _, ok := rec.finished[dep]; !ok {
+				continue
+			}
+			require.Less(t, rec.finished[dep], rec.started[res.name],
+				"state %s started before its dependency %s finished", res.name, dep)
+		}
+	}
+
+	// a state not ready does not block the states depending on it
+	require.Equal(t, gpuv1.NotReady, results[7].status)
+	require.Equal(t, gpuv1.Ready, results[8].status)
+}
+
+func TestSyncStatesSkipsDependentsOfFailedState(t *testing.T) {
+	names := []string{
+		"pre-requisites",
+		"state-operator-metrics",
+		"state-driver",
+		"state-container-toolkit",
+		"state-operator-validation",
+		"state-device-plugin",
+		"state-vgpu-manager",
+	}
+	synced := sync.Map{}
+	n := newStateGraphTestController(names, func(name string) (gpuv1.State, error) {
+		synced.Store(name, true)
+		if name == "state-driver" {
+			return gpuv1.NotReady, fmt.Errorf("driver failure")
+		}
+		return gpuv1.Ready, nil
+	})
+
+	results := n.syncStates(nil)
+	require.Len(t, results, len(names))
+
+	expected := map[string]struct {
+		skipped bool
+		failed  bool
+	}{
+		"pre-requisites":            {},
+		"state-operator-metrics":    {},
+		"state-driver":              {failed: true},
+		"state-container-toolkit":   {skipped: true},
+		"state-operator-validation": {skipped: true},
+		"state-device-plugin":       {skipped: true},
+		"state-vgpu-manager":        {},
+	}
+	for _, res := range results {
+		e := expected[res.name]
+		require.Equal(t, e.skipped, res.skipped, res.name)
+		require.Equal(t, e.failed, res.err != nil, res.name)
+		_, ok := synced.Load(res.name)
+		require.Equal(t, !e.skipped, ok, res.name)
+		if e.skipped || e.failed {
+			require.Equal(t, gpuv1.NotReady, res.status, res.name)
+		}
+	}
+}
+
+func TestSyncStatesDefersStatesNotDue(t *testing.T) {
+	names := []string{
+		"pre-requisites",
+		"state-driver",
+		"state-container-toolkit",
+		"state-operator-validation",
+		"state-device-plugin",
+	}
+	synced := sync.Map{}
+	n := newStateGraphTestController(names, func(name string) (gpuv1.State, error) {
+		synced.Store(name, true)
+		return gpuv1.Ready, nil
+	})
+
+	// the driver is not due, the states depending on it are synced nonetheless
+	results := n.syncStates(func(name string) bool { return name != "state-driver" })
+	require.Len(t, results, len(names))
+	for _, res := range results {
+		_, ok := synced.Load(res.name)
+		if res.name == "state-driver" {
+			require.False(t, ok)
+			require.True(t, res.deferred)
+			require.Equal(t, gpuv1.NotReady, res.status)
+			continue
+		}
+		require.True(t, ok, res.name)
+		require.False(t, res.deferred, res.name)
+		require.Equal(t, gpuv1.Ready, res.status, res.name)
+	}
+}
+
+func TestStateDueTimes(t *testing.T) {
+	r := &ClusterPolicyReconciler{}
+	cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", Generation: 1}}
+	due := time.Now().Add(time.Minute)
+
+	require.Empty(t, r.getStateDueTimes(cp))
+	r.setStateDue("state-driver", due)
+	r.setStateDue("state-dcgm", due)
+	r.setStateDue("state-dcgm", time.Time{})
+	require.Equal(t, map[string]time.Time{"state-driver": due}, r.getStateDueTimes(cp))
+
+	// a change of the spec makes all states due
+	cp.Generation++
+	require.Empty(t, r.getStateDueTimes(cp))
+}
+
+func TestGetStateRequeueInterval(t *testing.T) {
+	require.Equal(t, defaultStateRequeueInterval, getStateRequeueInterval("state-device-plugin"))
+	require.Equal(t, 15*time.Second, getStateRequeueInterval("state-driver"))
+}
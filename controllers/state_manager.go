@@ -20,20 +20,28 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	secv1 "github.com/openshift/api/security/v1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	operatorassets "github.com/NVIDIA/gpu-operator/assets"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
+	"github.com/NVIDIA/gpu-operator/internal/validator"
 
 	"github.com/go-logr/logr"
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	apiimagev1 "github.com/openshift/api/image/v1"
 	configv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/mod/semver"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -80,13 +88,43 @@ const (
 	driverAutoUpgradeAnnotationKey = "nvidia.com/gpu-driver-upgrade-enabled"
 	commonDriverDaemonsetName      = "nvidia-driver-daemonset"
 	commonVGPUManagerDaemonsetName = "nvidia-vgpu-manager-daemonset"
+	// stateLabelKey identifies the DaemonSets of a state, whatever the suffix of their name
+	stateLabelKey = "nvidia.com/gpu-operator.state"
 )
 
 var (
-	defaultGPUWorkloadConfig = gpuWorkloadConfigContainer
-	podSecurityModes         = []string{"enforce", "audit", "warn"}
+	podSecurityModes = []string{"enforce", "audit", "warn"}
 )
 
+// clusterPolicyStates are the states of the ClusterPolicy, in the order they are added, with
+// the states which must be synced successfully before each of them. States without
+// dependencies between them are synced concurrently.
+var clusterPolicyStates = []struct {
+	name      string
+	dependsOn []string
+}{
+	{"pre-requisites", nil},
+	{"state-operator-metrics", nil},
+	{"state-driver", []string{"pre-requisites"}},
+	{"state-container-toolkit", []string{"state-driver"}},
+	{"state-operator-validation", []string{"state-container-toolkit"}},
+	{"state-device-plugin", []string{"state-operator-validation"}},
+	{"state-mps-control-daemon", []string{"state-device-plugin"}},
+	{"state-dcgm", []string{"state-operator-validation"}},
+	{"state-dcgm-exporter", []string{"state-dcgm"}},
+	{"gpu-feature-discovery", []string{"state-operator-validation"}},
+	{"state-mig-manager", []string{"state-operator-validation"}},
+	{"state-node-status-exporter", []string{"state-operator-validation"}},
+	// sandbox workload states
+	{"state-vgpu-manager", []string{"pre-requisites"}},
+	{"state-vgpu-device-manager", []string{"state-vgpu-manager"}},
+	{"state-sandbox-validation", []string{"state-vgpu-device-manager", "state-vfio-manager"}},
+	{"state-vfio-manager", []string{"pre-requisites"}},
+	{"state-sandbox-device-plugin", []string{"state-sandbox-validation"}},
+	{"state-kata-manager", []string{"pre-requisites"}},
+	{"state-cc-manager", []string{"pre-requisites"}},
+}
+
 var gpuStateLabels = map[string]map[string]string{
 	gpuWorkloadConfigContainer: {
 		"nvidia.com/gpu.deploy.driver":                "true",
@@ -152,6 +190,7 @@ type ClusterPolicyController struct {
 	resources            []Resources
 	controls             []controlFunc
 	stateNames           []string
+	dependencies         [][]string
 	operatorMetrics      *OperatorMetrics
 	rec                  *ClusterPolicyReconciler
 	idx                  int
@@ -162,19 +201,35 @@ type ClusterPolicyController struct {
 	openshift        string
 	ocpDriverToolkit OpenShiftDriverToolkit
 
-	runtime        gpuv1.Runtime
+	runtime gpuv1.Runtime
+	// runtimes are the container runtimes detected on the GPU nodes
+	runtimes []gpuv1.Runtime
+	// distribution is the Kubernetes distribution selecting the host paths of the operands
+	distribution   gpuv1.Distribution
 	hasGPUNodes    bool
 	hasNFDLabels   bool
 	sandboxEnabled bool
+	// defaultGPUWorkloadConfig is the GPU workload of nodes without a valid workload config label
+	defaultGPUWorkloadConfig string
 }
 
-func addState(n *ClusterPolicyController, path string) {
-	// TODO check for path
-	res, ctrl := addResourcesControls(n, path)
+// addState loads the resources and controls of a state, which is synced once the states it
+// depends on are synced successfully. Dependencies on states which are not loaded are ignored.
+func addState(n *ClusterPolicyController, loader *assets.Loader, state string, dependsOn ...string) error {
+	files, err := loader.Load(n.ctx, state)
+	if err != nil {
+		return fmt.Errorf("failed to load assets of %s: %w", state, err)
+	}
+	res, ctrl, err := addResourcesControls(n, state, files)
+	if err != nil {
+		return err
+	}
 
 	n.controls = append(n.controls, ctrl)
 	n.resources = append(n.resources, res)
-	n.stateNames = append(n.stateNames, filepath.Base(path))
+	n.stateNames = append(n.stateNames, state)
+	n.dependencies = append(n.dependencies, dependsOn)
+	return nil
 }
 
 // OpenshiftVersion fetches OCP version
@@ -326,8 +381,8 @@ func isValidWorkloadConfig(workloadConfig string) bool {
 
 // getWorkloadConfig returns the GPU workload configured for the node.
 // If an error occurs when searching for the workload config,
-// return defaultGPUWorkloadConfig.
-func getWorkloadConfig(labels map[string]string, sandboxEnabled bool) (string, error) {
+// return defaultWorkloadConfig.
+func getWorkloadConfig(labels map[string]string, sandboxEnabled bool, defaultWorkloadConfig string) (string, error) {
 	if !sandboxEnabled {
 		return gpuWorkloadConfigContainer, nil
 	}
@@ -335,9 +390,9 @@ func getWorkloadConfig(labels map[string]string, sandboxEnabled bool) (string, e
 		if isValidWorkloadConfig(workloadConfig) {
 			return workloadConfig, nil
 		}
-		return defaultGPUWorkloadConfig, fmt.Errorf("Invalid GPU workload config: %v", workloadConfig)
+		return defaultWorkloadConfig, fmt.Errorf("Invalid GPU workload config: %v", workloadConfig)
 	}
-	return defaultGPUWorkloadConfig, fmt.Errorf("No GPU workload config found")
+	return defaultWorkloadConfig, fmt.Errorf("No GPU workload config found")
 }
 
 // removeAllGPUStateLabels removes all gpuStateLabels from the provided map of node labels.
@@ -489,6 +544,12 @@ func (n *ClusterPolicyController) labelGPUNodes() (bool, int, error) {
 		return false, 0, fmt.Errorf("Unable to list nodes to check labels, err %s", err.Error())
 	}
 
+	// a node must not be selected by more than one node pool
+	err = validator.ValidateNodePoolOverrides(n.singleton.Spec.NodePoolOverrides, list.Items)
+	if err != nil {
+		return false, 0, err
+	}
+
 	clusterHasNFDLabels := false
 	updateLabels := false
 	gpuNodesTotal := 0
@@ -499,11 +560,11 @@ func (n *ClusterPolicyController) labelGPUNodes() (bool, int, error) {
 		if !clusterHasNFDLabels {
 			clusterHasNFDLabels = hasNFDLabels(labels)
 		}
-		config, err := getWorkloadConfig(labels, n.sandboxEnabled)
+		config, err := getWorkloadConfig(labels, n.sandboxEnabled, n.defaultGPUWorkloadConfig)
 		if err != nil {
 			n.rec.Log.Info("WARNING: failed to get GPU workload config for node; using default",
 				"NodeName", node.ObjectMeta.Name, "SandboxEnabled", n.sandboxEnabled,
-				"Error", err, "defaultGPUWorkloadConfig", defaultGPUWorkloadConfig)
+				"Error", err, "defaultGPUWorkloadConfig", n.defaultGPUWorkloadConfig)
 		}
 		n.rec.Log.Info("GPU workload configuration", "NodeName", node.ObjectMeta.Name, "GpuWorkloadConfig", config)
 		gpuWorkloadConfig := &gpuWorkloadConfiguration{config, node.ObjectMeta.Name, n.rec.Log}
@@ -522,7 +583,11 @@ func (n *ClusterPolicyController) labelGPUNodes() (bool, int, error) {
 			n.rec.Log.Info("Setting node label", "Label", commonGPULabelKey, "Value", "false")
 			labels[commonGPULabelKey] = "false"
 			n.rec.Log.Info("Disabling all operands for node", "NodeName", node.ObjectMeta.Name)
+			n.rec.recordEvent(n.singleton, corev1.EventTypeNormal, consts.WorkloadConfigChangedReason,
+				"Node %s no longer has GPUs, GPU state labels removed", node.ObjectMeta.Name)
 			removeAllGPUStateLabels(labels)
+			updateNodePoolLabel(labels, "")
+			updateRuntimeLabel(labels, "")
 			// update node labels
 			node.SetLabels(labels)
 			updateLabels = true
@@ -535,6 +600,8 @@ func (n *ClusterPolicyController) labelGPUNodes() (bool, int, error) {
 				n.rec.Log.Info("Applying correct GPU state labels to the node", "NodeName", node.ObjectMeta.Name)
 				node.SetLabels(labels)
 				updateLabels = true
+				n.rec.recordEvent(n.singleton, corev1.EventTypeNormal, consts.WorkloadConfigChangedReason,
+					"GPU state labels of node %s updated for GPU workload config %s", node.ObjectMeta.Name, config)
 			}
 			// Disable MIG on the node explicitly where no MIG config is specified
 			if n.singleton.Spec.MIGManager.IsEnabled() && hasMIGCapableGPU(labels) && !hasMIGConfigLabel(labels) {
@@ -545,6 +612,19 @@ func (n *ClusterPolicyController) labelGPUNodes() (bool, int, error) {
 					updateLabels = true
 				}
 			}
+			// label the node with the node pool selecting it, if any
+			if updateNodePoolLabel(labels, getNodePool(&n.singleton.Spec, labels)) {
+				n.rec.Log.Info("Setting node pool label", "NodeName", node.ObjectMeta.Name, "Label", nodePoolLabelKey, "Value", labels[nodePoolLabelKey])
+				node.SetLabels(labels)
+				updateLabels = true
+			}
+			// label the node with its container runtime, to schedule the DaemonSets configured per runtime
+			runtime, _ := getRuntimeString(node)
+			if updateRuntimeLabel(labels, runtime) {
+				n.rec.Log.Info("Setting container runtime label", "NodeName", node.ObjectMeta.Name, "Label", runtimeLabelKey, "Value", runtime.String())
+				node.SetLabels(labels)
+				updateLabels = true
+			}
 			// increment GPU node count
 			gpuNodesTotal++
 
@@ -600,7 +680,7 @@ func getRuntimeString(node corev1.Node) (gpuv1.Runtime, error) {
 
 func (n *ClusterPolicyController) setPodSecurityLabelsForNamespace() error {
 	ctx := n.ctx
-	namespaceName := clusterPolicyCtrl.operatorNamespace
+	namespaceName := n.operatorNamespace
 
 	if n.openshift != "" && namespaceName != ocpSuggestedNamespace {
 		// The GPU Operator is not installed in the suggested
@@ -650,7 +730,7 @@ func (n *ClusterPolicyController) setPodSecurityLabelsForNamespace() error {
 
 func (n *ClusterPolicyController) ocpEnsureNamespaceMonitoring() error {
 	ctx := n.ctx
-	namespaceName := clusterPolicyCtrl.operatorNamespace
+	namespaceName := n.operatorNamespace
 
 	if namespaceName != ocpSuggestedNamespace {
 		// The GPU Operator is not installed in the suggested
@@ -712,11 +792,14 @@ func (n *ClusterPolicyController) ocpEnsureNamespaceMonitoring() error {
 // For openshift, set runtime to crio. Otherwise, the default runtime is
 // containerd -- if >=1 node is configured with containerd, set
 // clusterPolicyController.runtime = containerd
+// All the runtimes detected on the GPU nodes are set in clusterPolicyController.runtimes,
+// so that the DaemonSets configured per runtime can be deployed for each of them.
 func (n *ClusterPolicyController) getRuntime() error {
 	ctx := n.ctx
 	// assume crio for openshift clusters
 	if n.openshift != "" {
 		n.runtime = gpuv1.CRIO
+		n.runtimes = []gpuv1.Runtime{gpuv1.CRIO}
 		return nil
 	}
 
@@ -730,16 +813,17 @@ func (n *ClusterPolicyController) getRuntime() error {
 	}
 
 	var runtime gpuv1.Runtime
+	runtimes := map[gpuv1.Runtime]bool{}
 	for _, node := range list.Items {
 		rt, err := getRuntimeString(node)
 		if err != nil {
 			n.rec.Log.Info(fmt.Sprintf("Unable to get runtime info for node %s: %v", node.Name, err))
 			continue
 		}
-		runtime = rt
-		if runtime == gpuv1.Containerd {
+		runtimes[rt] = true
+		if runtime != gpuv1.Containerd {
 			// default to containerd if >=1 node running containerd
-			break
+			runtime = rt
 		}
 	}
 
@@ -748,6 +832,12 @@ func (n *ClusterPolicyController) getRuntime() error {
 		runtime = gpuv1.Containerd
 	}
 	n.runtime = runtime
+
+	n.runtimes = make([]gpuv1.Runtime, 0, len(runtimes))
+	for rt := range runtimes {
+		n.runtimes = append(n.runtimes, rt)
+	}
+	sort.Slice(n.runtimes, func(i, j int) bool { return n.runtimes[i] < n.runtimes[j] })
 	return nil
 }
 
@@ -757,77 +847,21 @@ func (n *ClusterPolicyController) init(ctx context.Context, reconciler *ClusterP
 	n.rec = reconciler
 	n.idx = 0
 
-	if len(n.controls) == 0 {
-		clusterPolicyCtrl.operatorNamespace = os.Getenv("OPERATOR_NAMESPACE")
-
-		if clusterPolicyCtrl.operatorNamespace == "" {
-			n.rec.Log.Error(nil, "OPERATOR_NAMESPACE environment variable not set, cannot proceed")
-			// we cannot do anything without the operator namespace,
-			// let the operator Pod run into `CrashloopBackOff`
-
-			os.Exit(1)
-		}
-
-		version, err := OpenshiftVersion(ctx)
-		if err != nil && !apierrors.IsNotFound(err) {
-			return err
-		}
-		n.openshift = version
-
-		k8sVersion, err := KubernetesVersion()
-		if err != nil {
-			return err
-		}
-		if !semver.IsValid(k8sVersion) {
-			return fmt.Errorf("k8s version detected '%s' is not a valid semantic version", k8sVersion)
-		}
-		n.k8sVersion = k8sVersion
-		n.rec.Log.Info("Kubernetes version detected", "version", k8sVersion)
-
-		utilruntime.Must(promv1.AddToScheme(reconciler.Scheme))
-		utilruntime.Must(secv1.Install(reconciler.Scheme))
-		utilruntime.Must(apiconfigv1.Install(reconciler.Scheme))
-		utilruntime.Must(apiimagev1.Install(reconciler.Scheme))
-
-		n.operatorMetrics = initOperatorMetrics(n)
-		n.rec.Log.Info("Operator metrics initialized.")
-
-		addState(n, "/opt/gpu-operator/pre-requisites")
-		addState(n, "/opt/gpu-operator/state-operator-metrics")
-		addState(n, "/opt/gpu-operator/state-driver")
-		addState(n, "/opt/gpu-operator/state-container-toolkit")
-		addState(n, "/opt/gpu-operator/state-operator-validation")
-		addState(n, "/opt/gpu-operator/state-device-plugin")
-		addState(n, "/opt/gpu-operator/state-mps-control-daemon")
-		addState(n, "/opt/gpu-operator/state-dcgm")
-		addState(n, "/opt/gpu-operator/state-dcgm-exporter")
-		addState(n, "/opt/gpu-operator/gpu-feature-discovery")
-		addState(n, "/opt/gpu-operator/state-mig-manager")
-		addState(n, "/opt/gpu-operator/state-node-status-exporter")
-		// add sandbox workload states
-		addState(n, "/opt/gpu-operator/state-vgpu-manager")
-		addState(n, "/opt/gpu-operator/state-vgpu-device-manager")
-		addState(n, "/opt/gpu-operator/state-sandbox-validation")
-		addState(n, "/opt/gpu-operator/state-vfio-manager")
-		addState(n, "/opt/gpu-operator/state-sandbox-device-plugin")
-		addState(n, "/opt/gpu-operator/state-kata-manager")
-		addState(n, "/opt/gpu-operator/state-cc-manager")
-	}
-
+	// defaultGPUWorkloadConfig is container, unless
+	// user overrides in ClusterPolicy with a valid GPU
+	// workload configuration
+	n.defaultGPUWorkloadConfig = gpuWorkloadConfigContainer
 	if clusterPolicy.Spec.SandboxWorkloads.IsEnabled() {
 		n.sandboxEnabled = true
-		// defaultGPUWorkloadConfig is container, unless
-		// user overrides in ClusterPolicy with a valid GPU
-		// workload configuration
 		defaultWorkload := clusterPolicy.Spec.SandboxWorkloads.DefaultWorkload
 		if isValidWorkloadConfig(defaultWorkload) {
 			n.rec.Log.Info("Default GPU workload is overridden in ClusterPolicy", "DefaultWorkload", defaultWorkload)
-			defaultGPUWorkloadConfig = defaultWorkload
+			n.defaultGPUWorkloadConfig = defaultWorkload
 		}
 	} else {
 		n.sandboxEnabled = false
 	}
-	n.rec.Log.Info("Sandbox workloads", "Enabled", n.sandboxEnabled, "DefaultWorkload", defaultGPUWorkloadConfig)
+	n.rec.Log.Info("Sandbox workloads", "Enabled", n.sandboxEnabled, "DefaultWorkload", n.defaultGPUWorkloadConfig)
 
 	if n.openshift != "" && (n.singleton.Spec.Operator.UseOpenShiftDriverToolkit == nil ||
 		*n.singleton.Spec.Operator.UseOpenShiftDriverToolkit) {
@@ -876,7 +910,14 @@ func (n *ClusterPolicyController) init(ctx context.Context, reconciler *ClusterP
 	if err != nil {
 		return err
 	}
-	n.rec.Log.Info(fmt.Sprintf("Using container runtime: %s", n.runtime.String()))
+	n.rec.Log.Info(fmt.Sprintf("Using container runtime: %s", n.runtime.String()), "detectedRuntimes", n.runtimes)
+
+	// detect the Kubernetes distribution selecting the host paths of the operands
+	err = n.getDistribution()
+	if err != nil {
+		return err
+	}
+	n.rec.Log.Info("Using Kubernetes distribution profile", "distribution", n.distribution)
 
 	// fetch all kernel versions from the GPU nodes in the cluster
 	if n.singleton.Spec.Driver.IsEnabled() && n.singleton.Spec.Driver.UsePrecompiledDrivers() {
@@ -898,6 +939,63 @@ func (n *ClusterPolicyController) init(ctx context.Context, reconciler *ClusterP
 	return nil
 }
 
+// initStates performs the one-time initialization of the controller: cluster
+// facts which do not change over the lifetime of the operator, metrics and
+// the resources and controls of all states.
+func (n *ClusterPolicyController) initStates(ctx context.Context) error {
+	n.operatorNamespace = os.Getenv("OPERATOR_NAMESPACE")
+
+	if n.operatorNamespace == "" {
+		n.rec.Log.Error(nil, "OPERATOR_NAMESPACE environment variable not set, cannot proceed")
+		// we cannot do anything without the operator namespace,
+		// let the operator Pod run into `CrashloopBackOff`
+
+		os.Exit(1)
+	}
+
+	version, err := OpenshiftVersion(ctx)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	n.openshift = version
+
+	k8sVersion, err := KubernetesVersion()
+	if err != nil {
+		return err
+	}
+	if !semver.IsValid(k8sVersion) {
+		return fmt.Errorf("k8s version detected '%s' is not a valid semantic version", k8sVersion)
+	}
+	n.k8sVersion = k8sVersion
+	n.rec.Log.Info("Kubernetes version detected", "version", k8sVersion)
+
+	utilruntime.Must(promv1.AddToScheme(n.rec.Scheme))
+	utilruntime.Must(secv1.Install(n.rec.Scheme))
+	utilruntime.Must(apiconfigv1.Install(n.rec.Scheme))
+	utilruntime.Must(apiimagev1.Install(n.rec.Scheme))
+
+	n.operatorMetrics = initOperatorMetrics(n)
+	n.rec.Log.Info("Operator metrics initialized.")
+
+	return n.addStates()
+}
+
+// addStates loads the resources and controls of all the states of the ClusterPolicy,
+// replacing the ones loaded before
+func (n *ClusterPolicyController) addStates() error {
+	loader := n.rec.Assets
+	if loader == nil {
+		loader = assets.NewLoader(operatorassets.FS, "")
+	}
+	n.resources, n.controls, n.stateNames, n.dependencies = nil, nil, nil, nil
+	for _, state := range clusterPolicyStates {
+		if err := addState(n, loader, state.name, state.dependsOn...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (n *ClusterPolicyController) initOCPParams() error {
 	// initialize openshift specific parameters
 	if n.singleton.Spec.Driver.UsePrecompiledDrivers() {
@@ -943,7 +1041,32 @@ func (n *ClusterPolicyController) initOCPParams() error {
 }
 
 func (n *ClusterPolicyController) step() (gpuv1.State, error) {
-	result := gpuv1.Ready
+	result, err := n.syncState(n.idx)
+	if err != nil {
+		return result, err
+	}
+
+	// move to next state
+	n.idx++
+
+	return result, nil
+}
+
+// syncState deploys the resources of the state at the given index and returns
+// its status. It operates on a copy of the controller, so states without
+// dependencies on each other can be synced concurrently.
+func (n ClusterPolicyController) syncState(idx int) (result gpuv1.State, err error) {
+	n.idx = idx
+	result = gpuv1.Ready
+
+	start := time.Now()
+	var span trace.Span
+	n.ctx, span = tracing.Start(n.ctx, "Sync "+n.stateNames[idx], tracing.State(n.stateNames[idx]))
+	defer func() {
+		n.operatorMetrics.observeStateSync(n.stateNames[idx], time.Since(start))
+		span.SetAttributes(tracing.StatusKey.String(string(result)))
+		tracing.End(span, err)
+	}()
 
 	// Skip state-driver if NVIDIADriver CRD is enabled
 	// TODO:
@@ -955,7 +1078,6 @@ func (n *ClusterPolicyController) step() (gpuv1.State, error) {
 	if (n.stateNames[n.idx] == "state-driver" || n.stateNames[n.idx] == "state-vgpu-manager") &&
 		n.singleton.Spec.Driver.UseNvdiaDriverCRDType() {
 		n.rec.Log.Info("NVIDIADriver CRD is enabled, cleaning up all NVIDIA driver daemonsets owned by ClusterPolicy")
-		n.idx++
 		// Cleanup all driver daemonsets owned by ClusterPolicy.
 		err := n.cleanupAllDriverDaemonSets(n.ctx)
 		if err != nil {
@@ -965,7 +1087,7 @@ func (n *ClusterPolicyController) step() (gpuv1.State, error) {
 	}
 
 	for _, fs := range n.controls[n.idx] {
-		stat, err := fs(*n)
+		stat, err := fs(n)
 		if err != nil {
 			return stat, err
 		}
@@ -976,9 +1098,6 @@ func (n *ClusterPolicyController) step() (gpuv1.State, error) {
 		}
 	}
 
-	// move to next state
-	n.idx++
-
 	return result, nil
 }
 
@@ -991,6 +1110,90 @@ func (n ClusterPolicyController) last() bool {
 	return n.idx == len(n.controls)
 }
 
+// getComponentStatus returns the status of the state at the given index, including
+// the pod counts of the DaemonSet(s) deployed by the state and the nodes where its pods are not ready
+func (n ClusterPolicyController) getComponentStatus(idx int, state gpuv1.State) gpuv1.ComponentStatus {
+	name := n.stateNames[idx]
+	status := gpuv1.ComponentStatus{
+		Name:    name,
+		Enabled: state != gpuv1.Disabled && n.isStateEnabled(name),
+		State:   state,
+	}
+	if !status.Enabled {
+		status.State = gpuv1.Disabled
+		return status
+	}
+
+	dsName := n.resources[idx].DaemonSet.Name
+	if dsName == "" {
+		return status
+	}
+
+	list := &appsv1.DaemonSetList{}
+	err := n.rec.Client.List(n.ctx, list, client.InNamespace(n.operatorNamespace), client.MatchingLabels{stateLabelKey: name})
+	if err != nil {
+		n.rec.Log.Error(err, "could not list daemonsets for component status", "state", name)
+		return status
+	}
+
+	notReadyNodes := map[string]bool{}
+	var daemonsets []*appsv1.DaemonSet
+	for i := range list.Items {
+		ds := &list.Items[i]
+		if !metav1.IsControlledBy(ds, n.singleton) {
+			continue
+		}
+		daemonsets = append(daemonsets, ds)
+		status.DesiredPods += ds.Status.DesiredNumberScheduled
+		status.ReadyPods += ds.Status.NumberReady
+		if ds.Status.NumberReady >= ds.Status.DesiredNumberScheduled {
+			continue
+		}
+		for _, nodeName := range n.getNotReadyPodNodes(ds) {
+			notReadyNodes[nodeName] = true
+		}
+	}
+
+	for nodeName := range notReadyNodes {
+		status.NotReadyNodes = append(status.NotReadyNodes, nodeName)
+	}
+	sort.Strings(status.NotReadyNodes)
+	if n.singleton.Spec.Operator.IsPinImageDigestsEnabled() {
+		status.Images = getPinnedImages(daemonsets...)
+	}
+	return status
+}
+
+// getNotReadyPodNodes returns the names of the nodes running a pod of the DaemonSet which is not ready
+func (n ClusterPolicyController) getNotReadyPodNodes(ds *appsv1.DaemonSet) []string {
+	if ds.Spec.Selector == nil {
+		return nil
+	}
+	list := &corev1.PodList{}
+	err := n.rec.Client.List(n.ctx, list, client.InNamespace(ds.Namespace), client.MatchingLabels(ds.Spec.Selector.MatchLabels))
+	if err != nil {
+		n.rec.Log.Error(err, "could not list pods for component status", "daemonset", ds.Name)
+		return nil
+	}
+
+	var nodes []string
+	for _, pod := range list.Items {
+		if !metav1.IsControlledBy(&pod, ds) || pod.Spec.NodeName == "" {
+			continue
+		}
+		ready := pod.Status.Phase == corev1.PodRunning
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue {
+				ready = false
+			}
+		}
+		if !ready {
+			nodes = append(nodes, pod.Spec.NodeName)
+		}
+	}
+	return nodes
+}
+
 func (n ClusterPolicyController) isStateEnabled(stateName string) bool {
 	clusterPolicySpec := &n.singleton.Spec
 
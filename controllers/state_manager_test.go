@@ -17,9 +17,17 @@
 package controllers
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 )
@@ -69,3 +77,132 @@ func TestGetRuntimeString(t *testing.T) {
 		})
 	}
 }
+
+func TestGetComponentStatus(t *testing.T) {
+	cp := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "cp-uid"},
+	}
+	isController := true
+	dsOwner := metav1.OwnerReference{APIVersion: "nvidia.com/v1", Kind: "ClusterPolicy", Name: cp.Name, UID: cp.UID, Controller: &isController}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "nvidia-device-plugin-daemonset",
+			Namespace:       "test-operator",
+			UID:             "ds-uid",
+			Labels:          map[string]string{stateLabelKey: "state-device-plugin"},
+			OwnerReferences: []metav1.OwnerReference{dsOwner},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nvidia-device-plugin-daemonset"}},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 1},
+	}
+	podOwner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "DaemonSet", Name: ds.Name, UID: ds.UID, Controller: &isController}
+	newPod := func(name, node string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       ds.Namespace,
+				Labels:          ds.Spec.Selector.MatchLabels,
+				OwnerReferences: []metav1.OwnerReference{podOwner},
+			},
+			Spec:   corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	newStateDaemonSet := func(name, state string, desired int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       ds.Namespace,
+				Labels:          map[string]string{stateLabelKey: state},
+				OwnerReferences: []metav1.OwnerReference{dsOwner},
+			},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired, NumberReady: desired},
+		}
+	}
+	objs := []client.Object{
+		ds,
+		newPod("dp-0", "node0", corev1.PodRunning),
+		newPod("dp-1", "node1", corev1.PodPending),
+		// the name of the dcgm-exporter DaemonSet is prefixed with the one of dcgm
+		newStateDaemonSet("nvidia-dcgm", "state-dcgm", 1),
+		newStateDaemonSet("nvidia-dcgm-exporter", "state-dcgm-exporter", 3),
+	}
+
+	n := ClusterPolicyController{
+		ctx:               context.Background(),
+		singleton:         cp,
+		operatorNamespace: ds.Namespace,
+		rec: &ClusterPolicyReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+		},
+		stateNames: []string{"state-device-plugin", "state-dcgm"},
+		resources: []Resources{
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: ds.Name}}},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-dcgm"}}},
+		},
+	}
+
+	status := n.getComponentStatus(0, gpuv1.NotReady)
+	require.Equal(t, "state-device-plugin", status.Name)
+	require.True(t, status.Enabled)
+	require.Equal(t, gpuv1.NotReady, status.State)
+	require.Equal(t, int32(2), status.DesiredPods)
+	require.Equal(t, int32(1), status.ReadyPods)
+	require.Equal(t, []string{"node1"}, status.NotReadyNodes)
+
+	cp.Spec.DCGM.Enabled = boolTrue
+	status = n.getComponentStatus(1, gpuv1.Ready)
+	require.Equal(t, "state-dcgm", status.Name)
+	require.True(t, status.Enabled)
+	require.Equal(t, int32(1), status.DesiredPods)
+	require.Equal(t, int32(1), status.ReadyPods)
+
+	status = n.getComponentStatus(1, gpuv1.Disabled)
+	require.Equal(t, "state-dcgm", status.Name)
+	require.False(t, status.Enabled)
+	require.Equal(t, gpuv1.Disabled, status.State)
+	require.Empty(t, status.NotReadyNodes)
+}
+
+func TestSetComponentStatus(t *testing.T) {
+	cp := &gpuv1.ClusterPolicy{}
+
+	cp.SetComponentStatus(gpuv1.ComponentStatus{Name: "state-driver", Enabled: true, State: gpuv1.NotReady})
+	require.Len(t, cp.Status.Components, 1)
+	transitionTime := metav1.NewTime(cp.Status.Components[0].LastTransitionTime.Add(-time.Hour))
+	cp.Status.Components[0].LastTransitionTime = transitionTime
+
+	// unchanged state keeps the last transition time
+	cp.SetComponentStatus(gpuv1.ComponentStatus{Name: "state-driver", Enabled: true, State: gpuv1.NotReady, ReadyPods: 1})
+	require.Len(t, cp.Status.Components, 1)
+	require.Equal(t, int32(1), cp.GetComponentStatus("state-driver").ReadyPods)
+	require.Equal(t, transitionTime, cp.GetComponentStatus("state-driver").LastTransitionTime)
+
+	// state change updates the last transition time
+	cp.SetComponentStatus(gpuv1.ComponentStatus{Name: "state-driver", Enabled: true, State: gpuv1.Ready})
+	require.Equal(t, gpuv1.Ready, cp.GetComponentStatus("state-driver").State)
+	require.NotEqual(t, transitionTime, cp.GetComponentStatus("state-driver").LastTransitionTime)
+
+	require.Nil(t, cp.GetComponentStatus("state-dcgm"))
+
+	// unchanged conditions keep their last transition time
+	verified := metav1.Condition{Type: "ImagesVerified", Status: metav1.ConditionTrue, Reason: "SignaturesVerified"}
+	cp.SetComponentStatus(gpuv1.ComponentStatus{Name: "state-driver", Enabled: true, State: gpuv1.Ready, Conditions: []metav1.Condition{verified}})
+	require.Len(t, cp.GetComponentStatus("state-driver").Conditions, 1)
+	conditionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	cp.Status.Components[0].Conditions[0].LastTransitionTime = conditionTime
+
+	cp.SetComponentStatus(gpuv1.ComponentStatus{Name: "state-driver", Enabled: true, State: gpuv1.Ready, Conditions: []metav1.Condition{verified}})
+	require.Equal(t, conditionTime, cp.GetComponentStatus("state-driver").Conditions[0].LastTransitionTime)
+
+	failed := metav1.Condition{Type: "ImagesVerified", Status: metav1.ConditionFalse, Reason: "ImageVerificationFailed"}
+	cp.SetComponentStatus(gpuv1.ComponentStatus{Name: "state-driver", Enabled: true, State: gpuv1.NotReady, Conditions: []metav1.Condition{failed}})
+	require.Equal(t, metav1.ConditionFalse, cp.GetComponentStatus("state-driver").Conditions[0].Status)
+	require.NotEqual(t, conditionTime, cp.GetComponentStatus("state-driver").Conditions[0].LastTransitionTime)
+
+	// conditions no longer reported are removed
+	cp.SetComponentStatus(gpuv1.ComponentStatus{Name: "state-driver", Enabled: true, State: gpuv1.NotReady})
+	require.Empty(t, cp.GetComponentStatus("state-driver").Conditions)
+}
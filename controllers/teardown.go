@@ -0,0 +1,312 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/consts"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/apply"
+	"github.com/NVIDIA/gpu-operator/internal/conditions"
+)
+
+const (
+	// clusterPolicyFinalizer is set on the active ClusterPolicy to remove the operands
+	// in order and clean up the GPU nodes before the ClusterPolicy is deleted
+	clusterPolicyFinalizer = "nvidia.com/gpu-operator-cleanup"
+	// gpuDeployLabelPrefix is the prefix of the node labels used to schedule the operands
+	gpuDeployLabelPrefix = "nvidia.com/gpu.deploy."
+)
+
+// operandKinds are the kinds of the objects deployed by the states of the ClusterPolicy
+var operandKinds = []schema.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "ServiceAccount"},
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "node.k8s.io", Version: "v1", Kind: "RuntimeClass"},
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"},
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"},
+	{Group: "security.openshift.io", Version: "v1", Kind: "SecurityContextConstraints"},
+}
+
+// teardown removes the operands owned by the ClusterPolicy in the reverse order
+// of the states, one state at a time, waiting for the pods of a state to terminate
+// before moving to the previous state. Once all operands are removed, the labels
+// and annotations set by the operator are removed from the nodes.
+// teardown returns the name of the state being removed, or an empty string once
+// the teardown is complete.
+func (n *ClusterPolicyController) teardown() (string, error) {
+	ctx := n.ctx
+
+	dsList := &appsv1.DaemonSetList{}
+	err := n.rec.Client.List(ctx, dsList, client.InNamespace(n.operatorNamespace))
+	if err != nil {
+		return "", fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	deployList := &appsv1.DeploymentList{}
+	err = n.rec.Client.List(ctx, deployList, client.InNamespace(n.operatorNamespace))
+	if err != nil {
+		return "", fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	for i := len(n.stateNames) - 1; i >= 0; i-- {
+		objs := n.getStateWorkloads(i, dsList.Items, deployList.Items)
+		if len(objs) == 0 {
+			continue
+		}
+
+		for _, obj := range objs {
+			if obj.GetDeletionTimestamp() != nil {
+				// deletion already in progress
+				continue
+			}
+			n.rec.Log.Info("Removing operand", "state", n.stateNames[i], "kind", obj.GetObjectKind().GroupVersionKind().Kind, "name", obj.GetName())
+			// foreground deletion keeps the object until all its pods are terminated
+			err = n.rec.Client.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationForeground))
+			if err != nil && !apierrors.IsNotFound(err) {
+				return n.stateNames[i], fmt.Errorf("failed to delete %s for state %s: %w", obj.GetName(), n.stateNames[i], err)
+			}
+		}
+		return n.stateNames[i], nil
+	}
+
+	if err := n.cleanupNodes(); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// getStateWorkloads returns the DaemonSets and Deployments of the state at the given
+// index which are owned by the ClusterPolicy
+func (n *ClusterPolicyController) getStateWorkloads(idx int, daemonsets []appsv1.DaemonSet, deployments []appsv1.Deployment) []client.Object {
+	objs := []client.Object{}
+
+	if dsName := n.resources[idx].DaemonSet.Name; dsName != "" {
+		for i := range daemonsets {
+			ds := &daemonsets[i]
+			// the suffixed DaemonSets of the state (precompiled, driver-toolkit, node pool and
+			// runtime) carry the state label, DaemonSets created by older releases only the name
+			if ds.Labels[stateLabelKey] != n.stateNames[idx] && ds.Name != dsName {
+				continue
+			}
+			if !metav1.IsControlledBy(ds, n.singleton) {
+				continue
+			}
+			ds.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+			objs = append(objs, ds)
+		}
+	}
+
+	if deployName := n.resources[idx].Deployment.Name; deployName != "" {
+		for i := range deployments {
+			deploy := &deployments[i]
+			if deploy.Name != deployName || !metav1.IsControlledBy(deploy, n.singleton) {
+				continue
+			}
+			deploy.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+			objs = append(objs, deploy)
+		}
+	}
+
+	return objs
+}
+
+// cleanupNodes removes the labels and annotations managed by the operator from all nodes
+func (n *ClusterPolicyController) cleanupNodes() error {
+	ctx := n.ctx
+
+	list := &corev1.NodeList{}
+	err := n.rec.Client.List(ctx, list)
+	if err != nil {
+		return fmt.Errorf("unable to list nodes to remove operator labels: %w", err)
+	}
+
+	annotations := []string{
+		driverAutoUpgradeAnnotationKey,
+		upgrade.GetUpgradeRequestedAnnotationKey(),
+		upgrade.GetUpgradeInitialStateAnnotationKey(),
+		upgrade.GetUpgradeDriverWaitForSafeLoadAnnotationKey(),
+		upgrade.GetWaitForPodCompletionStartTimeAnnotationKey(),
+		upgrade.GetValidationStartTimeAnnotationKey(),
+	}
+
+	for i := range list.Items {
+		node := &list.Items[i]
+		modified := false
+		for key := range node.Labels {
+			if key == commonGPULabelKey || key == nodePoolLabelKey || key == runtimeLabelKey || key == upgrade.GetUpgradeStateLabelKey() || strings.HasPrefix(key, gpuDeployLabelPrefix) {
+				delete(node.Labels, key)
+				modified = true
+			}
+		}
+		for _, key := range annotations {
+			if _, ok := node.Annotations[key]; ok {
+				delete(node.Annotations, key)
+				modified = true
+			}
+		}
+		if !modified {
+			continue
+		}
+		n.rec.Log.Info("Removing GPU Operator labels and annotations from node", "node", node.Name)
+		err = n.rec.Client.Update(ctx, node)
+		if err != nil {
+			return fmt.Errorf("unable to remove operator labels from node %s: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// reconcileDelete runs the ordered teardown of the operands of a ClusterPolicy being
+// deleted and removes the finalizer once complete. It returns true while the teardown
+// is still in progress.
+func (r *ClusterPolicyReconciler) reconcileDelete(ctx context.Context, instance *gpuv1.ClusterPolicy) (bool, error) {
+	if !controllerutil.ContainsFinalizer(instance, clusterPolicyFinalizer) {
+		return false, nil
+	}
+
+	if active := r.getActive(); active != nil && active.Name != instance.Name {
+		// another ClusterPolicy is active, the operands of this instance (if any)
+		// are garbage collected and the nodes must not be cleaned up
+		r.Log.Info("Removing finalizer from inactive ClusterPolicy", "name", instance.Name)
+		controllerutil.RemoveFinalizer(instance, clusterPolicyFinalizer)
+		if err := r.Client.Update(ctx, instance); err != nil && !apierrors.IsNotFound(err) {
+			return true, err
+		}
+		return false, nil
+	}
+
+	// when the ClusterPolicy is replaced (blue/green), its successor adopts the operands
+	// as deployed instead of removing them and cleaning up the GPU nodes
+	successor, err := getActiveClusterPolicy(ctx, r.Client)
+	if err != nil {
+		return true, err
+	}
+	if successor != nil {
+		r.Log.Info("ClusterPolicy is replaced, handing its operands over", "name", instance.Name, "successor", successor.Name)
+		if err := r.handover(ctx, instance, successor); err != nil {
+			return true, err
+		}
+		controllerutil.RemoveFinalizer(instance, clusterPolicyFinalizer)
+		if err := r.Client.Update(ctx, instance); err != nil && !apierrors.IsNotFound(err) {
+			return true, err
+		}
+		// the deletion of the instance requeues the successor, which is promoted
+		r.setActive(nil)
+		return false, nil
+	}
+
+	n, err := r.loadStates(ctx)
+	if err != nil {
+		return true, fmt.Errorf("failed to initialize ClusterPolicy controller: %w", err)
+	}
+	n.singleton = instance
+	n.ctx = ctx
+	n.rec = r
+
+	stateName, err := n.teardown()
+	if err != nil {
+		r.Log.Error(err, "Failed to remove operands of deleted ClusterPolicy")
+		if condErr := r.conditionUpdater.SetConditionsError(ctx, instance, conditions.TerminationFailed, err.Error()); condErr != nil {
+			r.Log.V(consts.LogLevelDebug).Error(nil, condErr.Error())
+		}
+		return true, err
+	}
+
+	if stateName != "" {
+		msg := fmt.Sprintf("ClusterPolicy is being deleted, waiting for %s to be removed", stateName)
+		r.Log.Info(msg)
+		if condErr := r.conditionUpdater.SetConditionsNotReady(ctx, instance, conditions.Terminating, msg); condErr != nil {
+			r.Log.V(consts.LogLevelDebug).Error(nil, condErr.Error())
+		}
+		return true, nil
+	}
+
+	r.Log.Info("All operands removed, removing finalizer from ClusterPolicy", "name", instance.Name)
+	controllerutil.RemoveFinalizer(instance, clusterPolicyFinalizer)
+	if err := r.Client.Update(ctx, instance); err != nil && !apierrors.IsNotFound(err) {
+		return true, err
+	}
+
+	// the operands are removed, so are their drift metrics
+	apply.DeleteDrift("ClusterPolicy", instance.Name)
+
+	// the ClusterPolicy is no longer active, allow another one to be promoted
+	r.setActive(nil)
+	return false, nil
+}
+
+// handover transfers the objects controlled by the deleted ClusterPolicy to its successor, so
+// that they are not garbage collected with the deleted instance
+func (r *ClusterPolicyReconciler) handover(ctx context.Context, instance, successor *gpuv1.ClusterPolicy) error {
+	successorRef := metav1.NewControllerRef(successor, gpuv1.GroupVersion.WithKind("ClusterPolicy"))
+	for _, gvk := range operandKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := r.Client.List(ctx, list)
+		if meta.IsNoMatchError(err) {
+			// the kind is not served by the cluster, e.g. SecurityContextConstraints outside of OpenShift
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list %s objects: %w", gvk.Kind, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !metav1.IsControlledBy(obj, instance) {
+				continue
+			}
+			orig := obj.DeepCopy()
+			var refs []metav1.OwnerReference
+			for _, ref := range obj.GetOwnerReferences() {
+				if ref.UID != instance.UID {
+					refs = append(refs, ref)
+				}
+			}
+			obj.SetOwnerReferences(append(refs, *successorRef))
+			r.Log.V(consts.LogLevelDebug).Info("Handing operand over", "kind", gvk.Kind, "name", obj.GetName(), "successor", successor.Name)
+			err = r.Client.Patch(ctx, obj, client.MergeFrom(orig))
+			if err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to hand %s %s over to ClusterPolicy %s: %w", gvk.Kind, obj.GetName(), successor.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func TestTeardown(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test-operator"
+	cp := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "cp-uid"},
+	}
+	isController := true
+	owner := metav1.OwnerReference{APIVersion: "nvidia.com/v1", Kind: "ClusterPolicy", Name: cp.Name, UID: cp.UID, Controller: &isController}
+	newDaemonSet := func(name, state string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				Labels:          map[string]string{stateLabelKey: state},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+		}
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node0",
+			Labels: map[string]string{
+				commonGPULabelKey:                   "true",
+				"nvidia.com/gpu.deploy.driver":      "true",
+				"nvidia.com/gpu.deploy.mig-manager": "true",
+				upgrade.GetUpgradeStateLabelKey():   "upgrade-done",
+				"nvidia.com/gpu.workload.config":    "container",
+			},
+			Annotations: map[string]string{
+				driverAutoUpgradeAnnotationKey: "true",
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			node,
+			newDaemonSet("nvidia-driver-daemonset-5.4.0-generic-ubuntu22.04", "state-driver"),
+			newDaemonSet("nvidia-container-toolkit-daemonset", "state-container-toolkit"),
+			newDaemonSet("nvidia-device-plugin-daemonset", "state-device-plugin"),
+		).
+		Build()
+
+	n := ClusterPolicyController{
+		ctx:               ctx,
+		singleton:         cp,
+		operatorNamespace: namespace,
+		rec:               &ClusterPolicyReconciler{Client: c, Log: ctrl.Log.WithName("test")},
+		stateNames:        []string{"pre-requisites", "state-driver", "state-container-toolkit", "state-device-plugin"},
+		resources: []Resources{
+			{},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: commonDriverDaemonsetName}}},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-container-toolkit-daemonset"}}},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset"}}},
+		},
+	}
+
+	// operands are removed in reverse order of the states
+	for _, expected := range []struct {
+		state   string
+		deleted string
+	}{
+		{"state-device-plugin", "nvidia-device-plugin-daemonset"},
+		{"state-container-toolkit", "nvidia-container-toolkit-daemonset"},
+		{"state-driver", "nvidia-driver-daemonset-5.4.0-generic-ubuntu22.04"},
+	} {
+		stateName, err := n.teardown()
+		require.NoError(t, err)
+		require.Equal(t, expected.state, stateName)
+		err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: expected.deleted}, &appsv1.DaemonSet{})
+		require.True(t, apierrors.IsNotFound(err), "expected daemonset %s to be deleted", expected.deleted)
+	}
+
+	// nodes are cleaned up once all operands are removed
+	stateName, err := n.teardown()
+	require.NoError(t, err)
+	require.Empty(t, stateName)
+
+	updated := &corev1.Node{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(node), updated))
+	require.Equal(t, map[string]string{"nvidia.com/gpu.workload.config": "container"}, updated.Labels)
+	require.Empty(t, updated.Annotations)
+}
+
+func TestGetStateWorkloads(t *testing.T) {
+	cp := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy", UID: "cp-uid"},
+	}
+	isController := true
+	owner := metav1.OwnerReference{APIVersion: "nvidia.com/v1", Kind: "ClusterPolicy", Name: cp.Name, UID: cp.UID, Controller: &isController}
+	newDaemonSet := func(name string, labels map[string]string) appsv1.DaemonSet {
+		return appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, OwnerReferences: []metav1.OwnerReference{owner}},
+		}
+	}
+	daemonsets := []appsv1.DaemonSet{
+		// created by an older release, without the state label
+		newDaemonSet("nvidia-dcgm", nil),
+		newDaemonSet("nvidia-dcgm-exporter", map[string]string{stateLabelKey: "state-dcgm-exporter"}),
+		newDaemonSet("nvidia-dcgm-exporter-pool-a", map[string]string{stateLabelKey: "state-dcgm-exporter"}),
+	}
+
+	n := ClusterPolicyController{
+		singleton:  cp,
+		stateNames: []string{"state-dcgm", "state-dcgm-exporter"},
+		resources: []Resources{
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-dcgm"}}},
+			{DaemonSet: appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-dcgm-exporter"}}},
+		},
+	}
+
+	names := func(objs []client.Object) []string {
+		var names []string
+		for _, obj := range objs {
+			names = append(names, obj.GetName())
+		}
+		return names
+	}
+	require.Equal(t, []string{"nvidia-dcgm"}, names(n.getStateWorkloads(0, daemonsets, nil)))
+	require.Equal(t, []string{"nvidia-dcgm-exporter", "nvidia-dcgm-exporter-pool-a"}, names(n.getStateWorkloads(1, daemonsets, nil)))
+}
+
+func TestReconcileDeleteHandover(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test-operator"
+	deletionTimestamp := metav1.Now()
+	deleted := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "blue",
+			UID:               "blue-uid",
+			DeletionTimestamp: &deletionTimestamp,
+			Finalizers:        []string{clusterPolicyFinalizer},
+		},
+	}
+	successor := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "green", UID: "green-uid"}}
+	isController := true
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nvidia-device-plugin-daemonset",
+			Namespace: namespace,
+			Labels:    map[string]string{stateLabelKey: "state-device-plugin"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "nvidia.com/v1", Kind: "ClusterPolicy", Name: deleted.Name, UID: deleted.UID, Controller: &isController},
+			},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node0",
+			Labels: map[string]string{commonGPULabelKey: "true", "nvidia.com/gpu.deploy.device-plugin": "true"},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(deleted, successor, ds, node).
+		Build()
+	r := &ClusterPolicyReconciler{Client: c, Log: ctrl.Log.WithName("test")}
+	r.setActive(deleted)
+
+	requeue, err := r.reconcileDelete(ctx, deleted)
+	require.NoError(t, err)
+	require.False(t, requeue)
+	require.Nil(t, r.getActive())
+
+	// the deleted instance is released without tearing down its operands
+	err = c.Get(ctx, client.ObjectKeyFromObject(deleted), &gpuv1.ClusterPolicy{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	got := &appsv1.DaemonSet{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ds), got))
+	require.Len(t, got.OwnerReferences, 1)
+	require.Equal(t, successor.UID, got.OwnerReferences[0].UID)
+	require.True(t, *got.OwnerReferences[0].Controller)
+
+	updated := &corev1.Node{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(node), updated))
+	require.Equal(t, node.Labels, updated.Labels)
+}
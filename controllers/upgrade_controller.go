@@ -19,14 +19,12 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -44,6 +42,9 @@ import (
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/health"
+	"github.com/NVIDIA/gpu-operator/internal/requeue"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
 )
 
 // UpgradeReconciler reconciles Driver Daemon Sets for upgrade
@@ -52,10 +53,15 @@ type UpgradeReconciler struct {
 	Log          logr.Logger
 	Scheme       *runtime.Scheme
 	StateManager upgrade.ClusterUpgradeStateManager
+	// ClusterFacts provides the facts discovered by the ClusterPolicy controller
+	ClusterFacts ClusterFacts
+	// Health records the reconciles for the readiness and liveness checks of the operator, if set
+	Health *health.Tracker
+	// Requeue computes the requeue delays, the delays of the default policy are used if nil
+	Requeue *requeue.Backoff
 }
 
 const (
-	plannedRequeueInterval = time.Minute * 2
 	// DriverLabelKey indicates pod label key of the driver
 	DriverLabelKey = "app"
 	// DriverLabelValue indicates pod label value of the driver
@@ -80,14 +86,15 @@ const (
 func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := r.Log.WithValues("upgrade", req.NamespacedName)
 	reqLogger.V(consts.LogLevelInfo).Info("Reconciling Upgrade")
+	metrics := r.ClusterFacts.Metrics()
 
 	// Fetch the ClusterPolicy instance
 	clusterPolicy := &gpuv1.ClusterPolicy{}
 	err := r.Client.Get(ctx, req.NamespacedName, clusterPolicy)
 	if err != nil {
 		reqLogger.V(consts.LogLevelError).Error(err, "Error getting ClusterPolicy object")
-		if clusterPolicyCtrl.operatorMetrics != nil {
-			clusterPolicyCtrl.operatorMetrics.reconciliationStatus.Set(reconciliationStatusClusterPolicyUnavailable)
+		if metrics != nil {
+			metrics.reconciliationStatus.Set(reconciliationStatusClusterPolicyUnavailable)
 		}
 		if apierrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -103,8 +110,8 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		reqLogger.V(consts.LogLevelInfo).Info("Advanced driver upgrade policy is not supported when 'sandboxWorkloads.enabled=true'" +
 			"in ClusterPolicy, cleaning up upgrade state and skipping reconciliation")
 		// disable driver upgrade metrics
-		if clusterPolicyCtrl.operatorMetrics != nil {
-			clusterPolicyCtrl.operatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
+		if metrics != nil {
+			metrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
 		}
 		return ctrl.Result{}, r.removeNodeUpgradeStateLabels(ctx)
 	}
@@ -113,14 +120,14 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		!clusterPolicy.Spec.Driver.UpgradePolicy.AutoUpgrade {
 		reqLogger.V(consts.LogLevelInfo).Info("Advanced driver upgrade policy is disabled, cleaning up upgrade state and skipping reconciliation")
 		// disable driver upgrade metrics
-		if clusterPolicyCtrl.operatorMetrics != nil {
-			clusterPolicyCtrl.operatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
+		if metrics != nil {
+			metrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeDisabled)
 		}
 		return ctrl.Result{}, r.removeNodeUpgradeStateLabels(ctx)
 	}
 	// enable driver upgrade metrics
-	if clusterPolicyCtrl.operatorMetrics != nil {
-		clusterPolicyCtrl.operatorMetrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeEnabled)
+	if metrics != nil {
+		metrics.driverAutoUpgradeEnabled.Set(driverAutoUpgradeEnabled)
 	}
 
 	var driverLabel map[string]string
@@ -133,7 +140,7 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		// app component label is added for all new driver daemonsets deployed by NVIDIADriver controller
 		driverLabelKey = AppComponentLabelKey
 		driverLabelValue = AppComponentLabelValue
-	} else if clusterPolicyCtrl.openshift != "" && clusterPolicyCtrl.ocpDriverToolkit.enabled {
+	} else if r.ClusterFacts.DriverToolkitEnabled() {
 		// For OCP, when DTK is enabled app=nvidia-driver-daemonset label is not constant and changes
 		// based on rhcos version. Hence use DTK label instead
 		driverLabelKey = ocpDriverToolkitIdentificationLabel
@@ -143,7 +150,7 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	driverLabel = map[string]string{driverLabelKey: driverLabelValue}
 	reqLogger.Info("Using label selector", "key", driverLabelKey, "value", driverLabelValue)
 
-	state, err := r.StateManager.BuildState(ctx, clusterPolicyCtrl.operatorNamespace,
+	state, err := r.StateManager.BuildState(ctx, r.ClusterFacts.OperatorNamespace(),
 		driverLabel)
 	if err != nil {
 		r.Log.Error(err, "Failed to build cluster upgrade state")
@@ -175,12 +182,12 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	// log metrics with the current state
-	if clusterPolicyCtrl.operatorMetrics != nil {
-		clusterPolicyCtrl.operatorMetrics.upgradesInProgress.Set(float64(r.StateManager.GetUpgradesInProgress(ctx, state)))
-		clusterPolicyCtrl.operatorMetrics.upgradesDone.Set(float64(r.StateManager.GetUpgradesDone(ctx, state)))
-		clusterPolicyCtrl.operatorMetrics.upgradesAvailable.Set(float64(r.StateManager.GetUpgradesAvailable(ctx, state, clusterPolicy.Spec.Driver.UpgradePolicy.MaxParallelUpgrades, maxUnavailable)))
-		clusterPolicyCtrl.operatorMetrics.upgradesFailed.Set(float64(r.StateManager.GetUpgradesFailed(ctx, state)))
-		clusterPolicyCtrl.operatorMetrics.upgradesPending.Set(float64(r.StateManager.GetUpgradesPending(ctx, state)))
+	if metrics != nil {
+		metrics.upgradesInProgress.Set(float64(r.StateManager.GetUpgradesInProgress(ctx, state)))
+		metrics.upgradesDone.Set(float64(r.StateManager.GetUpgradesDone(ctx, state)))
+		metrics.upgradesAvailable.Set(float64(r.StateManager.GetUpgradesAvailable(ctx, state, clusterPolicy.Spec.Driver.UpgradePolicy.MaxParallelUpgrades, maxUnavailable)))
+		metrics.upgradesFailed.Set(float64(r.StateManager.GetUpgradesFailed(ctx, state)))
+		metrics.upgradesPending.Set(float64(r.StateManager.GetUpgradesPending(ctx, state)))
 	}
 
 	err = r.StateManager.ApplyState(ctx, state, clusterPolicy.Spec.Driver.UpgradePolicy)
@@ -193,7 +200,7 @@ func (r *UpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// might become stuck until the new reconcile loop is scheduled.
 	// Since node/ds/clusterpolicy updates from outside of the upgrade flow
 	// are not guaranteed, for safety reconcile loop should be requeued every few minutes.
-	return ctrl.Result{Requeue: true, RequeueAfter: plannedRequeueInterval}, nil
+	return ctrl.Result{Requeue: true, RequeueAfter: r.Requeue.UpgradeInterval()}, nil
 }
 
 // removeNodeUpgradeStateLabels loops over nodes in the cluster and removes "nvidia.com/gpu-driver-upgrade-state"
@@ -231,7 +238,7 @@ func (r *UpgradeReconciler) removeNodeUpgradeStateLabels(ctx context.Context) er
 //nolint:dupl
 func (r *UpgradeReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	// Create a new controller
-	c, err := controller.New("upgrade-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: 1, RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(minDelayCR, maxDelayCR)})
+	c, err := controller.New("upgrade-controller", mgr, controller.Options{Reconciler: r.Health.Wrap("upgrade-controller", tracing.WrapReconciler("upgrade-controller", r)), MaxConcurrentReconciles: 1, RateLimiter: r.Requeue.RateLimiter("upgrade-controller")})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,66 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NVIDIA/k8s-operator-libs/pkg/upgrade"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// fakeClusterFacts implements ClusterFacts before the first ClusterPolicy reconciliation
+type fakeClusterFacts struct{}
+
+func (fakeClusterFacts) OperatorNamespace() string  { return "" }
+func (fakeClusterFacts) DriverToolkitEnabled() bool { return false }
+func (fakeClusterFacts) Metrics() *OperatorMetrics  { return nil }
+
+func TestUpgradeReconcileAutoUpgradeDisabled(t *testing.T) {
+	upgradeStateLabel := upgrade.GetUpgradeStateLabelKey()
+	cp := &gpuv1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "gpu-node",
+			Labels: map[string]string{upgradeStateLabel: "upgrade-done"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cp, node).Build()
+
+	r := &UpgradeReconciler{
+		Client:       c,
+		Log:          ctrl.Log.WithName("test"),
+		Scheme:       scheme.Scheme,
+		ClusterFacts: fakeClusterFacts{},
+	}
+	res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: cp.Name}})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, res)
+
+	got := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: node.Name}, got))
+	require.NotContains(t, got.Labels, upgradeStateLabel)
+}
@@ -0,0 +1,274 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package apply
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/utils"
+)
+
+// FieldManager is the field manager owning the fields of the operand objects rendered by the operator
+const FieldManager = "gpu-operator"
+
+// Conflicts collects the conflicts with other field managers encountered while applying objects
+type Conflicts struct {
+	mu    sync.Mutex
+	items []string
+}
+
+type conflictsKey struct{}
+
+// WithConflicts returns a context recording the conflicts encountered by Apply in the returned Conflicts
+func WithConflicts(ctx context.Context) (context.Context, *Conflicts) {
+	c := &Conflicts{}
+	return context.WithValue(ctx, conflictsKey{}, c), c
+}
+
+// List returns the conflicts recorded so far
+func (c *Conflicts) List() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.items...)
+}
+
+func (c *Conflicts) add(conflict string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = append(c.items, conflict)
+}
+
+// Result tells whether Apply patched an object
+type Result string
+
+const (
+	// Unchanged is returned when no patch was applied to the object
+	Unchanged Result = "Unchanged"
+	// Created is returned when the object was created
+	Created Result = "Created"
+	// Updated is returned when the object was patched with a changed rendered object
+	Updated Result = "Updated"
+	// Restored is returned when the drifted fields of the object were restored to the unchanged rendered object
+	Restored Result = "Restored"
+)
+
+// Apply server-side applies obj under FieldManager, so that the operator only owns
+// the fields it renders and fields set by other managers are preserved. The fields
+// of objects previously updated by the operator are migrated to FieldManager first.
+// No patch is sent if the rendered object did not change since it was last applied
+// and its fields were not modified since. Rendered fields modified outside of the
+// operator are reported to the DriftHandler of the context, which decides whether
+// the object is restored.
+//
+// If another field manager owns some of the rendered fields with a different value,
+// the conflict is recorded in the Conflicts of the context. The ownership of these
+// fields is only forced back if they were owned by the operator before, otherwise
+// the object is left as is until the conflict is resolved.
+// On success, obj is updated with the object returned by the API server, and the
+// returned Result tells whether the object was patched.
+func Apply(ctx context.Context, c client.Client, obj client.Object) (Result, error) {
+	logger := log.FromContext(ctx)
+
+	desired, err := toApplyObject(c, obj)
+	if err != nil {
+		return Unchanged, err
+	}
+	hash := utils.GetObjectHash(desired)
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[consts.AppliedHashAnnotationKey] = hash
+	desired.SetAnnotations(annotations)
+
+	live, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return Unchanged, fmt.Errorf("failed to copy %s %s", desired.GetKind(), client.ObjectKeyFromObject(obj))
+	}
+	err = c.Get(ctx, client.ObjectKeyFromObject(obj), live)
+	if apierrors.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		return Unchanged, fmt.Errorf("failed to get %s %s: %w", desired.GetKind(), client.ObjectKeyFromObject(obj), err)
+	}
+
+	restore := false
+	result := Created
+	if live != nil {
+		result = Updated
+		// the drift is only checked if the rendered object did not change since it was
+		// last applied, as the differences are expected otherwise
+		var fields []string
+		applied := live.GetAnnotations()[consts.AppliedHashAnnotationKey] == hash
+		if applied {
+			fields, err = Drift(c, obj, live)
+			if err != nil {
+				return Unchanged, fmt.Errorf("failed to compare %s %s with its rendered spec: %w", desired.GetKind(), client.ObjectKeyFromObject(obj), err)
+			}
+		}
+		setDrift(desired, desired.GetKind(), len(fields) > 0)
+		if applied && len(fields) == 0 {
+			return Unchanged, setObject(obj, live)
+		}
+		if len(fields) > 0 {
+			restore = true
+			if handler, ok := ctx.Value(driftHandlerKey{}).(DriftHandler); ok {
+				restore = handler(obj, desired.GetKind(), fields)
+			}
+			if !restore {
+				return Unchanged, setObject(obj, live)
+			}
+			// the rendered object did not change, its drifted fields are restored
+			if applied {
+				result = Restored
+			}
+		}
+		if err := upgradeManagedFields(ctx, c, live); err != nil {
+			return Unchanged, fmt.Errorf("failed to migrate the managed fields of %s %s: %w", desired.GetKind(), client.ObjectKeyFromObject(obj), err)
+		}
+	}
+
+	err = c.Patch(ctx, desired, client.Apply, client.FieldOwner(FieldManager))
+	if apierrors.IsConflict(err) {
+		conflict := fmt.Sprintf("%s %s: %v", desired.GetKind(), client.ObjectKeyFromObject(obj), err)
+		if conflicts, ok := ctx.Value(conflictsKey{}).(*Conflicts); ok {
+			conflicts.add(conflict)
+		}
+		// the drifted fields were applied by the operator before being taken over
+		if !restore && (live == nil || !ownsConflictingFields(live, err)) {
+			logger.V(consts.LogLevelWarning).Info("Fields are managed by another field manager, not applying",
+				"Kind", desired.GetKind(), "Name", obj.GetName(), "Namespace", obj.GetNamespace(), "Conflict", err.Error())
+			if live == nil {
+				return Unchanged, nil
+			}
+			return Unchanged, setObject(obj, live)
+		}
+		logger.V(consts.LogLevelWarning).Info("Fields of the operator were taken over by another field manager, forcing ownership",
+			"Kind", desired.GetKind(), "Name", obj.GetName(), "Namespace", obj.GetNamespace(), "Conflict", err.Error())
+		err = c.Patch(ctx, desired, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+	}
+	if err != nil {
+		return Unchanged, fmt.Errorf("failed to apply %s %s: %w", desired.GetKind(), client.ObjectKeyFromObject(obj), err)
+	}
+	return result, setObject(obj, desired)
+}
+
+// upgradeManagedFields transfers the fields of live owned by the operator through updates to
+// FieldManager, so that they are owned through server-side apply and dropped when no longer rendered
+func upgradeManagedFields(ctx context.Context, c client.Client, live client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(live, sets.New(FieldManager), FieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	return c.Patch(ctx, live, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// ownsConflictingFields returns true if all the fields of the apply conflict err were owned by
+// FieldManager in live, i.e. they were taken over from the operator by another field manager
+func ownsConflictingFields(live client.Object, err error) bool {
+	var statusErr apierrors.APIStatus
+	if !errors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return false
+	}
+
+	owned := map[string]bool{}
+	for _, entry := range live.GetManagedFields() {
+		if entry.Manager != FieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return false
+		}
+		set.Iterate(func(path fieldpath.Path) {
+			owned[path.String()] = true
+		})
+	}
+
+	found := false
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		if !owned[cause.Field] {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// setObject updates obj with the content of the object returned by the API server
+func setObject(obj client.Object, from runtime.Object) error {
+	var content map[string]interface{}
+	if u, ok := from.(runtime.Unstructured); ok {
+		content = u.UnstructuredContent()
+	} else {
+		var err error
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+		if err != nil {
+			return err
+		}
+	}
+	if u, ok := obj.(runtime.Unstructured); ok {
+		u.SetUnstructuredContent(content)
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, obj)
+}
+
+// toApplyObject returns the apply configuration of obj: its fields set by the operator,
+// without the fields which are set by the API server.
+func toApplyObject(c client.Client, obj client.Object) (*unstructured.Unstructured, error) {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return nil, err
+	}
+	var content map[string]interface{}
+	if u, ok := obj.DeepCopyObject().(runtime.Unstructured); ok {
+		content = u.UnstructuredContent()
+	} else {
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s to unstructured: %w", gvk.Kind, obj.GetName(), err)
+		}
+	}
+
+	desired := &unstructured.Unstructured{Object: content}
+	desired.SetGroupVersionKind(gvk)
+	desired.SetResourceVersion("")
+	desired.SetManagedFields(nil)
+	unstructured.RemoveNestedField(desired.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(desired.Object, "status")
+	return desired, nil
+}
@@ -0,0 +1,284 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package apply
+
+import (
+	"context"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Data:       data,
+	}
+}
+
+func TestApplyUpdatesExistingObject(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(newConfigMap(map[string]string{"key": "old"})).Build()
+
+	obj := newConfigMap(map[string]string{"key": "new"})
+	result, err := Apply(ctx, c, obj)
+	require.NoError(t, err)
+	require.Equal(t, Updated, result)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(obj), cm))
+	require.Equal(t, "new", cm.Data["key"])
+	require.NotEmpty(t, obj.ResourceVersion)
+}
+
+func TestApplyCreatesObject(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				// the fake client does not create objects on apply
+				return c.Create(ctx, obj)
+			},
+		}).
+		Build()
+
+	result, err := Apply(ctx, c, newConfigMap(map[string]string{"key": "value"}))
+	require.NoError(t, err)
+	require.Equal(t, Created, result)
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "test"}, &corev1.ConfigMap{}))
+}
+
+func TestApplySkipsUnchangedObject(t *testing.T) {
+	var patches int
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(newConfigMap(map[string]string{"key": "old"})).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patches++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	ctx := context.Background()
+	result, err := Apply(ctx, c, newConfigMap(map[string]string{"key": "value"}))
+	require.NoError(t, err)
+	require.Equal(t, Updated, result)
+	require.Equal(t, 1, patches)
+
+	// the rendered object did not change
+	obj := newConfigMap(map[string]string{"key": "value"})
+	result, err = Apply(ctx, c, obj)
+	require.NoError(t, err)
+	require.Equal(t, Unchanged, result)
+	require.Equal(t, 1, patches)
+	require.NotEmpty(t, obj.ResourceVersion)
+
+	// the rendered fields were modified by another client
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(obj), cm))
+	cm.Data["key"] = "modified"
+	require.NoError(t, c.Update(ctx, cm))
+	result, err = Apply(ctx, c, newConfigMap(map[string]string{"key": "value"}))
+	require.NoError(t, err)
+	require.Equal(t, Restored, result)
+	require.Equal(t, 2, patches)
+}
+
+func TestApplyUpgradesManagedFields(t *testing.T) {
+	live := newConfigMap(map[string]string{"key": "old"})
+	live.ManagedFields = []metav1.ManagedFieldsEntry{{
+		Manager:    FieldManager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}}}`)},
+	}}
+
+	var patchTypes []types.PatchType
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(live).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patchTypes = append(patchTypes, patch.Type())
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	_, err := Apply(context.Background(), c, newConfigMap(map[string]string{"key": "new"}))
+	require.NoError(t, err)
+	// the fields updated by the operator are migrated to server-side apply before the apply
+	require.Equal(t, []types.PatchType{types.JSONPatchType, types.ApplyPatchType}, patchTypes)
+}
+
+func TestApplyConflicts(t *testing.T) {
+	testCases := []struct {
+		description   string
+		managedFields []metav1.ManagedFieldsEntry
+		expectForce   bool
+		expectedValue string
+	}{
+		{
+			description: "fields taken over from the operator are forced",
+			managedFields: []metav1.ManagedFieldsEntry{{
+				Manager:    FieldManager,
+				Operation:  metav1.ManagedFieldsOperationApply,
+				APIVersion: "v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}}}`)},
+			}},
+			expectForce:   true,
+			expectedValue: "new",
+		},
+		{
+			description:   "fields of other managers are not forced",
+			expectedValue: "old",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			live := newConfigMap(map[string]string{"key": "old"})
+			live.ManagedFields = tc.managedFields
+			var patches []bool
+			c := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(live).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						po := &client.PatchOptions{}
+						po.ApplyOptions(opts)
+						force := po.Force != nil && *po.Force
+						patches = append(patches, force)
+						require.Equal(t, FieldManager, po.FieldManager)
+						if !force {
+							return apierrors.NewApplyConflict([]metav1.StatusCause{{
+								Type:    metav1.CauseTypeFieldManagerConflict,
+								Message: `conflict with "kubectl-edit"`,
+								Field:   ".data.key",
+							}}, "Apply failed with 1 conflict")
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).
+				Build()
+
+			ctx, conflicts := WithConflicts(context.Background())
+			obj := newConfigMap(map[string]string{"key": "new"})
+			result, err := Apply(ctx, c, obj)
+			require.NoError(t, err)
+			if tc.expectForce {
+				require.Equal(t, []bool{false, true}, patches)
+				require.Equal(t, Updated, result)
+			} else {
+				// the object is not patched until the conflict is resolved
+				require.Equal(t, []bool{false}, patches)
+				require.Equal(t, Unchanged, result)
+			}
+			require.Len(t, conflicts.List(), 1)
+			require.Contains(t, conflicts.List()[0], "ConfigMap test-ns/test")
+
+			cm := &corev1.ConfigMap{}
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(obj), cm))
+			require.Equal(t, tc.expectedValue, cm.Data["key"])
+			require.Equal(t, tc.expectedValue, obj.Data["key"])
+		})
+	}
+}
+
+func TestApplyReturnsError(t *testing.T) {
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), testError("denied"))
+			},
+		}).
+		Build()
+
+	ctx, conflicts := WithConflicts(context.Background())
+	result, err := Apply(ctx, c, newConfigMap(nil))
+	require.Error(t, err)
+	require.Equal(t, Unchanged, result)
+	require.True(t, apierrors.IsForbidden(err))
+	require.Empty(t, conflicts.List())
+}
+
+type testError string
+
+func (e testError) Error() string { return string(e) }
+
+func TestApplyDrift(t *testing.T) {
+	owner := true
+	newOwnedConfigMap := func(value string) *corev1.ConfigMap {
+		cm := newConfigMap(map[string]string{"key": value})
+		cm.OwnerReferences = []metav1.OwnerReference{{APIVersion: "nvidia.com/v1", Kind: "ClusterPolicy", Name: "cluster-policy", UID: "cp-uid", Controller: &owner}}
+		return cm
+	}
+
+	for _, restore := range []bool{true, false} {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(newOwnedConfigMap("old")).Build()
+		var drifted []string
+		ctx := WithDriftHandler(context.Background(), func(obj client.Object, kind string, fields []string) bool {
+			require.Equal(t, "ConfigMap", kind)
+			drifted = fields
+			return restore
+		})
+		_, err := Apply(ctx, c, newOwnedConfigMap("value"))
+		require.NoError(t, err)
+		require.Empty(t, drifted)
+
+		// the rendered fields are modified outside of the operator
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "test"}, cm))
+		cm.Data["key"] = "modified"
+		require.NoError(t, c.Update(ctx, cm))
+
+		result, err := Apply(ctx, c, newOwnedConfigMap("value"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"data.key"}, drifted)
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cm), cm))
+		if restore {
+			require.Equal(t, "value", cm.Data["key"])
+			require.Equal(t, Restored, result)
+		} else {
+			require.Equal(t, "modified", cm.Data["key"])
+			require.Equal(t, Unchanged, result)
+		}
+		m := &dto.Metric{}
+		require.NoError(t, operandDrift.WithLabelValues("ConfigMap", "test").Write(m))
+		require.Equal(t, 1.0, m.GetGauge().GetValue())
+
+		// the drift metrics are removed with the owner
+		DeleteDrift("ClusterPolicy", "cluster-policy")
+		require.False(t, operandDrift.DeleteLabelValues("ConfigMap", "test"))
+	}
+}
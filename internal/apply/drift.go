@@ -0,0 +1,152 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package apply
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Drift returns the paths of the fields rendered in desired whose value differs in live.
+// Fields which are not rendered in desired, e.g. defaulted by the API server or set by
+// other field managers, are ignored.
+func Drift(c client.Client, desired, live client.Object) ([]string, error) {
+	d, err := toApplyObject(c, desired)
+	if err != nil {
+		return nil, err
+	}
+	l, err := toApplyObject(c, live)
+	if err != nil {
+		return nil, err
+	}
+	// the metadata of the objects is owned by the API server and other controllers as well
+	var fields []string
+	for key, value := range d.Object {
+		if key == "metadata" || key == "apiVersion" || key == "kind" {
+			continue
+		}
+		fields = append(fields, diffValue(key, value, l.Object[key])...)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// diffValue returns the paths below path of the values set in desired which differ in live
+func diffValue(path string, desired, live interface{}) []string {
+	switch d := desired.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			if len(d) == 0 {
+				return nil
+			}
+			return []string{path}
+		}
+		var fields []string
+		for key, value := range d {
+			fields = append(fields, diffValue(path+"."+key, value, l[key])...)
+		}
+		return fields
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			if len(d) == 0 {
+				return nil
+			}
+			return []string{path}
+		}
+		return diffList(path, d, l)
+	default:
+		if !reflect.DeepEqual(desired, live) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// diffList matches the items of lists of named objects, e.g. containers or env, by name
+// and the items of any other list by index.
+func diffList(path string, desired, live []interface{}) []string {
+	names := listNames(desired)
+	if names == nil {
+		if len(live) < len(desired) {
+			return []string{path}
+		}
+		var fields []string
+		for i := range desired {
+			fields = append(fields, diffValue(fmt.Sprintf("%s[%d]", path, i), desired[i], live[i])...)
+		}
+		return fields
+	}
+
+	liveByName := map[string]interface{}{}
+	for _, item := range live {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				liveByName[name] = item
+			}
+		}
+	}
+	var fields []string
+	for i, name := range names {
+		itemPath := fmt.Sprintf("%s[name=%s]", path, name)
+		item, ok := liveByName[name]
+		if !ok {
+			fields = append(fields, itemPath)
+			continue
+		}
+		fields = append(fields, diffValue(itemPath, desired[i], item)...)
+	}
+	return fields
+}
+
+// listNames returns the names of the items of a list of uniquely named objects, nil otherwise
+func listNames(list []interface{}) []string {
+	seen := map[string]bool{}
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		name, ok := m["name"].(string)
+		if !ok || name == "" || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// FormatDrift returns a human readable summary of the drifted fields
+func FormatDrift(fields []string) string {
+	const maxFields = 5
+	if len(fields) > maxFields {
+		return fmt.Sprintf("%s and %d more", strings.Join(fields[:maxFields], ", "), len(fields)-maxFields)
+	}
+	return strings.Join(fields, ", ")
+}
@@ -0,0 +1,107 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package apply
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDrift(t *testing.T) {
+	newDaemonSet := func() *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  "main",
+								Image: "nvcr.io/nvidia/test:v1",
+								Env:   []corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "b"}},
+							},
+						},
+						Volumes: []corev1.Volume{{Name: "run", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/run"}}}},
+					},
+				},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	testCases := []struct {
+		description string
+		modify      func(*appsv1.DaemonSet)
+		expected    []string
+	}{
+		{
+			description: "unmodified",
+			modify:      func(*appsv1.DaemonSet) {},
+		},
+		{
+			description: "defaulted and foreign fields are ignored",
+			modify: func(ds *appsv1.DaemonSet) {
+				ds.Labels = map[string]string{"foo": "bar"}
+				ds.Spec.RevisionHistoryLimit = new(int32)
+				ds.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+				ds.Spec.Template.Spec.Containers[0].Env = append([]corev1.EnvVar{{Name: "C", Value: "c"}}, ds.Spec.Template.Spec.Containers[0].Env...)
+				ds.Status.NumberReady = 1
+			},
+		},
+		{
+			description: "modified fields are reported",
+			modify: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].Image = "example.com/test:v2"
+				ds.Spec.Template.Spec.Containers[0].Env[1].Value = "c"
+				ds.Spec.Template.Spec.Volumes[0].HostPath.Path = "/var/run"
+			},
+			expected: []string{
+				"spec.template.spec.containers[name=main].env[name=B].value",
+				"spec.template.spec.containers[name=main].image",
+				"spec.template.spec.volumes[name=run].hostPath.path",
+			},
+		},
+		{
+			description: "removed list items are reported",
+			modify: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].Env = ds.Spec.Template.Spec.Containers[0].Env[:1]
+			},
+			expected: []string{"spec.template.spec.containers[name=main].env[name=B]"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			live := newDaemonSet()
+			tc.modify(live)
+			fields, err := Drift(c, newDaemonSet(), live)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fields)
+		})
+	}
+}
+
+func TestFormatDrift(t *testing.T) {
+	require.Equal(t, "a, b", FormatDrift([]string{"a", "b"}))
+	require.Equal(t, "a, b, c, d, e and 2 more", FormatDrift([]string{"a", "b", "c", "d", "e", "f", "g"}))
+}
@@ -0,0 +1,92 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package apply
+
+import (
+	"context"
+	"sync"
+
+	promcli "github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// DriftHandler is called by Apply with the rendered fields of an operand object which were
+// modified outside of the operator since it was last applied. The object is restored to its
+// rendered spec if the handler returns true, and left as is otherwise.
+type DriftHandler func(obj client.Object, kind string, fields []string) bool
+
+type driftHandlerKey struct{}
+
+// WithDriftHandler returns a context in which Apply reports the drifted objects to handler,
+// drifted objects are restored if the context has no DriftHandler
+func WithDriftHandler(ctx context.Context, handler DriftHandler) context.Context {
+	return context.WithValue(ctx, driftHandlerKey{}, handler)
+}
+
+// operandDrift reports the operand objects modified outside of the operator
+var operandDrift = promcli.NewGaugeVec(
+	promcli.GaugeOpts{
+		Name: "gpu_operator_operand_drift",
+		Help: "1 if the operand object was modified outside of the operator and differs from its rendered spec, 0 otherwise",
+	},
+	[]string{"kind", "name"},
+)
+
+var (
+	driftMu sync.Mutex
+	// driftSeries holds the label values of the operandDrift series of the objects of each owner
+	driftSeries = map[string]map[[2]string]bool{}
+)
+
+func init() {
+	metrics.Registry.MustRegister(operandDrift)
+}
+
+// setDrift reports whether the operand object drifted from its rendered spec
+func setDrift(obj client.Object, kind string, drifted bool) {
+	value := 0.0
+	if drifted {
+		value = 1
+	}
+	operandDrift.WithLabelValues(kind, obj.GetName()).Set(value)
+
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return
+	}
+	key := ref.Kind + "/" + ref.Name
+	driftMu.Lock()
+	defer driftMu.Unlock()
+	if driftSeries[key] == nil {
+		driftSeries[key] = map[[2]string]bool{}
+	}
+	driftSeries[key][[2]string{kind, obj.GetName()}] = true
+}
+
+// DeleteDrift removes the drift metrics of the operand objects controlled by the owner
+// of the given kind and name, once the owner is deleted
+func DeleteDrift(ownerKind, ownerName string) {
+	driftMu.Lock()
+	defer driftMu.Unlock()
+	key := ownerKind + "/" + ownerName
+	for series := range driftSeries[key] {
+		operandDrift.DeleteLabelValues(series[0], series[1])
+	}
+	delete(driftSeries, key)
+}
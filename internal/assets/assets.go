@@ -0,0 +1,193 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+/*
+Package assets loads the manifests of the operand states. The manifests embedded in the operator
+binary can be replaced or complemented per state by the files of an overlay directory or ConfigMap,
+so that operands can be customized without rebuilding the operator image.
+*/
+package assets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+// Overlay configures the manifest files replacing or complementing the embedded manifests of the states
+type Overlay struct {
+	// Dir is a directory holding manifest files in <Dir>/<prefix>/<state>/<file>
+	Dir string
+	// ConfigMap is the name of a ConfigMap in the operator namespace holding manifest
+	// files in keys <prefix>.<state>.<file>
+	ConfigMap string
+}
+
+// File is a manifest file of a state
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Loader loads the manifest files of states
+type Loader struct {
+	base    fs.FS
+	prefix  string
+	overlay Overlay
+	reader  client.Reader
+}
+
+// NewLoader creates a Loader of the manifests embedded in base, in one directory per state.
+// prefix is the path of the directories of the states in the overlay.
+func NewLoader(base fs.FS, prefix string) *Loader {
+	return &Loader{base: base, prefix: prefix}
+}
+
+// WithOverlay returns a copy of the Loader overlaying the embedded manifests with the files of
+// overlay. The overlay ConfigMap is read with reader.
+func (l *Loader) WithOverlay(overlay Overlay, reader client.Reader) *Loader {
+	c := *l
+	c.overlay = overlay
+	c.reader = reader
+	return &c
+}
+
+// OverlaySource returns a source of the events of the overlay ConfigMap, nil if the Loader has
+// none, so that the states can be reloaded when it changes. The ConfigMap is watched with a cache
+// restricted to it, which is started by mgr.
+func (l *Loader) OverlaySource(mgr manager.Manager) (source.Source, error) {
+	if l == nil || l.overlay.ConfigMap == "" {
+		return nil, nil
+	}
+	namespace := os.Getenv("OPERATOR_NAMESPACE")
+	if namespace == "" {
+		return nil, fmt.Errorf("OPERATOR_NAMESPACE environment variable not set, cannot watch overlay ConfigMap")
+	}
+	c, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: map[string]cache.Config{namespace: {}},
+				Field:      fields.OneTermEqualSelector("metadata.name", l.overlay.ConfigMap),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cache of overlay ConfigMap %s: %w", l.overlay.ConfigMap, err)
+	}
+	if err := mgr.Add(c); err != nil {
+		return nil, err
+	}
+	return source.Kind(c, &corev1.ConfigMap{}), nil
+}
+
+// Load returns the manifest files of state sorted by name: the embedded files, replaced or complemented
+// by the files of the overlay directory and then by the files of the overlay ConfigMap.
+func (l *Loader) Load(ctx context.Context, state string) ([]File, error) {
+	files := map[string][]byte{}
+
+	if err := readDir(l.base, state, files); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read embedded manifests of %s: %w", state, err)
+	}
+	if l.overlay.Dir != "" {
+		dir := filepath.Join(l.overlay.Dir, l.prefix, state)
+		if err := readDir(os.DirFS(dir), ".", files); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read overlay manifests of %s from %s: %w", state, dir, err)
+		}
+	}
+	if l.overlay.ConfigMap != "" && l.reader != nil {
+		if err := l.readConfigMap(ctx, state, files); err != nil {
+			return nil, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no manifests found for %s", state)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]File, 0, len(names))
+	for _, name := range names {
+		result = append(result, File{Name: name, Data: files[name]})
+	}
+	return result, nil
+}
+
+// readDir reads the regular files of dir in fsys into files
+func readDir(fsys fs.FS, dir string, files map[string][]byte) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		files[entry.Name()] = data
+	}
+	return nil
+}
+
+// readConfigMap reads the files of state in the overlay ConfigMap into files
+func (l *Loader) readConfigMap(ctx context.Context, state string, files map[string][]byte) error {
+	logger := log.FromContext(ctx)
+
+	namespace := os.Getenv("OPERATOR_NAMESPACE")
+	if namespace == "" {
+		return fmt.Errorf("OPERATOR_NAMESPACE environment variable not set, cannot read overlay ConfigMap")
+	}
+	cm := &corev1.ConfigMap{}
+	err := l.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: l.overlay.ConfigMap}, cm)
+	if err != nil {
+		return fmt.Errorf("failed to get overlay ConfigMap %s: %w", l.overlay.ConfigMap, err)
+	}
+
+	keyPrefix := strings.ReplaceAll(path.Join(l.prefix, state), "/", ".") + "."
+	for key, value := range cm.Data {
+		name := strings.TrimPrefix(key, keyPrefix)
+		if name == key || name == "" {
+			continue
+		}
+		logger.V(consts.LogLevelInfo).Info("Using overlay manifest", "state", state, "file", name, "ConfigMap", cm.Name)
+		files[name] = []byte(value)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package assets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testFS = fstest.MapFS{
+	"state-test/0100_service_account.yaml": {Data: []byte("embedded-sa")},
+	"state-test/0500_daemonset.yaml":       {Data: []byte("embedded-ds")},
+}
+
+func fileNames(files []File) []string {
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestLoadEmbedded(t *testing.T) {
+	files, err := NewLoader(testFS, "").Load(context.Background(), "state-test")
+	require.NoError(t, err)
+	require.Equal(t, []string{"0100_service_account.yaml", "0500_daemonset.yaml"}, fileNames(files))
+	require.Equal(t, "embedded-ds", string(files[1].Data))
+
+	_, err = NewLoader(testFS, "").Load(context.Background(), "state-missing")
+	require.Error(t, err)
+}
+
+func TestLoadOverlayDir(t *testing.T) {
+	dir := t.TempDir()
+	stateDir := filepath.Join(dir, "manifests", "state-test")
+	require.NoError(t, os.MkdirAll(stateDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "0500_daemonset.yaml"), []byte("overlay-ds"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "0600_configmap.yaml"), []byte("overlay-cm"), 0o600))
+
+	loader := NewLoader(testFS, "manifests").WithOverlay(Overlay{Dir: dir}, nil)
+	files, err := loader.Load(context.Background(), "state-test")
+	require.NoError(t, err)
+	require.Equal(t, []string{"0100_service_account.yaml", "0500_daemonset.yaml", "0600_configmap.yaml"}, fileNames(files))
+	require.Equal(t, "embedded-sa", string(files[0].Data))
+	require.Equal(t, "overlay-ds", string(files[1].Data))
+	require.Equal(t, "overlay-cm", string(files[2].Data))
+
+	// states without an overlay directory use the embedded manifests only
+	files, err = NewLoader(testFS, "").WithOverlay(Overlay{Dir: dir}, nil).Load(context.Background(), "state-test")
+	require.NoError(t, err)
+	require.Equal(t, "embedded-ds", string(files[1].Data))
+}
+
+func TestLoadOverlayConfigMap(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "gpu-operator")
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-operator-assets", Namespace: "gpu-operator"},
+		Data: map[string]string{
+			"manifests.state-test.0500_daemonset.yaml":  "configmap-ds",
+			"manifests.state-test.0700_service.yaml":    "configmap-svc",
+			"manifests.state-other.0500_daemonset.yaml": "other-ds",
+			"state-test.0100_service_account.yaml":      "unprefixed-sa",
+		},
+	}
+	reader := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	loader := NewLoader(testFS, "manifests").WithOverlay(Overlay{ConfigMap: cm.Name}, reader)
+	files, err := loader.Load(context.Background(), "state-test")
+	require.NoError(t, err)
+	require.Equal(t, []string{"0100_service_account.yaml", "0500_daemonset.yaml", "0700_service.yaml"}, fileNames(files))
+	require.Equal(t, "embedded-sa", string(files[0].Data))
+	require.Equal(t, "configmap-ds", string(files[1].Data))
+	require.Equal(t, "configmap-svc", string(files[2].Data))
+
+	loader = NewLoader(testFS, "manifests").WithOverlay(Overlay{ConfigMap: "missing"}, reader)
+	_, err = loader.Load(context.Background(), "state-test")
+	require.Error(t, err)
+}
@@ -48,6 +48,16 @@ func (u *clusterPolicyUpdater) SetConditionsError(ctx context.Context, cr any, r
 	return u.setConditionsError(ctx, clusterPolicyCr, reason, message)
 }
 
+func (u *clusterPolicyUpdater) SetConditionsNotReady(ctx context.Context, cr any, reason, message string) error {
+	clusterPolicyCr, _ := cr.(*nvidiav1.ClusterPolicy)
+	return u.setConditionsNotReady(ctx, clusterPolicyCr, reason, message)
+}
+
+func (u *clusterPolicyUpdater) SetConditionConflict(ctx context.Context, cr any, conflicts []string) error {
+	clusterPolicyCr, _ := cr.(*nvidiav1.ClusterPolicy)
+	return u.setConditionConflict(ctx, clusterPolicyCr, conflicts)
+}
+
 func (u *clusterPolicyUpdater) setConditionsReady(ctx context.Context, cr *nvidiav1.ClusterPolicy, reason, message string) error {
 	reqLogger := log.FromContext(ctx)
 	// Fetch latest instance and update state to avoid version mismatch
@@ -99,3 +109,45 @@ func (u *clusterPolicyUpdater) setConditionsError(ctx context.Context, cr *nvidi
 
 	return u.client.Status().Update(ctx, instance)
 }
+
+func (u *clusterPolicyUpdater) setConditionsNotReady(ctx context.Context, cr *nvidiav1.ClusterPolicy, reason, message string) error {
+	reqLogger := log.FromContext(ctx)
+	// Fetch latest instance and update state to avoid version mismatch
+	instance := &nvidiav1.ClusterPolicy{}
+	err := u.client.Get(ctx, types.NamespacedName{Name: cr.Name}, instance)
+	if err != nil {
+		reqLogger.Error(err, "Failed to get ClusterPolicy instance for status update", "name", cr.Name)
+		return err
+	}
+
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    Ready,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:   Error,
+		Status: metav1.ConditionFalse,
+		Reason: reason,
+	})
+
+	return u.client.Status().Update(ctx, instance)
+}
+
+func (u *clusterPolicyUpdater) setConditionConflict(ctx context.Context, cr *nvidiav1.ClusterPolicy, conflicts []string) error {
+	reqLogger := log.FromContext(ctx)
+	// Fetch latest instance and update state to avoid version mismatch
+	instance := &nvidiav1.ClusterPolicy{}
+	err := u.client.Get(ctx, types.NamespacedName{Name: cr.Name}, instance)
+	if err != nil {
+		reqLogger.Error(err, "Failed to get ClusterPolicy instance for status update", "name", cr.Name)
+		return err
+	}
+
+	if !meta.SetStatusCondition(&instance.Status.Conditions, conflictCondition(conflicts)) {
+		return nil
+	}
+	return u.client.Status().Update(ctx, instance)
+}
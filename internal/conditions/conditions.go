@@ -18,6 +18,10 @@ package conditions
 
 import (
 	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -25,10 +29,50 @@ const (
 	Ready = "Ready"
 	// Error condition type indicates one or more of the resources managed by the controller are in error state
 	Error = "Error"
+	// Conflict condition type indicates that fields rendered by the controller were also managed by another field manager
+	Conflict = "Conflict"
+	// ImagesVerified condition type indicates that the signatures of the images of an operand were verified
+	ImagesVerified = "ImagesVerified"
 )
 
 // Updater interface
 type Updater interface {
 	SetConditionsReady(ctx context.Context, cr any, reason, message string) error
 	SetConditionsError(ctx context.Context, cr any, reason, message string) error
+	SetConditionsNotReady(ctx context.Context, cr any, reason, message string) error
+	SetConditionConflict(ctx context.Context, cr any, conflicts []string) error
+}
+
+// ImagesVerifiedCondition returns the ImagesVerified condition reporting the given image verification error
+func ImagesVerifiedCondition(verificationErr error) metav1.Condition {
+	if verificationErr == nil {
+		return metav1.Condition{
+			Type:   ImagesVerified,
+			Status: metav1.ConditionTrue,
+			Reason: SignaturesVerified,
+		}
+	}
+	return metav1.Condition{
+		Type:    ImagesVerified,
+		Status:  metav1.ConditionFalse,
+		Reason:  ImageVerificationFailed,
+		Message: verificationErr.Error(),
+	}
+}
+
+// conflictCondition returns the Conflict condition reporting the given field manager conflicts
+func conflictCondition(conflicts []string) metav1.Condition {
+	if len(conflicts) == 0 {
+		return metav1.Condition{
+			Type:   Conflict,
+			Status: metav1.ConditionFalse,
+			Reason: NoConflicts,
+		}
+	}
+	return metav1.Condition{
+		Type:    Conflict,
+		Status:  metav1.ConditionTrue,
+		Reason:  FieldManagerConflict,
+		Message: fmt.Sprintf("Ownership of fields managed by another field manager was taken over: %s", strings.Join(conflicts, "; ")),
+	}
 }
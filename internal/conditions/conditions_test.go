@@ -26,6 +26,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	nvidiav1 "github.com/NVIDIA/gpu-operator/api/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
 )
 
@@ -114,3 +115,80 @@ func TestConditionsUpdater_SetConditionsErrored(t *testing.T) {
 	assert.Equal(t, expectedError.Reason, instance.Status.Conditions[1].Reason)
 	assert.Equal(t, expectedError.Message, instance.Status.Conditions[1].Message)
 }
+
+func TestConditionsUpdater_SetConditionsNotReady(t *testing.T) {
+	cp := &nvidiav1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}}
+	s := scheme.Scheme
+	_ = nvidiav1.AddToScheme(s)
+	c := fake.
+		NewClientBuilder().
+		WithScheme(s).
+		WithObjects(cp).
+		WithStatusSubresource(cp).
+		Build()
+	u := NewClusterPolicyUpdater(c)
+
+	expectedReady := metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionFalse,
+		Reason:  Terminating,
+		Message: "ClusterPolicy is being deleted",
+	}
+	expectedError := metav1.Condition{
+		Type:   "Error",
+		Status: metav1.ConditionFalse,
+		Reason: Terminating,
+	}
+
+	err := u.SetConditionsNotReady(context.Background(), cp, Terminating, "ClusterPolicy is being deleted")
+	assert.NoError(t, err)
+
+	instance := &nvidiav1.ClusterPolicy{}
+	err = c.Get(context.Background(), types.NamespacedName{Name: cp.Name}, instance)
+	assert.NoError(t, err)
+
+	assert.Len(t, instance.Status.Conditions, 2)
+
+	assert.Equal(t, expectedReady.Type, instance.Status.Conditions[0].Type)
+	assert.Equal(t, expectedReady.Status, instance.Status.Conditions[0].Status)
+	assert.Equal(t, expectedReady.Reason, instance.Status.Conditions[0].Reason)
+	assert.Equal(t, expectedReady.Message, instance.Status.Conditions[0].Message)
+
+	assert.Equal(t, expectedError.Type, instance.Status.Conditions[1].Type)
+	assert.Equal(t, expectedError.Status, instance.Status.Conditions[1].Status)
+	assert.Equal(t, expectedError.Reason, instance.Status.Conditions[1].Reason)
+}
+
+func TestConditionsUpdater_SetConditionConflict(t *testing.T) {
+	driver := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "gpu-driver"}}
+	s := scheme.Scheme
+	_ = nvidiav1alpha1.AddToScheme(s)
+	c := fake.
+		NewClientBuilder().
+		WithScheme(s).
+		WithObjects(driver).
+		WithStatusSubresource(driver).
+		Build()
+	u := NewNvDriverUpdater(c)
+
+	err := u.SetConditionConflict(context.Background(), driver, []string{"DaemonSet ns/driver: conflict"})
+	assert.NoError(t, err)
+
+	instance := &nvidiav1alpha1.NVIDIADriver{}
+	err = c.Get(context.Background(), types.NamespacedName{Name: driver.Name}, instance)
+	assert.NoError(t, err)
+	assert.Len(t, instance.Status.Conditions, 1)
+	assert.Equal(t, Conflict, instance.Status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, instance.Status.Conditions[0].Status)
+	assert.Equal(t, FieldManagerConflict, instance.Status.Conditions[0].Reason)
+	assert.Contains(t, instance.Status.Conditions[0].Message, "DaemonSet ns/driver: conflict")
+
+	err = u.SetConditionConflict(context.Background(), instance, nil)
+	assert.NoError(t, err)
+
+	err = c.Get(context.Background(), types.NamespacedName{Name: driver.Name}, instance)
+	assert.NoError(t, err)
+	assert.Len(t, instance.Status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, instance.Status.Conditions[0].Status)
+	assert.Equal(t, NoConflicts, instance.Status.Conditions[0].Reason)
+}
@@ -32,4 +32,19 @@ const (
 	OperandNotReady = "OperandNotReady"
 	// DriverNotReady indicates that the driver daemonset pods are not ready
 	DriverNotReady = "DriverNotReady"
+
+	// Terminating indicates that the resource is being deleted and its operands are being removed
+	Terminating = "Terminating"
+	// TerminationFailed indicates that the removal of the operands of a deleted resource failed
+	TerminationFailed = "TerminationFailed"
+
+	// FieldManagerConflict indicates that fields of the operands were also managed by another field manager
+	FieldManagerConflict = "FieldManagerConflict"
+	// NoConflicts indicates that no field manager conflict was encountered applying the operands
+	NoConflicts = "NoConflicts"
+
+	// ImageVerificationFailed indicates that the signature of an operand image could not be verified
+	ImageVerificationFailed = "ImageVerificationFailed"
+	// SignaturesVerified indicates that the signatures of the operand images were verified
+	SignaturesVerified = "SignaturesVerified"
 )
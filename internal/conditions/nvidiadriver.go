@@ -54,6 +54,16 @@ func (u *nvDriverUpdater) SetConditionsError(ctx context.Context, cr any, reason
 	return u.setConditionsError(ctx, nvDriverCr, reason, message)
 }
 
+func (u *nvDriverUpdater) SetConditionsNotReady(ctx context.Context, cr any, reason, message string) error {
+	nvDriverCr, _ := cr.(*nvidiav1alpha1.NVIDIADriver)
+	return u.setConditionsNotReady(ctx, nvDriverCr, reason, message)
+}
+
+func (u *nvDriverUpdater) SetConditionConflict(ctx context.Context, cr any, conflicts []string) error {
+	nvDriverCr, _ := cr.(*nvidiav1alpha1.NVIDIADriver)
+	return u.setConditionConflict(ctx, nvDriverCr, conflicts)
+}
+
 func (u *nvDriverUpdater) setConditionsReady(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, reason, message string) error {
 	reqLogger := log.FromContext(ctx)
 	// Fetch latest instance and update state to avoid version mismatch
@@ -112,3 +122,47 @@ func (u *nvDriverUpdater) setConditionsError(ctx context.Context, cr *nvidiav1al
 
 	return u.client.Status().Update(ctx, instance)
 }
+
+func (u *nvDriverUpdater) setConditionsNotReady(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, reason, message string) error {
+	reqLogger := log.FromContext(ctx)
+	// Fetch latest instance and update state to avoid version mismatch
+	instance := &nvidiav1alpha1.NVIDIADriver{}
+	err := u.client.Get(ctx, types.NamespacedName{Name: cr.Name}, instance)
+	if err != nil {
+		reqLogger.Error(err, "Failed to get NVIDIADriver instance for status update", "name", cr.Name)
+		return err
+	}
+
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    Ready,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:   Error,
+		Status: metav1.ConditionFalse,
+		Reason: reason,
+	})
+
+	instance.Status.State = nvidiav1alpha1.NotReady
+
+	return u.client.Status().Update(ctx, instance)
+}
+
+func (u *nvDriverUpdater) setConditionConflict(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, conflicts []string) error {
+	reqLogger := log.FromContext(ctx)
+	// Fetch latest instance and update state to avoid version mismatch
+	instance := &nvidiav1alpha1.NVIDIADriver{}
+	err := u.client.Get(ctx, types.NamespacedName{Name: cr.Name}, instance)
+	if err != nil {
+		reqLogger.Error(err, "Failed to get NVIDIADriver instance for status update", "name", cr.Name)
+		return err
+	}
+
+	if !meta.SetStatusCondition(&instance.Status.Conditions, conflictCondition(conflicts)) {
+		return nil
+	}
+	return u.client.Status().Update(ctx, instance)
+}
@@ -45,6 +45,8 @@ const (
 
 	// NvidiaAnnotationHashKey indicates annotation name for last applied hash by gpu-operator
 	NvidiaAnnotationHashKey = "nvidia.com/last-applied-hash"
+	// AppliedHashAnnotationKey is the hash of the object last server-side applied by gpu-operator
+	AppliedHashAnnotationKey = "nvidia.com/gpu-operator.applied-hash"
 
 	// VGPULicensingConfigMountPath indicates target mount path for vGPU licensing configuration file
 	VGPULicensingConfigMountPath = "/drivers/gridd.conf"
@@ -65,3 +67,19 @@ const (
 	// MinimumGDSVersionForOpenRM indicates the minimum GDS version that is supported only with OpenRM driver
 	MinimumGDSVersionForOpenRM = "v2.17.5"
 )
+
+// Reasons of the Events recorded on the ClusterPolicy and NVIDIADriver instances
+const (
+	// StateTransitionReason indicates that a state transitioned between ready, notReady, disabled and error
+	StateTransitionReason = "StateTransition"
+	// DaemonSetCreatedReason indicates that an operand DaemonSet was created
+	DaemonSetCreatedReason = "DaemonSetCreated"
+	// DaemonSetUpdatedReason indicates that the rendered spec of an operand DaemonSet changed and was applied
+	DaemonSetUpdatedReason = "DaemonSetUpdated"
+	// DaemonSetDeletedReason indicates that an operand DaemonSet was deleted
+	DaemonSetDeletedReason = "DaemonSetDeleted"
+	// OperandDriftReason indicates that an operand object was modified outside of the operator
+	OperandDriftReason = "OperandDrift"
+	// WorkloadConfigChangedReason indicates that the GPU state labels of a node changed for its workload config
+	WorkloadConfigChangedReason = "WorkloadConfigChanged"
+)
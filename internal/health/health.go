@@ -0,0 +1,276 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package health tracks the reconcile loops of the operator controllers and
+// implements the readiness and liveness checks of the operator pod on top of them.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultReadyWindow is the default duration the reconciles of a controller may keep failing
+	// before the operator is reported not ready
+	DefaultReadyWindow = 10 * time.Minute
+	// DefaultReconcileTimeout is the default duration after which a running reconcile is
+	// considered wedged and the operator is reported not alive
+	DefaultReconcileTimeout = 15 * time.Minute
+	// cacheSyncTimeout bounds the time the readiness check waits for the informer caches
+	cacheSyncTimeout = time.Second
+)
+
+// Tracker records the reconciles of the operator controllers. Its methods are no-ops on a nil Tracker.
+type Tracker struct {
+	readyWindow      time.Duration
+	reconcileTimeout time.Duration
+	cacheSynced      func(ctx context.Context) bool
+	elected          <-chan struct{}
+	now              func() time.Time
+
+	mu          sync.Mutex
+	nextID      uint64
+	controllers map[string]*controllerState
+}
+
+// controllerState is the reconcile activity of a single controller
+type controllerState struct {
+	// lastSuccess is the completion time of the last successful reconcile
+	lastSuccess time.Time
+	// failingSince is the completion time of the first failed reconcile since the last successful one
+	failingSince time.Time
+	// lastError is the error of the last failed reconcile
+	lastError error
+	// running maps the reconciles in progress to their start time
+	running map[uint64]time.Time
+}
+
+// Option configures a Tracker
+type Option func(*Tracker)
+
+// WithReadyWindow sets the duration the reconciles of a controller may keep failing before
+// the operator is reported not ready. A zero duration disables the check.
+func WithReadyWindow(window time.Duration) Option {
+	return func(t *Tracker) {
+		t.readyWindow = window
+	}
+}
+
+// WithReconcileTimeout sets the duration after which a running reconcile is considered
+// wedged and the operator is reported not alive. A zero duration disables the check.
+func WithReconcileTimeout(timeout time.Duration) Option {
+	return func(t *Tracker) {
+		t.reconcileTimeout = timeout
+	}
+}
+
+// WithCacheSync sets the function returning whether the informer caches are synced,
+// typically the WaitForCacheSync method of the manager cache
+func WithCacheSync(synced func(ctx context.Context) bool) Option {
+	return func(t *Tracker) {
+		t.cacheSynced = synced
+	}
+}
+
+// WithLeaderElection sets the channel closed once the operator is elected leader,
+// typically the Elected channel of the manager
+func WithLeaderElection(elected <-chan struct{}) Option {
+	return func(t *Tracker) {
+		t.elected = elected
+	}
+}
+
+// WithClock sets the function returning the current time, time.Now by default
+func WithClock(now func() time.Time) Option {
+	return func(t *Tracker) {
+		t.now = now
+	}
+}
+
+// NewTracker creates a Tracker
+func NewTracker(opts ...Option) *Tracker {
+	t := &Tracker{
+		readyWindow:      DefaultReadyWindow,
+		reconcileTimeout: DefaultReconcileTimeout,
+		now:              time.Now,
+		controllers:      make(map[string]*controllerState),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Start records the start of a reconcile of the controller name and returns the
+// function recording its completion with the error returned by the reconcile
+func (t *Tracker) Start(name string) func(err error) {
+	if t == nil {
+		return func(error) {}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.state(name)
+	t.nextID++
+	id := t.nextID
+	state.running[id] = t.now()
+
+	return func(err error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(state.running, id)
+		now := t.now()
+		if err == nil {
+			state.lastSuccess = now
+			state.failingSince = time.Time{}
+			state.lastError = nil
+			return
+		}
+		if state.failingSince.IsZero() {
+			state.failingSince = now
+		}
+		state.lastError = err
+	}
+}
+
+// Wrap returns a reconciler tracking the reconciles of r as the controller name
+func (t *Tracker) Wrap(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	if t == nil {
+		return r
+	}
+	t.mu.Lock()
+	t.state(name)
+	t.mu.Unlock()
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) {
+		done := t.Start(name)
+		// a panicking reconcile, recovered by the controller, is recorded as failed
+		defer func() {
+			if p := recover(); p != nil {
+				done(fmt.Errorf("panic: %v", p))
+				panic(p)
+			}
+			done(err)
+		}()
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// state returns the state of the controller name, t.mu must be held
+func (t *Tracker) state(name string) *controllerState {
+	state, ok := t.controllers[name]
+	if !ok {
+		state = &controllerState{running: make(map[uint64]time.Time)}
+		t.controllers[name] = state
+	}
+	return state
+}
+
+// isLeader returns true if the operator is the leader, or if leader election is disabled
+func (t *Tracker) isLeader() bool {
+	if t.elected == nil {
+		return true
+	}
+	select {
+	case <-t.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReadyzCheck reports the operator ready once the informer caches are synced and, on the
+// leader, as long as no controller has only failed reconciles for longer than the ready window.
+// Standby replicas are reported ready as soon as their caches are synced.
+func (t *Tracker) ReadyzCheck(req *http.Request) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.cacheSynced != nil {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !t.cacheSynced(ctx) {
+			return fmt.Errorf("informer caches are not synced")
+		}
+	}
+
+	if !t.isLeader() || t.readyWindow == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	var failing []string
+	for _, name := range t.names() {
+		state := t.controllers[name]
+		if state.failingSince.IsZero() || now.Sub(state.failingSince) <= t.readyWindow {
+			continue
+		}
+		lastSuccess := "never"
+		if !state.lastSuccess.IsZero() {
+			lastSuccess = state.lastSuccess.Format(time.RFC3339)
+		}
+		failing = append(failing, fmt.Sprintf("%s (last success: %s, last error: %v)", name, lastSuccess, state.lastError))
+	}
+	if len(failing) != 0 {
+		return fmt.Errorf("reconciles failing for more than %s: %s", t.readyWindow, strings.Join(failing, "; "))
+	}
+	return nil
+}
+
+// HealthzCheck reports the operator not alive when a reconcile has been running for longer
+// than the reconcile timeout, i.e. the reconcile loop of a controller is wedged
+func (t *Tracker) HealthzCheck(_ *http.Request) error {
+	if t == nil || t.reconcileTimeout == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	var wedged []string
+	for _, name := range t.names() {
+		for _, started := range t.controllers[name].running {
+			if now.Sub(started) > t.reconcileTimeout {
+				wedged = append(wedged, fmt.Sprintf("%s (running since %s)", name, started.Format(time.RFC3339)))
+				break
+			}
+		}
+	}
+	if len(wedged) != 0 {
+		return fmt.Errorf("reconciles running for more than %s: %s", t.reconcileTimeout, strings.Join(wedged, "; "))
+	}
+	return nil
+}
+
+// names returns the sorted names of the tracked controllers, t.mu must be held
+func (t *Tracker) names() []string {
+	names := make([]string, 0, len(t.controllers))
+	for name := range t.controllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,145 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Step(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestReadyzCheck(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	synced := false
+	elected := make(chan struct{})
+	tracker := NewTracker(
+		WithReadyWindow(5*time.Minute),
+		WithClock(clock.Now),
+		WithCacheSync(func(context.Context) bool { return synced }),
+		WithLeaderElection(elected),
+	)
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	require.ErrorContains(t, tracker.ReadyzCheck(req), "informer caches are not synced")
+	synced = true
+	require.NoError(t, tracker.ReadyzCheck(req))
+
+	// failing reconciles are tolerated within the ready window
+	tracker.Start("clusterpolicy-controller")(errors.New("failed to list nodes"))
+	clock.Step(4 * time.Minute)
+	tracker.Start("clusterpolicy-controller")(errors.New("failed to list nodes"))
+	require.NoError(t, tracker.ReadyzCheck(req))
+
+	clock.Step(2 * time.Minute)
+	// standby replicas do not reconcile and are ready once their caches are synced
+	require.NoError(t, tracker.ReadyzCheck(req))
+	close(elected)
+	err := tracker.ReadyzCheck(req)
+	require.ErrorContains(t, err, "clusterpolicy-controller (last success: never, last error: failed to list nodes)")
+
+	// a successful reconcile makes the operator ready again
+	tracker.Start("clusterpolicy-controller")(nil)
+	require.NoError(t, tracker.ReadyzCheck(req))
+
+	// the ready window starts with the first failure following a success
+	clock.Step(time.Hour)
+	tracker.Start("clusterpolicy-controller")(errors.New("conflict"))
+	require.NoError(t, tracker.ReadyzCheck(req))
+	clock.Step(6 * time.Minute)
+	require.ErrorContains(t, tracker.ReadyzCheck(req), "last error: conflict")
+}
+
+func TestHealthzCheck(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	tracker := NewTracker(WithReconcileTimeout(10*time.Minute), WithClock(clock.Now))
+	req := httptest.NewRequest("GET", "/healthz", nil)
+
+	done := tracker.Start("nvidia-driver-controller")
+	clock.Step(9 * time.Minute)
+	require.NoError(t, tracker.HealthzCheck(req))
+
+	clock.Step(2 * time.Minute)
+	require.ErrorContains(t, tracker.HealthzCheck(req), "nvidia-driver-controller (running since 2024-01-01T00:00:00Z)")
+
+	// a failed reconcile is not a wedged reconcile loop
+	done(errors.New("failed"))
+	require.NoError(t, tracker.HealthzCheck(req))
+
+	disabled := NewTracker(WithReconcileTimeout(0), WithClock(clock.Now))
+	disabled.Start("upgrade-controller")
+	clock.Step(time.Hour)
+	require.NoError(t, disabled.HealthzCheck(req))
+}
+
+func TestWrap(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	tracker := NewTracker(WithReadyWindow(time.Minute), WithClock(clock.Now))
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	var reconcileErr error
+	r := tracker.Wrap("upgrade-controller", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{RequeueAfter: time.Minute}, reconcileErr
+	}))
+
+	reconcileErr = errors.New("failed to get ClusterPolicy")
+	result, err := r.Reconcile(context.Background(), reconcile.Request{})
+	require.Equal(t, reconcileErr, err)
+	require.Equal(t, time.Minute, result.RequeueAfter)
+
+	clock.Step(2 * time.Minute)
+	require.ErrorContains(t, tracker.ReadyzCheck(req), "upgrade-controller")
+
+	reconcileErr = nil
+	_, err = r.Reconcile(context.Background(), reconcile.Request{})
+	require.NoError(t, err)
+	require.NoError(t, tracker.ReadyzCheck(req))
+
+	// a panicking reconcile is not left running and is recorded as failed
+	panicking := tracker.Wrap("upgrade-controller", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		panic("nil pointer dereference")
+	}))
+	require.PanicsWithValue(t, "nil pointer dereference", func() {
+		_, _ = panicking.Reconcile(context.Background(), reconcile.Request{})
+	})
+	clock.Step(time.Hour)
+	require.NoError(t, tracker.HealthzCheck(req))
+	require.ErrorContains(t, tracker.ReadyzCheck(req), "panic: nil pointer dereference")
+
+	// a nil tracker does not track anything
+	var nilTracker *Tracker
+	require.NotNil(t, nilTracker.Wrap("upgrade-controller", r))
+	nilTracker.Start("upgrade-controller")(errors.New("failed"))
+	require.NoError(t, nilTracker.ReadyzCheck(req))
+	require.NoError(t, nilTracker.HealthzCheck(req))
+}
@@ -0,0 +1,55 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package image computes the image paths of the operands. It is imported by the API
+// types and must only depend on the standard library; the code talking to the image
+// registries lives in the registry subpackage.
+package image
+
+import (
+	"strings"
+)
+
+// MirrorImage returns the image with its source prefix replaced by the mirror prefix
+// the mirrors map it to, e.g. nvcr.io/nvidia/driver:550.54.15 becomes
+// registry.example.com/nvidia/driver:550.54.15 with the mirror nvcr.io: registry.example.com.
+// A prefix only matches whole path components of the image and the longest matching
+// prefix is used. The image is returned unchanged if no prefix matches.
+func MirrorImage(image string, mirrors map[string]string) string {
+	var source string
+	for prefix := range mirrors {
+		if len(prefix) <= len(source) || !hasPathPrefix(image, prefix) {
+			continue
+		}
+		source = prefix
+	}
+	if source == "" {
+		return image
+	}
+	return strings.TrimSuffix(mirrors[source], "/") + strings.TrimPrefix(image, strings.TrimSuffix(source, "/"))
+}
+
+// hasPathPrefix returns true if prefix matches whole path components of the image, e.g.
+// nvcr.io/nvidia and nvcr.io/nvidia/driver match nvcr.io/nvidia/driver:550.54.15
+// but nvcr.io/nvidia does not match nvcr.io/nvidia-test/driver
+func hasPathPrefix(image string, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" || !strings.HasPrefix(image, prefix) {
+		return false
+	}
+	rest := image[len(prefix):]
+	return rest == "" || strings.ContainsAny(rest[:1], "/:@")
+}
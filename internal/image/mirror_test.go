@@ -0,0 +1,82 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package image
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportsStandardLibraryOnly(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	require.NoError(t, err)
+	for _, imp := range pkg.Imports {
+		// the import paths of the standard library have no domain
+		require.NotContains(t, strings.Split(imp, "/")[0], ".", "package image is imported by the API types and must not import %s", imp)
+	}
+}
+
+func TestMirrorImage(t *testing.T) {
+	mirrors := map[string]string{
+		"nvcr.io":                       "registry.example.com",
+		"nvcr.io/nvidia/k8s":            "registry.example.com/k8s-mirror/",
+		"nvcr.io/nvidia/driver":         "registry.example.com/drivers/nvidia-driver",
+		"quay.io/openshift-release-dev": "mirror.example.com/ocp",
+	}
+
+	testCases := []struct {
+		image    string
+		expected string
+	}{
+		{
+			image:    "nvcr.io/nvidia/k8s-device-plugin:v0.15.0",
+			expected: "registry.example.com/nvidia/k8s-device-plugin:v0.15.0",
+		},
+		{
+			image:    "nvcr.io/nvidia/k8s/dcgm-exporter:3.3.0",
+			expected: "registry.example.com/k8s-mirror/dcgm-exporter:3.3.0",
+		},
+		{
+			image:    "nvcr.io/nvidia/driver:550.54.15-ubuntu22.04",
+			expected: "registry.example.com/drivers/nvidia-driver:550.54.15-ubuntu22.04",
+		},
+		{
+			image:    "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			expected: "mirror.example.com/ocp/ocp-v4.0-art-dev@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		},
+		{
+			// prefixes only match whole path components
+			image:    "quay.io/openshift-release-dev-test/image:v1",
+			expected: "quay.io/openshift-release-dev-test/image:v1",
+		},
+		{
+			image:    "docker.io/library/ubuntu:22.04",
+			expected: "docker.io/library/ubuntu:22.04",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			require.Equal(t, tc.expected, MirrorImage(tc.image, mirrors))
+		})
+	}
+
+	require.Equal(t, "nvcr.io/nvidia/driver:550.54.15", MirrorImage("nvcr.io/nvidia/driver:550.54.15", nil))
+}
@@ -0,0 +1,120 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/regclient/regclient/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type credentialsKey struct{}
+
+// WithCredentials returns a context whose registry requests authenticate with the credentials
+// of the hosts, in addition to the credentials of the docker config of the operator
+func WithCredentials(ctx context.Context, hosts []config.Host) context.Context {
+	if len(hosts) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, credentialsKey{}, hosts)
+}
+
+func credentialsFrom(ctx context.Context) []config.Host {
+	hosts, _ := ctx.Value(credentialsKey{}).([]config.Host)
+	return hosts
+}
+
+// dockerAuth is the credentials of a registry in a docker config
+type dockerAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// LoadCredentials returns the registry credentials of the image pull secrets of the namespace.
+// Pull secrets which do not exist are ignored, as they are by the kubelet.
+func LoadCredentials(ctx context.Context, c client.Reader, namespace string, pullSecrets []corev1.LocalObjectReference) ([]config.Host, error) {
+	var hosts []config.Host
+	for _, ref := range pullSecrets {
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, apitypes.NamespacedName{Namespace: namespace, Name: ref.Name}, secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image pull Secret %s/%s: %w", namespace, ref.Name, err)
+		}
+		secretHosts, err := ParseCredentials(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image pull Secret %s/%s: %w", namespace, ref.Name, err)
+		}
+		hosts = append(hosts, secretHosts...)
+	}
+	return hosts, nil
+}
+
+// ParseCredentials returns the registry credentials of the docker config of an image pull secret,
+// of type kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg
+func ParseCredentials(secret *corev1.Secret) ([]config.Host, error) {
+	auths := map[string]dockerAuth{}
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		cfg := struct {
+			Auths map[string]dockerAuth `json:"auths"`
+		}{}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", corev1.DockerConfigJsonKey, err)
+		}
+		auths = cfg.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", corev1.DockerConfigKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", secret.Type)
+	}
+
+	var hosts []config.Host
+	for name, auth := range auths {
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s: %w", name, err)
+			}
+			user, pass, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid auth of registry %s", name)
+			}
+			auth.Username, auth.Password = user, pass
+		}
+		host := config.HostNewName(name)
+		host.User = auth.Username
+		host.Pass = auth.Password
+		hosts = append(hosts, *host)
+	}
+	slices.SortFunc(hosts, func(a, b config.Host) int { return strings.Compare(a.Name, b.Name) })
+	return hosts, nil
+}
@@ -0,0 +1,120 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/regclient/regclient/config"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cr:et"))
+
+	testCases := []struct {
+		description string
+		secret      *corev1.Secret
+		expected    map[string][2]string
+		errorMsg    string
+	}{
+		{
+			description: "dockerconfigjson",
+			secret: &corev1.Secret{
+				Type: corev1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry.example.com":{"auth":"` + auth + `"},"https://nvcr.io/v2/":{"username":"$oauthtoken","password":"key"}}}`),
+				},
+			},
+			expected: map[string][2]string{
+				"registry.example.com": {"robot", "s3cr:et"},
+				"nvcr.io":              {"$oauthtoken", "key"},
+			},
+		},
+		{
+			description: "dockercfg",
+			secret: &corev1.Secret{
+				Type: corev1.SecretTypeDockercfg,
+				Data: map[string][]byte{
+					corev1.DockerConfigKey: []byte(`{"registry.example.com":{"auth":"` + auth + `"}}`),
+				},
+			},
+			expected: map[string][2]string{
+				"registry.example.com": {"robot", "s3cr:et"},
+			},
+		},
+		{
+			description: "invalid auth",
+			secret: &corev1.Secret{
+				Type: corev1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry.example.com":{"auth":"` + base64.StdEncoding.EncodeToString([]byte("robot")) + `"}}}`),
+				},
+			},
+			errorMsg: "invalid auth of registry registry.example.com",
+		},
+		{
+			description: "unsupported type",
+			secret:      &corev1.Secret{Type: corev1.SecretTypeOpaque},
+			errorMsg:    "unsupported type Opaque",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hosts, err := ParseCredentials(tc.secret)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			actual := map[string][2]string{}
+			for _, h := range hosts {
+				actual[h.Name] = [2]string{h.User, h.Pass}
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestLoadCredentials(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: "test-operator"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry.example.com":{"username":"robot","password":"secret"}}}`),
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	// pull secrets which do not exist are ignored
+	hosts, err := LoadCredentials(context.TODO(), c, "test-operator",
+		[]corev1.LocalObjectReference{{Name: "missing"}, {Name: "pull-secret"}})
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, "registry.example.com", hosts[0].Name)
+
+	ctx := WithCredentials(context.TODO(), hosts)
+	require.Equal(t, hosts, credentialsFrom(ctx))
+	require.Equal(t, []config.Host(nil), credentialsFrom(WithCredentials(context.TODO(), nil)))
+}
@@ -0,0 +1,201 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/regclient/regclient"
+	"github.com/regclient/regclient/types/ref"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DigestLookup returns the digest of the manifest an image reference points to
+type DigestLookup func(ctx context.Context, image string) (string, error)
+
+// Client is a client of the image registries
+type Client struct {
+	opts   []regclient.Opt
+	client *regclient.RegClient
+}
+
+// NewRegistryClient returns a client of the image registries, with the credentials
+// of the docker config if any
+func NewRegistryClient(opts ...regclient.Opt) *Client {
+	opts = append([]regclient.Opt{regclient.WithDockerCreds(), regclient.WithUserAgent("gpu-operator")}, opts...)
+	return &Client{opts: opts, client: regclient.New(opts...)}
+}
+
+// get returns the regclient authenticating with the credentials of the context, see WithCredentials
+func (c *Client) get(ctx context.Context) *regclient.RegClient {
+	hosts := credentialsFrom(ctx)
+	if len(hosts) == 0 {
+		return c.client
+	}
+	opts := append(slices.Clone(c.opts), regclient.WithConfigHosts(hosts))
+	return regclient.New(opts...)
+}
+
+// RegistryDigestLookup returns a DigestLookup querying the image registries with client
+func RegistryDigestLookup(c *Client) DigestLookup {
+	return func(ctx context.Context, image string) (string, error) {
+		r, err := ref.New(image)
+		if err != nil {
+			return "", fmt.Errorf("failed to construct an image reference: %w", err)
+		}
+		client := c.get(ctx)
+		defer client.Close(ctx, r)
+
+		m, err := client.ManifestHead(ctx, r, regclient.WithManifestRequireDigest())
+		if err != nil {
+			return "", fmt.Errorf("failed to get image manifest: %w", err)
+		}
+		return m.GetDescriptor().Digest.String(), nil
+	}
+}
+
+// DigestResolver pins image references to the digest of their tag. The tag of
+// an image is resolved once, the same pinned reference is returned afterwards
+// even if the tag is pushed again to the registry. The environment variables
+// of the containers named *_IMAGE, e.g. VALIDATOR_IMAGE, are pinned as well.
+type DigestResolver struct {
+	lookup DigestLookup
+
+	mu sync.Mutex
+	// pinned maps the image references to their pinned reference
+	pinned map[string]string
+}
+
+// NewDigestResolver creates a DigestResolver resolving the tags of images with lookup
+func NewDigestResolver(lookup DigestLookup) *DigestResolver {
+	return &DigestResolver{
+		lookup: lookup,
+		pinned: map[string]string{},
+	}
+}
+
+// IsPinned returns true if the image reference includes a digest
+func IsPinned(image string) bool {
+	return strings.Contains(image, "@")
+}
+
+// Resolve returns the image reference pinned to the digest of its tag, in the
+// format <image>@<digest>. Image references which already include a digest are
+// returned unchanged.
+func (r *DigestResolver) Resolve(ctx context.Context, image string) (string, error) {
+	if image == "" || IsPinned(image) {
+		return image, nil
+	}
+
+	r.mu.Lock()
+	pinned, ok := r.pinned[image]
+	r.mu.Unlock()
+	if ok {
+		return pinned, nil
+	}
+
+	digest, err := r.lookup(ctx, image)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the digest of image %s: %w", image, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// the tag may have been resolved concurrently, the first pinned reference is kept
+	if pinned, ok := r.pinned[image]; ok {
+		return pinned, nil
+	}
+	pinned = image + "@" + digest
+	r.pinned[image] = pinned
+	return pinned, nil
+}
+
+// Add records pinned image references which were resolved before, e.g. by a
+// previous instance of the operator, so that their tags are not resolved again
+func (r *DigestResolver) Add(pinned ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range pinned {
+		idx := strings.LastIndex(p, "@")
+		if idx <= 0 {
+			continue
+		}
+		image := p[:idx]
+		if IsPinned(image) {
+			continue
+		}
+		if _, ok := r.pinned[image]; !ok {
+			r.pinned[image] = p
+		}
+	}
+}
+
+// PinPodSpec pins the images of the containers and init containers of the pod spec
+func (r *DigestResolver) PinPodSpec(ctx context.Context, spec *corev1.PodSpec) error {
+	return updatePodSpecImages(spec, func(image string) (string, error) {
+		return r.Resolve(ctx, image)
+	})
+}
+
+// PinnedImages returns the images of the containers and init containers of the
+// pod spec which are pinned to a digest
+func PinnedImages(spec *corev1.PodSpec) []string {
+	var images []string
+	_ = updatePodSpecImages(spec, func(image string) (string, error) {
+		if IsPinned(image) {
+			images = append(images, image)
+		}
+		return image, nil
+	})
+	return images
+}
+
+// isImageEnv returns true if the environment variable holds an image the container
+// runs workloads with, e.g. VALIDATOR_IMAGE
+func isImageEnv(env corev1.EnvVar) bool {
+	return strings.HasSuffix(env.Name, "_IMAGE") && env.ValueFrom == nil
+}
+
+// updatePodSpecImages replaces the images of the containers and init containers of the
+// pod spec, and of their image environment variables, with the result of update
+func updatePodSpecImages(spec *corev1.PodSpec, update func(image string) (string, error)) error {
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			c := &containers[i]
+			image, err := update(c.Image)
+			if err != nil {
+				return err
+			}
+			c.Image = image
+			for j := range c.Env {
+				if !isImageEnv(c.Env[j]) || c.Env[j].Value == "" {
+					continue
+				}
+				image, err := update(c.Env[j].Value)
+				if err != nil {
+					return err
+				}
+				c.Env[j].Value = image
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// fakeLookup resolves every image to testDigest and counts the lookups per image
+type fakeLookup map[string]int
+
+func (f fakeLookup) lookup(_ context.Context, image string) (string, error) {
+	f[image]++
+	if image == "nvcr.io/nvidia/missing:v1" {
+		return "", fmt.Errorf("not found")
+	}
+	return testDigest, nil
+}
+
+func TestDigestResolverResolve(t *testing.T) {
+	lookups := fakeLookup{}
+	r := NewDigestResolver(lookups.lookup)
+
+	pinned, err := r.Resolve(context.TODO(), "nvcr.io/nvidia/k8s-device-plugin:v0.15.0")
+	require.NoError(t, err)
+	require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.15.0@"+testDigest, pinned)
+
+	// the tag is only resolved once
+	pinned, err = r.Resolve(context.TODO(), "nvcr.io/nvidia/k8s-device-plugin:v0.15.0")
+	require.NoError(t, err)
+	require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.15.0@"+testDigest, pinned)
+	require.Equal(t, 1, lookups["nvcr.io/nvidia/k8s-device-plugin:v0.15.0"])
+
+	// images which include a digest are not resolved
+	digestImage := "nvcr.io/nvidia/cuda@sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	pinned, err = r.Resolve(context.TODO(), digestImage)
+	require.NoError(t, err)
+	require.Equal(t, digestImage, pinned)
+	require.Zero(t, lookups[digestImage])
+
+	_, err = r.Resolve(context.TODO(), "nvcr.io/nvidia/missing:v1")
+	require.ErrorContains(t, err, "failed to resolve the digest of image nvcr.io/nvidia/missing:v1")
+}
+
+func TestDigestResolverAdd(t *testing.T) {
+	lookups := fakeLookup{}
+	r := NewDigestResolver(lookups.lookup)
+
+	previous := "nvcr.io/nvidia/k8s/dcgm-exporter:3.3.0@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	r.Add(previous, "nvcr.io/nvidia/not-pinned:v1")
+
+	pinned, err := r.Resolve(context.TODO(), "nvcr.io/nvidia/k8s/dcgm-exporter:3.3.0")
+	require.NoError(t, err)
+	require.Equal(t, previous, pinned)
+	require.Zero(t, lookups["nvcr.io/nvidia/k8s/dcgm-exporter:3.3.0"])
+
+	pinned, err = r.Resolve(context.TODO(), "nvcr.io/nvidia/not-pinned:v1")
+	require.NoError(t, err)
+	require.Equal(t, "nvcr.io/nvidia/not-pinned:v1@"+testDigest, pinned)
+}
+
+func TestDigestResolverPinPodSpec(t *testing.T) {
+	r := NewDigestResolver(fakeLookup{}.lookup)
+	spec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name:  "plugin-validation",
+			Image: "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0",
+			Env: []corev1.EnvVar{
+				{Name: "VALIDATOR_IMAGE", Value: "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0"},
+				{Name: "VALIDATOR_IMAGE_PULL_POLICY", Value: "IfNotPresent"},
+			},
+		}},
+		Containers: []corev1.Container{{Name: "nvidia-device-plugin", Image: "nvcr.io/nvidia/k8s-device-plugin:v0.15.0"}},
+	}
+
+	require.NoError(t, r.PinPodSpec(context.TODO(), spec))
+	require.Equal(t, "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0@"+testDigest, spec.InitContainers[0].Image)
+	require.Equal(t, "nvcr.io/nvidia/cloud-native/gpu-operator-validator:v24.3.0@"+testDigest, spec.InitContainers[0].Env[0].Value)
+	require.Equal(t, "IfNotPresent", spec.InitContainers[0].Env[1].Value)
+	require.Equal(t, "nvcr.io/nvidia/k8s-device-plugin:v0.15.0@"+testDigest, spec.Containers[0].Image)
+	require.Equal(t, []string{spec.InitContainers[0].Image, spec.InitContainers[0].Env[0].Value, spec.Containers[0].Image}, PinnedImages(spec))
+}
+
+func TestDigestResolverResolveConcurrently(t *testing.T) {
+	// the lookups block until all of them are started, they must not be serialized
+	const lookups = 3
+	started := make(chan struct{}, lookups)
+	release := make(chan struct{})
+	r := NewDigestResolver(func(_ context.Context, image string) (string, error) {
+		started <- struct{}{}
+		<-release
+		return testDigest, nil
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, lookups)
+	for i := 0; i < lookups; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = r.Resolve(context.TODO(), fmt.Sprintf("nvcr.io/nvidia/image-%d:v1", i))
+		}(i)
+	}
+	for i := 0; i < lookups; i++ {
+		<-started
+	}
+	close(release)
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,205 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Keys of the Secret holding an image verification policy
+const (
+	// PolicyKeysKey holds the PEM encoded public keys of key-based signatures
+	PolicyKeysKey = "cosign.pub"
+	// PolicyCertificatesKey holds the PEM encoded certificates of the certificate authorities
+	// issuing the certificates of keyless signers, e.g. the Fulcio root and intermediate certificates
+	PolicyCertificatesKey = "fulcio.crt"
+	// PolicyRekorKeyKey holds the PEM encoded public key of the Rekor transparency log
+	// recording keyless signatures
+	PolicyRekorKeyKey = "rekor.pub"
+	// PolicyIdentitiesKey holds the YAML list of the identities of keyless signers
+	PolicyIdentitiesKey = "identities"
+)
+
+// Identity is the identity of keyless signers, as recorded in their certificates
+type Identity struct {
+	// Issuer is the OIDC issuer of the identity, e.g. https://token.actions.githubusercontent.com
+	Issuer string `json:"issuer"`
+	// Subject is the identity of the signer, e.g. an email address or a workflow URI
+	Subject string `json:"subject,omitempty"`
+	// SubjectRegExp matches the identity of the signer if subject is not set
+	SubjectRegExp string `json:"subjectRegExp,omitempty"`
+
+	subjectRegExp *regexp.Regexp
+}
+
+// Policy holds the keys and identities the signatures of images are verified with.
+// A signature is accepted if it is verified with one of the keys, or if it is a
+// keyless signature of one of the identities recorded in the transparency log.
+type Policy struct {
+	// ID identifies the policy, the images verified are cached per policy
+	ID string
+
+	Keys          []crypto.PublicKey
+	Roots         *x509.CertPool
+	Intermediates []*x509.Certificate
+	RekorKey      crypto.PublicKey
+	Identities    []Identity
+}
+
+// LoadPolicy loads the image verification policy of the Secret
+func LoadPolicy(ctx context.Context, c client.Reader, namespace string, name string) (*Policy, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, apitypes.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image verification Secret %s/%s: %w", namespace, name, err)
+	}
+	policy, err := ParsePolicy(string(secret.UID)+"/"+secret.ResourceVersion, secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image verification Secret %s/%s: %w", namespace, name, err)
+	}
+	return policy, nil
+}
+
+// ParsePolicy parses the image verification policy of the data of a Secret
+func ParsePolicy(id string, data map[string][]byte) (*Policy, error) {
+	policy := &Policy{ID: id}
+
+	keys, err := parsePublicKeys(data[PolicyKeysKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", PolicyKeysKey, err)
+	}
+	policy.Keys = keys
+
+	if identities := data[PolicyIdentitiesKey]; len(identities) != 0 {
+		if err := yaml.UnmarshalStrict(identities, &policy.Identities); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", PolicyIdentitiesKey, err)
+		}
+		for i := range policy.Identities {
+			identity := &policy.Identities[i]
+			if identity.Issuer == "" {
+				return nil, fmt.Errorf("invalid %s: issuer of identity %d not set", PolicyIdentitiesKey, i)
+			}
+			switch {
+			case identity.Subject != "":
+			case identity.SubjectRegExp != "":
+				identity.subjectRegExp, err = regexp.Compile(identity.SubjectRegExp)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s: invalid subjectRegExp of identity %d: %w", PolicyIdentitiesKey, i, err)
+				}
+			default:
+				return nil, fmt.Errorf("invalid %s: neither subject nor subjectRegExp of identity %d is set", PolicyIdentitiesKey, i)
+			}
+		}
+
+		policy.Roots, policy.Intermediates, err = parseCertificates(data[PolicyCertificatesKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", PolicyCertificatesKey, err)
+		}
+		if policy.Roots == nil {
+			return nil, fmt.Errorf("%s must hold the root certificates of the keyless signers", PolicyCertificatesKey)
+		}
+		rekorKeys, err := parsePublicKeys(data[PolicyRekorKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", PolicyRekorKeyKey, err)
+		}
+		if len(rekorKeys) != 1 {
+			return nil, fmt.Errorf("%s must hold the public key of the transparency log of keyless signatures", PolicyRekorKeyKey)
+		}
+		policy.RekorKey = rekorKeys[0]
+	}
+
+	if len(policy.Keys) == 0 && len(policy.Identities) == 0 {
+		return nil, fmt.Errorf("neither %s nor %s is set", PolicyKeysKey, PolicyIdentitiesKey)
+	}
+	return policy, nil
+}
+
+// matches returns true if the identity matches the issuer and the subjects of a certificate
+func (i *Identity) matches(issuer string, subjects []string) bool {
+	if issuer != i.Issuer {
+		return false
+	}
+	for _, subject := range subjects {
+		if i.Subject != "" && subject == i.Subject {
+			return true
+		}
+		if i.subjectRegExp != nil && i.subjectRegExp.MatchString(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePublicKeys parses the PEM encoded public keys of data
+func parsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			if len(bytes.TrimSpace(data)) != 0 {
+				return nil, fmt.Errorf("failed to decode PEM encoded public key")
+			}
+			return keys, nil
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+}
+
+// parseCertificates parses the PEM encoded certificates of data, self-signed certificates are
+// returned as roots and the other ones as intermediates
+func parseCertificates(data []byte) (*x509.CertPool, []*x509.Certificate, error) {
+	var roots *x509.CertPool
+	var intermediates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			if len(bytes.TrimSpace(data)) != 0 {
+				return nil, nil, fmt.Errorf("failed to decode PEM encoded certificate")
+			}
+			return roots, intermediates, nil
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		if cert.CheckSignatureFrom(cert) == nil {
+			if roots == nil {
+				roots = x509.NewCertPool()
+			}
+			roots.AddCert(cert)
+			continue
+		}
+		intermediates = append(intermediates, cert)
+	}
+}
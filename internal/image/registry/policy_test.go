@@ -0,0 +1,124 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKey returns an ECDSA key and the PEM encoding of its public key
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestParsePolicy(t *testing.T) {
+	_, key := newTestKey(t)
+	_, otherKey := newTestKey(t)
+	_, rekorKey := newTestKey(t)
+	ca := newTestCA(t)
+
+	testCases := []struct {
+		description string
+		data        map[string][]byte
+		keys        int
+		identities  int
+		errorMsg    string
+	}{
+		{
+			description: "public keys",
+			data:        map[string][]byte{PolicyKeysKey: append(key, otherKey...)},
+			keys:        2,
+		},
+		{
+			description: "keyless identities",
+			data: map[string][]byte{
+				PolicyIdentitiesKey:   []byte("- issuer: https://accounts.example.com\n  subject: release@example.com\n- issuer: https://token.actions.githubusercontent.com\n  subjectRegExp: ^https://github.com/NVIDIA/.*$\n"),
+				PolicyCertificatesKey: ca.certPEM,
+				PolicyRekorKeyKey:     rekorKey,
+			},
+			identities: 2,
+		},
+		{
+			description: "empty policy",
+			data:        map[string][]byte{},
+			errorMsg:    "neither cosign.pub nor identities is set",
+		},
+		{
+			description: "invalid public key",
+			data:        map[string][]byte{PolicyKeysKey: []byte("not a key")},
+			errorMsg:    "invalid cosign.pub",
+		},
+		{
+			description: "identity without subject",
+			data: map[string][]byte{
+				PolicyIdentitiesKey:   []byte("- issuer: https://accounts.example.com\n"),
+				PolicyCertificatesKey: ca.certPEM,
+				PolicyRekorKeyKey:     rekorKey,
+			},
+			errorMsg: "neither subject nor subjectRegExp of identity 0 is set",
+		},
+		{
+			description: "unknown identity field",
+			data: map[string][]byte{
+				PolicyIdentitiesKey:   []byte("- issuer: https://accounts.example.com\n  email: release@example.com\n"),
+				PolicyCertificatesKey: ca.certPEM,
+				PolicyRekorKeyKey:     rekorKey,
+			},
+			errorMsg: "invalid identities",
+		},
+		{
+			description: "identities without root certificates",
+			data: map[string][]byte{
+				PolicyIdentitiesKey: []byte("- issuer: https://accounts.example.com\n  subject: release@example.com\n"),
+				PolicyRekorKeyKey:   rekorKey,
+			},
+			errorMsg: "fulcio.crt must hold the root certificates",
+		},
+		{
+			description: "identities without transparency log key",
+			data: map[string][]byte{
+				PolicyIdentitiesKey:   []byte("- issuer: https://accounts.example.com\n  subject: release@example.com\n"),
+				PolicyCertificatesKey: ca.certPEM,
+			},
+			errorMsg: "rekor.pub must hold the public key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			policy, err := ParsePolicy("test", tc.data)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, policy.Keys, tc.keys)
+			require.Len(t, policy.Identities, tc.identities)
+		})
+	}
+}
@@ -0,0 +1,435 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/regclient/regclient/types/manifest"
+	"github.com/regclient/regclient/types/ref"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// simpleSigningMediaType is the media type of the layers of cosign signature manifests
+	simpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// simpleSigningType is the type of the payload of cosign image signatures
+	simpleSigningType = "cosign container image signature"
+
+	signatureAnnotation   = "dev.cosignproject.cosign/signature"
+	certificateAnnotation = "dev.sigstore.cosign/certificate"
+	chainAnnotation       = "dev.sigstore.cosign/chain"
+	bundleAnnotation      = "dev.sigstore.cosign/bundle"
+
+	// maxPayloadSize bounds the size of the signature payloads fetched from the registries
+	maxPayloadSize = 1 << 20
+)
+
+var (
+	// oidIssuer and oidIssuerV2 are the certificate extensions of Fulcio holding the OIDC issuer of the signer
+	oidIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// VerificationError reports an image whose signature could not be verified
+type VerificationError struct {
+	// Image is the image which failed verification, empty if no image could be verified
+	Image string
+	Err   error
+}
+
+func (e *VerificationError) Error() string {
+	if e.Image == "" {
+		return fmt.Sprintf("image verification failed: %v", e.Err)
+	}
+	return fmt.Sprintf("signature verification of image %s failed: %v", e.Image, e.Err)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+// IsVerificationError returns true if err reports an image which could not be verified
+func IsVerificationError(err error) bool {
+	var verificationErr *VerificationError
+	return errors.As(err, &verificationErr)
+}
+
+// SignatureVerifier verifies the cosign signatures of images, stored in the registries
+// under the tag sha256-<digest>.sig of the image repository
+type SignatureVerifier struct {
+	client *Client
+
+	mu sync.Mutex
+	// verified records the images verified per policy
+	verified map[string]bool
+}
+
+// NewSignatureVerifier creates a SignatureVerifier fetching the signatures with client
+func NewSignatureVerifier(client *Client) *SignatureVerifier {
+	return &SignatureVerifier{
+		client:   client,
+		verified: map[string]bool{},
+	}
+}
+
+// Verify verifies that the image, which must include a digest, is signed per the policy
+func (v *SignatureVerifier) Verify(ctx context.Context, policy *Policy, image string) error {
+	key := policy.ID + "|" + image
+	v.mu.Lock()
+	verified := v.verified[key]
+	v.mu.Unlock()
+	if verified {
+		return nil
+	}
+
+	if err := v.verify(ctx, policy, image); err != nil {
+		return &VerificationError{Image: image, Err: err}
+	}
+
+	v.mu.Lock()
+	v.verified[key] = true
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *SignatureVerifier) verify(ctx context.Context, policy *Policy, image string) error {
+	r, err := ref.New(image)
+	if err != nil {
+		return fmt.Errorf("failed to construct an image reference: %w", err)
+	}
+	if r.Digest == "" {
+		return fmt.Errorf("image is not resolved to a digest")
+	}
+	client := v.client.get(ctx)
+	defer client.Close(ctx, r)
+
+	sigRef := r
+	sigRef.Tag = strings.Replace(r.Digest, ":", "-", 1) + ".sig"
+	sigRef.Digest = ""
+	sigRef.Reference = sigRef.CommonName()
+
+	m, err := client.ManifestGet(ctx, sigRef)
+	if err != nil {
+		return fmt.Errorf("failed to get signature %s: %w", sigRef.CommonName(), err)
+	}
+	imager, ok := m.(manifest.Imager)
+	if !ok {
+		return fmt.Errorf("unexpected media type %s of signature %s", m.GetDescriptor().MediaType, sigRef.CommonName())
+	}
+	layers, err := imager.GetLayers()
+	if err != nil {
+		return fmt.Errorf("failed to get the layers of signature %s: %w", sigRef.CommonName(), err)
+	}
+
+	var errs []error
+	for _, layer := range layers {
+		if layer.MediaType != simpleSigningMediaType {
+			continue
+		}
+		blob, err := client.BlobGet(ctx, sigRef, layer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get signature payload %s: %w", layer.Digest, err))
+			continue
+		}
+		payload, err := io.ReadAll(io.LimitReader(blob, maxPayloadSize))
+		blob.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read signature payload %s: %w", layer.Digest, err))
+			continue
+		}
+		if layer.Digest.String() != fmt.Sprintf("sha256:%x", sha256.Sum256(payload)) {
+			errs = append(errs, fmt.Errorf("digest of signature payload %s does not match", layer.Digest))
+			continue
+		}
+		err = verifySignature(policy, r.Digest, layer.Annotations, payload)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no signature found in %s", sigRef.CommonName())
+	}
+	return errors.Join(errs...)
+}
+
+// PolicyVerifier verifies the images per a policy, resolving their tags to digests first
+type PolicyVerifier struct {
+	Policy     *Policy
+	Digests    *DigestResolver
+	Signatures *SignatureVerifier
+}
+
+// VerifyImage verifies the signature of the image
+func (v *PolicyVerifier) VerifyImage(ctx context.Context, image string) error {
+	if image == "" {
+		return nil
+	}
+	pinned, err := v.Digests.Resolve(ctx, image)
+	if err != nil {
+		return &VerificationError{Image: image, Err: err}
+	}
+	return v.Signatures.Verify(ctx, v.Policy, pinned)
+}
+
+// VerifyPodSpec verifies the signatures of the images of the containers and init containers of the pod spec
+func (v *PolicyVerifier) VerifyPodSpec(ctx context.Context, spec *corev1.PodSpec) error {
+	return updatePodSpecImages(spec.DeepCopy(), func(image string) (string, error) {
+		return image, v.VerifyImage(ctx, image)
+	})
+}
+
+// simpleSigningPayload is the payload signed by cosign for an image
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// verifySignature verifies the signature of the payload of a signature layer with the given
+// annotations, and that the payload is signing the image digest
+func verifySignature(policy *Policy, digest string, annotations map[string]string, payload []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(annotations[signatureAnnotation])
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("invalid signature annotation")
+	}
+
+	verified := false
+	for _, key := range policy.Keys {
+		if verifyWithKey(key, payload, sig) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		if annotations[certificateAnnotation] == "" || len(policy.Identities) == 0 {
+			return fmt.Errorf("signature not verified by any key")
+		}
+		if err := verifyKeyless(policy, annotations, payload, sig); err != nil {
+			return err
+		}
+	}
+
+	p := &simpleSigningPayload{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if p.Critical.Type != simpleSigningType {
+		return fmt.Errorf("unexpected signature payload type %q", p.Critical.Type)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signature is for digest %s", p.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// verifyWithKey verifies the signature of the payload with the public key
+func verifyWithKey(key crypto.PublicKey, payload []byte, sig []byte) error {
+	hash := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, hash[:], sig) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil {
+			return nil
+		}
+		return rsa.VerifyPSS(k, crypto.SHA256, hash[:], sig, nil)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, sig) {
+			return fmt.Errorf("invalid ED25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// rekorBundle is the transparency log entry of a keyless signature
+type rekorBundle struct {
+	SignedEntryTimestamp []byte `json:"SignedEntryTimestamp"`
+	Payload              struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogIndex       int64  `json:"logIndex"`
+		LogID          string `json:"logID"`
+	} `json:"Payload"`
+}
+
+// hashedRekord is the body of the transparency log entry of a signature
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content []byte `json:"content"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifyKeyless verifies a keyless signature of the payload: the certificate of the signer must
+// be issued by the certificate authorities of the policy to one of its identities when the signature
+// was recorded in the transparency log, and the signature must be verified by the certificate
+func verifyKeyless(policy *Policy, annotations map[string]string, payload []byte, sig []byte) error {
+	block, _ := pem.Decode([]byte(annotations[certificateAnnotation]))
+	if block == nil {
+		return fmt.Errorf("invalid certificate annotation")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid certificate annotation: %w", err)
+	}
+
+	integratedTime, err := verifyBundle(policy.RekorKey, annotations[bundleAnnotation], payload, sig)
+	if err != nil {
+		return err
+	}
+
+	// the chain recorded with the signature only provides intermediate certificates,
+	// which must still chain up to the roots of the policy
+	_, chain, err := parseCertificates([]byte(annotations[chainAnnotation]))
+	if err != nil {
+		return fmt.Errorf("invalid certificate chain annotation: %w", err)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range append(chain, policy.Intermediates...) {
+		intermediates.AddCert(c)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         policy.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   integratedTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return fmt.Errorf("certificate of the signer not trusted: %w", err)
+	}
+
+	issuer, err := getCertificateIssuer(cert)
+	if err != nil {
+		return err
+	}
+	subjects := append([]string{}, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		subjects = append(subjects, uri.String())
+	}
+	matched := false
+	for i := range policy.Identities {
+		if policy.Identities[i].matches(issuer, subjects) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return fmt.Errorf("signer %v of issuer %s does not match any identity", subjects, issuer)
+	}
+
+	if err := verifyWithKey(cert.PublicKey, payload, sig); err != nil {
+		return fmt.Errorf("signature not verified by the certificate of the signer: %w", err)
+	}
+	return nil
+}
+
+// verifyBundle verifies the transparency log bundle of the signature of the payload and
+// returns the time the signature was recorded
+func verifyBundle(rekorKey crypto.PublicKey, annotation string, payload []byte, sig []byte) (time.Time, error) {
+	if annotation == "" {
+		return time.Time{}, fmt.Errorf("keyless signature not recorded in the transparency log")
+	}
+	bundle := &rekorBundle{}
+	if err := json.Unmarshal([]byte(annotation), bundle); err != nil {
+		return time.Time{}, fmt.Errorf("invalid bundle annotation: %w", err)
+	}
+
+	// the signed entry timestamp signs the canonical JSON of the bundle payload
+	canonical := &bytes.Buffer{}
+	encoder := json.NewEncoder(canonical)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(map[string]interface{}{
+		"body":           bundle.Payload.Body,
+		"integratedTime": bundle.Payload.IntegratedTime,
+		"logIndex":       bundle.Payload.LogIndex,
+		"logID":          bundle.Payload.LogID,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid bundle annotation: %w", err)
+	}
+	if err := verifyWithKey(rekorKey, bytes.TrimSuffix(canonical.Bytes(), []byte("\n")), bundle.SignedEntryTimestamp); err != nil {
+		return time.Time{}, fmt.Errorf("signed entry timestamp of the transparency log not verified: %w", err)
+	}
+
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid bundle body: %w", err)
+	}
+	entry := &hashedRekord{}
+	if err := json.Unmarshal(body, entry); err != nil {
+		return time.Time{}, fmt.Errorf("invalid bundle body: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	if entry.Kind != "hashedrekord" || entry.Spec.Data.Hash.Algorithm != "sha256" ||
+		entry.Spec.Data.Hash.Value != hex.EncodeToString(hash[:]) || !bytes.Equal(entry.Spec.Signature.Content, sig) {
+		return time.Time{}, fmt.Errorf("transparency log entry does not record the signature")
+	}
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+// getCertificateIssuer returns the OIDC issuer of the signer recorded in a Fulcio certificate
+func getCertificateIssuer(cert *x509.Certificate) (string, error) {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err != nil {
+				return "", fmt.Errorf("invalid issuer extension of the certificate of the signer: %w", err)
+			}
+			return issuer, nil
+		case ext.Id.Equal(oidIssuer):
+			return string(ext.Value), nil
+		}
+	}
+	return "", fmt.Errorf("no issuer extension in the certificate of the signer")
+}
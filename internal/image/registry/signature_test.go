@@ -0,0 +1,455 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/regclient/regclient"
+	"github.com/regclient/regclient/config"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+// testRegistry is an in-process OCI registry serving the manifests and blobs pushed to it
+type testRegistry struct {
+	server *httptest.Server
+	host   string
+
+	mu        sync.Mutex
+	manifests map[string][]byte
+	blobs     map[string][]byte
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	r := &testRegistry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.server.Close)
+	u, err := url.Parse(r.server.URL)
+	require.NoError(t, err)
+	r.host = u.Host
+	return r
+}
+
+func (r *testRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if path == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var content []byte
+	var found bool
+	if i := strings.LastIndex(path, "/manifests/"); i >= 0 {
+		content, found = r.manifests[path[:i]+":"+path[i+len("/manifests/"):]]
+		w.Header().Set("Content-Type", ociManifestMediaType)
+	} else if i := strings.LastIndex(path, "/blobs/"); i >= 0 {
+		content, found = r.blobs[path[i+len("/blobs/"):]]
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", digestOf(content))
+	w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+	if req.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(content)
+}
+
+// client returns a client of the registry
+func (r *testRegistry) client() *Client {
+	return NewRegistryClient(
+		regclient.WithConfigHost(config.Host{Name: r.host, TLS: config.TLSDisabled}),
+		regclient.WithRetryLimit(1))
+}
+
+// pushImage pushes a manifest for the tag of the repository and returns the pinned image
+func (r *testRegistry) pushImage(repository string, tag string) string {
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":"application/vnd.oci.image.config.v1+json","digest":%q,"size":2},"layers":[],"annotations":{"tag":%q}}`,
+		ociManifestMediaType, digestOf([]byte("{}")), repository+":"+tag))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blobs[digestOf([]byte("{}"))] = []byte("{}")
+	r.manifests[repository+":"+tag] = manifest
+	r.manifests[repository+":"+digestOf(manifest)] = manifest
+	return r.host + "/" + repository + ":" + tag + "@" + digestOf(manifest)
+}
+
+// pushSignature pushes a cosign signature manifest for the digest of the repository,
+// with a layer per payload and its annotations
+func (r *testRegistry) pushSignature(repository string, digest string, payload []byte, annotations map[string]string) {
+	layers, err := json.Marshal([]map[string]interface{}{{
+		"mediaType":   simpleSigningMediaType,
+		"digest":      digestOf(payload),
+		"size":        len(payload),
+		"annotations": annotations,
+	}})
+	if err != nil {
+		panic(err)
+	}
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":"application/vnd.oci.image.config.v1+json","digest":%q,"size":2},"layers":%s}`,
+		ociManifestMediaType, digestOf([]byte("{}")), layers))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blobs[digestOf(payload)] = payload
+	r.manifests[repository+":"+strings.Replace(digest, ":", "-", 1)+".sig"] = manifest
+}
+
+func digestOf(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+// newPayload returns the cosign signature payload of the image digest
+func newPayload(digest string) []byte {
+	return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"test"},"image":{"docker-manifest-digest":%q},"type":%q},"optional":null}`,
+		digest, simpleSigningType))
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) []byte {
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+	return sig
+}
+
+// testCA is a certificate authority issuing code signing certificates to keyless signers
+type testCA struct {
+	key     *ecdsa.PrivateKey
+	cert    *x509.Certificate
+	certPEM []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{key: key, cert: cert, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a short-lived certificate of the signer, valid around the given time
+func (ca *testCA) issue(t *testing.T, signer *ecdsa.PrivateKey, email string, issuer string, at time.Time) []byte {
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       at.Add(-time.Minute),
+		NotAfter:        at.Add(5 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses:  []string{email},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuer, Value: []byte(issuer)}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &signer.PublicKey, ca.key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// newBundle returns the transparency log bundle of the signature of the payload, signed with the log key
+func newBundle(t *testing.T, logKey *ecdsa.PrivateKey, payload []byte, sig []byte, integratedTime time.Time) string {
+	hash := sha256.Sum256(payload)
+	body, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]interface{}{
+			"data":      map[string]interface{}{"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(hash[:])}},
+			"signature": map[string]interface{}{"content": sig},
+		},
+	})
+	require.NoError(t, err)
+	entry := map[string]interface{}{
+		"body":           base64.StdEncoding.EncodeToString(body),
+		"integratedTime": integratedTime.Unix(),
+		"logIndex":       42,
+		"logID":          "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d",
+	}
+	canonical := &bytes.Buffer{}
+	encoder := json.NewEncoder(canonical)
+	encoder.SetEscapeHTML(false)
+	require.NoError(t, encoder.Encode(entry))
+	bundle, err := json.Marshal(map[string]interface{}{
+		"SignedEntryTimestamp": sign(t, logKey, bytes.TrimSuffix(canonical.Bytes(), []byte("\n"))),
+		"Payload":              entry,
+	})
+	require.NoError(t, err)
+	return string(bundle)
+}
+
+func TestSignatureVerifierKeys(t *testing.T) {
+	registry := newTestRegistry(t)
+	key, keyPEM := newTestKey(t)
+	otherKey, _ := newTestKey(t)
+	policy, err := ParsePolicy("test", map[string][]byte{PolicyKeysKey: keyPEM})
+	require.NoError(t, err)
+
+	signed := registry.pushImage("nvidia/driver", "550.54.15")
+	digest := signed[strings.LastIndex(signed, "@")+1:]
+	payload := newPayload(digest)
+	registry.pushSignature("nvidia/driver", digest, payload, map[string]string{
+		signatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	wrongKey := registry.pushImage("nvidia/wrong-key", "v1")
+	digest = wrongKey[strings.LastIndex(wrongKey, "@")+1:]
+	payload = newPayload(digest)
+	registry.pushSignature("nvidia/wrong-key", digest, payload, map[string]string{
+		signatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, otherKey, payload)),
+	})
+
+	// the signature of another image is not valid for the image
+	otherImage := registry.pushImage("nvidia/other-image", "v1")
+	digest = otherImage[strings.LastIndex(otherImage, "@")+1:]
+	payload = newPayload(signed[strings.LastIndex(signed, "@")+1:])
+	registry.pushSignature("nvidia/other-image", digest, payload, map[string]string{
+		signatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	unsigned := registry.pushImage("nvidia/unsigned", "v1")
+
+	testCases := []struct {
+		description string
+		image       string
+		errorMsg    string
+	}{
+		{
+			description: "signed image",
+			image:       signed,
+		},
+		{
+			description: "image signed with another key",
+			image:       wrongKey,
+			errorMsg:    "signature not verified by any key",
+		},
+		{
+			description: "signature of another image",
+			image:       otherImage,
+			errorMsg:    "signature is for digest",
+		},
+		{
+			description: "unsigned image",
+			image:       unsigned,
+			errorMsg:    "failed to get signature",
+		},
+		{
+			description: "image without digest",
+			image:       registry.host + "/nvidia/driver:550.54.15",
+			errorMsg:    "image is not resolved to a digest",
+		},
+	}
+
+	verifier := NewSignatureVerifier(registry.client())
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := verifier.Verify(context.TODO(), policy, tc.image)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				require.True(t, IsVerificationError(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSignatureVerifierTamperedPayload(t *testing.T) {
+	registry := newTestRegistry(t)
+	key, keyPEM := newTestKey(t)
+	policy, err := ParsePolicy("test", map[string][]byte{PolicyKeysKey: keyPEM})
+	require.NoError(t, err)
+
+	img := registry.pushImage("nvidia/driver", "550.54.15")
+	digest := img[strings.LastIndex(img, "@")+1:]
+	payload := newPayload(digest)
+	registry.pushSignature("nvidia/driver", digest, payload, map[string]string{
+		signatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	verifier := NewSignatureVerifier(registry.client())
+	require.NoError(t, verifier.Verify(context.TODO(), policy, img))
+
+	// replace the payload with one signing the same digest with other content
+	tampered := bytes.Replace(payload, []byte(`"test"`), []byte(`"evil"`), 1)
+	registry.mu.Lock()
+	registry.blobs[digestOf(payload)] = tampered
+	registry.mu.Unlock()
+
+	// a verified image is not verified again with the same policy
+	require.NoError(t, verifier.Verify(context.TODO(), policy, img))
+
+	policy.ID = "updated"
+	err = verifier.Verify(context.TODO(), policy, img)
+	require.ErrorContains(t, err, "failed to read signature payload")
+}
+
+func TestSignatureVerifierKeyless(t *testing.T) {
+	registry := newTestRegistry(t)
+	ca := newTestCA(t)
+	logKey, logKeyPEM := newTestKey(t)
+	policy, err := ParsePolicy("test", map[string][]byte{
+		PolicyIdentitiesKey:   []byte("- issuer: https://accounts.example.com\n  subjectRegExp: ^release@example\\.com$\n"),
+		PolicyCertificatesKey: ca.certPEM,
+		PolicyRekorKeyKey:     logKeyPEM,
+	})
+	require.NoError(t, err)
+
+	// the certificate of the signer expired since the signature was recorded in the transparency log
+	signedAt := time.Now().Add(-30 * time.Minute)
+	push := func(repository string, email string, issuer string, bundle func(payload, sig []byte) string) string {
+		img := registry.pushImage(repository, "v1")
+		digest := img[strings.LastIndex(img, "@")+1:]
+		payload := newPayload(digest)
+		signer, _ := newTestKey(t)
+		sig := sign(t, signer, payload)
+		registry.pushSignature(repository, digest, payload, map[string]string{
+			signatureAnnotation:   base64.StdEncoding.EncodeToString(sig),
+			certificateAnnotation: string(ca.issue(t, signer, email, issuer, signedAt)),
+			bundleAnnotation:      bundle(payload, sig),
+		})
+		return img
+	}
+	recorded := func(payload, sig []byte) string {
+		return newBundle(t, logKey, payload, sig, signedAt)
+	}
+
+	otherLogKey, _ := newTestKey(t)
+	testCases := []struct {
+		description string
+		image       string
+		errorMsg    string
+	}{
+		{
+			description: "signed by identity",
+			image:       push("nvidia/signed", "release@example.com", "https://accounts.example.com", recorded),
+		},
+		{
+			description: "signed by another subject",
+			image:       push("nvidia/other-subject", "someone@example.com", "https://accounts.example.com", recorded),
+			errorMsg:    "does not match any identity",
+		},
+		{
+			description: "signed by identity of another issuer",
+			image:       push("nvidia/other-issuer", "release@example.com", "https://accounts.example.org", recorded),
+			errorMsg:    "does not match any identity",
+		},
+		{
+			description: "not recorded in the transparency log",
+			image: push("nvidia/not-recorded", "release@example.com", "https://accounts.example.com", func(_, _ []byte) string {
+				return ""
+			}),
+			errorMsg: "not recorded in the transparency log",
+		},
+		{
+			description: "bundle not signed by the transparency log",
+			image: push("nvidia/forged-bundle", "release@example.com", "https://accounts.example.com", func(payload, sig []byte) string {
+				return newBundle(t, otherLogKey, payload, sig, signedAt)
+			}),
+			errorMsg: "signed entry timestamp of the transparency log not verified",
+		},
+		{
+			description: "recorded after the certificate expired",
+			image: push("nvidia/expired", "release@example.com", "https://accounts.example.com", func(payload, sig []byte) string {
+				return newBundle(t, logKey, payload, sig, time.Now())
+			}),
+			errorMsg: "certificate of the signer not trusted",
+		},
+	}
+
+	verifier := NewSignatureVerifier(registry.client())
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := verifier.Verify(context.TODO(), policy, tc.image)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPolicyVerifierVerifyPodSpec(t *testing.T) {
+	registry := newTestRegistry(t)
+	key, keyPEM := newTestKey(t)
+	policy, err := ParsePolicy("test", map[string][]byte{PolicyKeysKey: keyPEM})
+	require.NoError(t, err)
+
+	img := registry.pushImage("nvidia/k8s-device-plugin", "v0.15.0")
+	digest := img[strings.LastIndex(img, "@")+1:]
+	payload := newPayload(digest)
+	registry.pushSignature("nvidia/k8s-device-plugin", digest, payload, map[string]string{
+		signatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+	registry.pushImage("nvidia/gpu-operator-validator", "v24.3.0")
+
+	client := registry.client()
+	verifier := &PolicyVerifier{
+		Policy:     policy,
+		Digests:    NewDigestResolver(RegistryDigestLookup(client)),
+		Signatures: NewSignatureVerifier(client),
+	}
+
+	// the tags of the images are resolved to the digests to verify
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "nvidia-device-plugin", Image: registry.host + "/nvidia/k8s-device-plugin:v0.15.0"}},
+	}
+	require.NoError(t, verifier.VerifyPodSpec(context.TODO(), spec))
+	require.Equal(t, registry.host+"/nvidia/k8s-device-plugin:v0.15.0", spec.Containers[0].Image)
+
+	spec.InitContainers = []corev1.Container{{Name: "toolkit-validation", Image: registry.host + "/nvidia/gpu-operator-validator:v24.3.0"}}
+	err = verifier.VerifyPodSpec(context.TODO(), spec)
+	require.ErrorContains(t, err, "gpu-operator-validator:v24.3.0")
+	require.True(t, IsVerificationError(err))
+}
@@ -0,0 +1,110 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+/*
+Package patch applies the user provided patches to the pod templates of the operand DaemonSets
+rendered by the operator.
+*/
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// Validate verifies that the patch p can be decoded and, for a strategic merge patch, that it
+// results in a valid pod template once applied to an empty one
+func Validate(p *gpuv1.PodTemplatePatch) error {
+	if p == nil {
+		return nil
+	}
+	switch p.GetType() {
+	case gpuv1.PodTemplatePatchStrategic:
+		return PodTemplate(&corev1.PodTemplateSpec{}, p)
+	case gpuv1.PodTemplatePatchJSON:
+		// the paths of a JSON patch are only resolved against the rendered pod template
+		_, err := decodeJSONPatch(p.Patch)
+		return err
+	default:
+		return fmt.Errorf("unsupported patch type %q", p.Type)
+	}
+}
+
+// PodTemplate applies the patch p to template. The patched pod template is decoded strictly,
+// so that a patch introducing unknown fields is rejected and template is left unchanged.
+func PodTemplate(template *corev1.PodTemplateSpec, p *gpuv1.PodTemplatePatch) error {
+	if p == nil {
+		return nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to encode pod template: %w", err)
+	}
+
+	var patched []byte
+	switch p.GetType() {
+	case gpuv1.PodTemplatePatchStrategic:
+		patch, err := yaml.YAMLToJSON([]byte(p.Patch))
+		if err != nil {
+			return fmt.Errorf("failed to decode strategic merge patch: %w", err)
+		}
+		patched, err = strategicpatch.StrategicMergePatch(original, patch, corev1.PodTemplateSpec{})
+		if err != nil {
+			return fmt.Errorf("failed to apply strategic merge patch: %w", err)
+		}
+	case gpuv1.PodTemplatePatchJSON:
+		patch, err := decodeJSONPatch(p.Patch)
+		if err != nil {
+			return err
+		}
+		patched, err = patch.Apply(original)
+		if err != nil {
+			return fmt.Errorf("failed to apply JSON patch: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported patch type %q", p.Type)
+	}
+
+	result := corev1.PodTemplateSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return fmt.Errorf("patched pod template is invalid: %w", err)
+	}
+	*template = result
+	return nil
+}
+
+func decodeJSONPatch(data string) (jsonpatch.Patch, error) {
+	patch, err := yaml.YAMLToJSON([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON patch: %w", err)
+	}
+	decoded, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON patch: %w", err)
+	}
+	return decoded, nil
+}
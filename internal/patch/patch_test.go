@@ -0,0 +1,143 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func testTemplate() *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nvidia-dcgm-exporter"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "nvidia-dcgm-exporter",
+					Image: "nvcr.io/nvidia/k8s/dcgm-exporter:3.3.0",
+					Args:  []string{"-f", "/etc/dcgm-exporter/dcp-metrics-included.csv"},
+					Env:   []corev1.EnvVar{{Name: "DCGM_EXPORTER_LISTEN", Value: ":9400"}},
+				},
+			},
+		},
+	}
+}
+
+func TestPodTemplate(t *testing.T) {
+	tests := []struct {
+		description string
+		patch       *gpuv1.PodTemplatePatch
+		validate    func(t *testing.T, template *corev1.PodTemplateSpec)
+		err         bool
+	}{
+		{
+			description: "no patch",
+			validate: func(t *testing.T, template *corev1.PodTemplateSpec) {
+				require.Equal(t, testTemplate(), template)
+			},
+		},
+		{
+			description: "strategic merge patch merges containers by name",
+			patch: &gpuv1.PodTemplatePatch{
+				Patch: `
+spec:
+  hostAliases:
+  - ip: 10.0.0.1
+    hostnames: ["mirror.local"]
+  containers:
+  - name: nvidia-dcgm-exporter
+    env:
+    - name: DCGM_EXPORTER_KUBERNETES
+      value: "true"
+  - name: sidecar
+    image: busybox
+`,
+			},
+			validate: func(t *testing.T, template *corev1.PodTemplateSpec) {
+				require.Len(t, template.Spec.HostAliases, 1)
+				require.Len(t, template.Spec.Containers, 2)
+				ctr := template.Spec.Containers[0]
+				require.Equal(t, "nvidia-dcgm-exporter", ctr.Name)
+				require.Equal(t, "nvcr.io/nvidia/k8s/dcgm-exporter:3.3.0", ctr.Image)
+				require.ElementsMatch(t, []corev1.EnvVar{
+					{Name: "DCGM_EXPORTER_LISTEN", Value: ":9400"},
+					{Name: "DCGM_EXPORTER_KUBERNETES", Value: "true"},
+				}, ctr.Env)
+				require.Equal(t, "sidecar", template.Spec.Containers[1].Name)
+			},
+		},
+		{
+			description: "JSON patch",
+			patch: &gpuv1.PodTemplatePatch{
+				Type: gpuv1.PodTemplatePatchJSON,
+				Patch: `
+- op: add
+  path: /spec/containers/0/args/-
+  value: "-v"
+- op: add
+  path: /spec/dnsPolicy
+  value: ClusterFirstWithHostNet
+`,
+			},
+			validate: func(t *testing.T, template *corev1.PodTemplateSpec) {
+				require.Equal(t, []string{"-f", "/etc/dcgm-exporter/dcp-metrics-included.csv", "-v"}, template.Spec.Containers[0].Args)
+				require.Equal(t, corev1.DNSClusterFirstWithHostNet, template.Spec.DNSPolicy)
+			},
+		},
+		{
+			description: "unknown field",
+			patch:       &gpuv1.PodTemplatePatch{Patch: "spec:\n  hostAliasses: []\n"},
+			err:         true,
+		},
+		{
+			description: "JSON patch with missing path",
+			patch: &gpuv1.PodTemplatePatch{
+				Type:  gpuv1.PodTemplatePatchJSON,
+				Patch: `[{"op": "replace", "path": "/spec/initContainers/0/image", "value": "busybox"}]`,
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			template := testTemplate()
+			err := PodTemplate(template, tc.patch)
+			if tc.err {
+				require.Error(t, err)
+				require.Equal(t, testTemplate(), template)
+				return
+			}
+			require.NoError(t, err)
+			tc.validate(t, template)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(nil))
+	require.NoError(t, Validate(&gpuv1.PodTemplatePatch{Patch: "spec:\n  priorityClassName: high\n"}))
+	require.NoError(t, Validate(&gpuv1.PodTemplatePatch{Type: gpuv1.PodTemplatePatchJSON, Patch: `[{"op": "remove", "path": "/spec/tolerations"}]`}))
+	require.Error(t, Validate(&gpuv1.PodTemplatePatch{Patch: "spec: ["}))
+	require.Error(t, Validate(&gpuv1.PodTemplatePatch{Type: gpuv1.PodTemplatePatchJSON, Patch: `{"op": "remove"}`}))
+	require.Error(t, Validate(&gpuv1.PodTemplatePatch{Type: "merge", Patch: "spec: {}"}))
+}
@@ -0,0 +1,265 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Client is a client.Client which records the changes to objects instead of
+// writing them to the API server. Reads are served by the wrapped client, so
+// that the live objects are compared against the desired ones.
+type Client struct {
+	client.Client
+
+	mu      sync.Mutex
+	changes []Change
+}
+
+var _ client.Client = (*Client)(nil)
+
+// NewClient returns a Client recording the changes made through the given client
+func NewClient(c client.Client) *Client {
+	return &Client{Client: c}
+}
+
+// Plan returns the changes recorded so far
+func (c *Client) Plan() Plan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Plan{Changes: append([]Change{}, c.changes...)}
+}
+
+// Create records the creation of obj, if it does not exist yet
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	_, err = c.getLive(ctx, obj)
+	if err == nil {
+		return apierrors.NewAlreadyExists(groupResource(gvk), obj.GetName())
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	c.record(Change{Action: ActionCreate, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	return nil
+}
+
+// Update records the update of obj, if any of its fields differ from the live object
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	live, err := c.getLive(ctx, obj)
+	if err != nil {
+		return err
+	}
+	fields, err := diffObjects(obj, live)
+	if err != nil {
+		return err
+	}
+	if len(fields) != 0 {
+		c.record(Change{Action: ActionUpdate, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Fields: fields})
+	}
+	return nil
+}
+
+// Patch records the update of obj, if any of the patched fields differ from the live object.
+// Only server-side apply, JSON merge and strategic merge patches are summarized at the field
+// level. Applying an object which does not exist yet is recorded as its creation.
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	live, err := c.getLive(ctx, obj)
+	if patch.Type() == types.ApplyPatchType && apierrors.IsNotFound(err) {
+		c.record(Change{Action: ActionCreate, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var fields []string
+	switch patch.Type() {
+	case types.ApplyPatchType:
+		fields, err = diffObjects(obj, live)
+		if err != nil {
+			return err
+		}
+	case types.MergePatchType, types.StrategicMergePatchType:
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		patched := map[string]interface{}{}
+		if err := json.Unmarshal(data, &patched); err != nil {
+			return err
+		}
+		current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+		if err != nil {
+			return err
+		}
+		fields = diff("", patched, current)
+	case types.JSONPatchType:
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		fields, err = jsonPatchFields(data)
+		if err != nil {
+			return err
+		}
+	default:
+		fields = []string{string(patch.Type())}
+	}
+	if len(fields) != 0 {
+		c.record(Change{Action: ActionUpdate, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Fields: fields})
+	}
+	return nil
+}
+
+// managedFieldsPath is the path of the managed fields of an object in a JSON patch
+const managedFieldsPath = "/metadata/managedFields"
+
+// jsonPatchFields returns the paths changed by a JSON patch. The changes of the managed fields only,
+// e.g. their migration to server-side apply, are not reported as they do not change the object.
+func jsonPatchFields(data []byte) ([]string, error) {
+	ops := []struct {
+		Path string `json:"path"`
+	}{}
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	for _, op := range ops {
+		if op.Path == managedFieldsPath || strings.HasPrefix(op.Path, managedFieldsPath+"/") {
+			continue
+		}
+		fields = append(fields, op.Path)
+	}
+	return fields, nil
+}
+
+// Delete records the deletion of obj, if it exists and is not being deleted already
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	live, err := c.getLive(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if live.GetDeletionTimestamp() == nil {
+		c.record(Change{Action: ActionDelete, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	}
+	return nil
+}
+
+// DeleteAllOf is a no-op, as the objects to be deleted cannot be reported individually
+func (c *Client) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	return nil
+}
+
+// Status returns a writer which discards all status updates
+func (c *Client) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+// SubResource returns a client for the given subresource which discards all writes
+func (c *Client) SubResource(subResource string) client.SubResourceClient {
+	return &subResourceClient{SubResourceClient: c.Client.SubResource(subResource)}
+}
+
+func (c *Client) getLive(ctx context.Context, obj client.Object) (client.Object, error) {
+	live, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil, apierrors.NewBadRequest("object does not implement client.Object")
+	}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		return nil, err
+	}
+	return live, nil
+}
+
+// record adds the change to the plan, merging it with a previous change of the same object
+func (c *Client) record(change Change) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.changes {
+		prev := &c.changes[i]
+		if prev.Kind != change.Kind || prev.Namespace != change.Namespace || prev.Name != change.Name {
+			continue
+		}
+		if prev.Action == ActionUpdate && change.Action == ActionUpdate {
+			prev.Fields = mergeFields(prev.Fields, change.Fields)
+		} else {
+			*prev = change
+		}
+		return
+	}
+	c.changes = append(c.changes, change)
+}
+
+func mergeFields(fields []string, added []string) []string {
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		seen[f] = true
+	}
+	for _, f := range added {
+		if !seen[f] {
+			fields = append(fields, f)
+			seen[f] = true
+		}
+	}
+	return fields
+}
+
+func groupResource(gvk schema.GroupVersionKind) schema.GroupResource {
+	return schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}
+}
+
+// subResourceClient discards all writes to a subresource
+type subResourceClient struct {
+	client.SubResourceClient
+}
+
+func (s *subResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return nil
+}
+
+func (s *subResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return nil
+}
+
+func (s *subResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return nil
+}
@@ -0,0 +1,151 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newDaemonSet(image string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nvidia-device-plugin-daemonset",
+			Namespace:   "gpu-operator",
+			Annotations: map[string]string{"nvidia.com/last-applied-hash": image},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "device-plugin", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	live := newDaemonSet("device-plugin:v1")
+	// defaults set by the API server are not reported as changes
+	live.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/termination-log"
+	stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "gpu-operator"}}
+	config := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "gpu-operator"}, Data: map[string]string{"key": "value"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(live, stale, config).Build()
+	c := NewClient(fakeClient)
+
+	// unchanged object
+	err := c.Update(ctx, newDaemonSet("device-plugin:v1"))
+	require.NoError(t, err)
+	require.Empty(t, c.Plan().Changes)
+
+	// existing object cannot be created
+	err = c.Create(ctx, newDaemonSet("device-plugin:v2"))
+	require.True(t, apierrors.IsAlreadyExists(err))
+
+	err = c.Update(ctx, newDaemonSet("device-plugin:v2"))
+	require.NoError(t, err)
+
+	toolkit := &unstructured.Unstructured{}
+	toolkit.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+	toolkit.SetName("nvidia-container-toolkit-daemonset")
+	toolkit.SetNamespace("gpu-operator")
+	err = c.Create(ctx, toolkit)
+	require.NoError(t, err)
+
+	err = c.Delete(ctx, stale)
+	require.NoError(t, err)
+
+	err = c.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "gpu-operator"}})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// only the patched fields which differ are reported
+	patch := client.RawPatch(types.MergePatchType, []byte(`{"data":{"key":"value","other":"value"}}`))
+	err = c.Patch(ctx, config.DeepCopy(), patch)
+	require.NoError(t, err)
+
+	// the migration of the managed fields to server-side apply does not change the object
+	migration := client.RawPatch(types.JSONPatchType, []byte(`[{"op":"test","path":"/metadata/managedFields","value":[]},{"op":"replace","path":"/metadata/managedFields","value":[]}]`))
+	err = c.Patch(ctx, config.DeepCopy(), migration)
+	require.NoError(t, err)
+	labels := client.RawPatch(types.JSONPatchType, []byte(`[{"op":"add","path":"/metadata/labels","value":{"app":"config"}}]`))
+	err = c.Patch(ctx, config.DeepCopy(), labels)
+	require.NoError(t, err)
+
+	expected := []Change{
+		{
+			Action:    ActionUpdate,
+			Kind:      "DaemonSet",
+			Namespace: "gpu-operator",
+			Name:      "nvidia-device-plugin-daemonset",
+			Fields: []string{
+				"metadata.annotations[nvidia.com/last-applied-hash]",
+				"spec.template.spec.containers[0].image",
+			},
+		},
+		{Action: ActionCreate, Kind: "DaemonSet", Namespace: "gpu-operator", Name: "nvidia-container-toolkit-daemonset"},
+		{Action: ActionDelete, Kind: "ConfigMap", Namespace: "gpu-operator", Name: "stale"},
+		{Action: ActionUpdate, Kind: "ConfigMap", Namespace: "gpu-operator", Name: "config", Fields: []string{"data.other", "/metadata/labels"}},
+	}
+	require.Equal(t, expected, c.Plan().Changes)
+
+	// the live objects are left untouched
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(live), ds))
+	require.Equal(t, "device-plugin:v1", ds.Spec.Template.Spec.Containers[0].Image)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(stale), &corev1.ConfigMap{}))
+	err = fakeClient.Get(ctx, client.ObjectKeyFromObject(toolkit), &appsv1.DaemonSet{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	owner := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "owner", UID: "owner-uid"}}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(owner).Build()
+
+	p := Plan{
+		ObservedGeneration: 2,
+		Changes:            []Change{{Action: ActionDelete, Kind: "DaemonSet", Namespace: "gpu-operator", Name: "ds"}},
+	}
+	require.NoError(t, Publish(ctx, c, scheme.Scheme, owner, "gpu-operator", p))
+	// publishing again updates the ConfigMap
+	require.NoError(t, Publish(ctx, c, scheme.Scheme, owner, "gpu-operator", p))
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "gpu-operator", Name: "namespace-owner-plan"}, cm))
+	require.Len(t, cm.OwnerReferences, 1)
+	require.Equal(t, owner.UID, cm.OwnerReferences[0].UID)
+	require.Equal(t, `changes:
+- action: delete
+  kind: DaemonSet
+  name: ds
+  namespace: gpu-operator
+observedGeneration: 2
+`, cm.Data[ConfigMapDataKey])
+}
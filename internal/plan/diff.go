@@ -0,0 +1,125 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package plan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// diffObjects returns the paths of the fields of desired which differ from current.
+// Only the labels and annotations of the object metadata are compared, and the
+// status is ignored as it is not written by an update.
+func diffObjects(desired runtime.Object, current runtime.Object) ([]string, error) {
+	desiredMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert desired object: %w", err)
+	}
+	currentMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert current object: %w", err)
+	}
+
+	delete(desiredMap, "status")
+	if metadata, ok := desiredMap["metadata"].(map[string]interface{}); ok {
+		desiredMap["metadata"] = map[string]interface{}{
+			"labels":      metadata["labels"],
+			"annotations": metadata["annotations"],
+		}
+	}
+	return diff("", desiredMap, currentMap), nil
+}
+
+// diff returns the paths of the fields set in desired which differ from current.
+// Fields which are only set in current, e.g. defaults set by the API server, are
+// ignored. Lists of different length are reported as a whole.
+func diff(path string, desired interface{}, current interface{}) []string {
+	if desired == nil {
+		return nil
+	}
+
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		c, _ := current.(map[string]interface{})
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var fields []string
+		for _, k := range keys {
+			fields = append(fields, diff(fieldPath(path, k), d[k], c[k])...)
+		}
+		return fields
+	case []interface{}:
+		c, _ := current.([]interface{})
+		if len(d) != len(c) {
+			return []string{path}
+		}
+		var fields []string
+		for i := range d {
+			fields = append(fields, diff(fmt.Sprintf("%s[%d]", path, i), d[i], c[i])...)
+		}
+		return fields
+	default:
+		if !scalarEqual(d, current) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+func fieldPath(path string, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// scalarEqual compares two scalar values, ignoring the type of numbers which
+// depends on how the object was decoded
+func scalarEqual(a interface{}, b interface{}) bool {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		return ok && af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
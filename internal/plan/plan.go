@@ -0,0 +1,115 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AnnotationKey is the annotation which enables the plan mode on a ClusterPolicy
+	// or NVIDIADriver when set to "true". In plan mode, the operand changes are
+	// published to a ConfigMap instead of being applied to the cluster.
+	AnnotationKey = "nvidia.com/gpu-operator.plan"
+	// ConfigMapDataKey is the key holding the plan in the published ConfigMap
+	ConfigMapDataKey = "plan.yaml"
+)
+
+// Action is the type of change planned for an object
+type Action string
+
+const (
+	// ActionCreate indicates the object does not exist and will be created
+	ActionCreate Action = "create"
+	// ActionUpdate indicates the object exists and will be updated
+	ActionUpdate Action = "update"
+	// ActionDelete indicates the object exists and will be deleted
+	ActionDelete Action = "delete"
+)
+
+// Change describes a planned change of a single object
+type Change struct {
+	Action    Action `json:"action"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Fields lists the paths of the fields which will be changed by an update
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Plan describes the changes the operator would apply to reconcile a custom resource
+type Plan struct {
+	// ObservedGeneration is the generation of the custom resource the plan was computed for
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// Changes lists the planned changes in the order they would be applied
+	Changes []Change `json:"changes"`
+	// Error reports the error which prevented the plan from being completed, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Pending returns true if the plan has changes left to apply or could not be completed
+func (p Plan) Pending() bool {
+	return len(p.Changes) > 0 || p.Error != ""
+}
+
+// Enabled returns true if the plan mode is enabled on the given custom resource
+func Enabled(obj metav1.Object) bool {
+	return strings.EqualFold(obj.GetAnnotations()[AnnotationKey], "true")
+}
+
+// ConfigMapName returns the name of the ConfigMap the plan of a custom resource is published to
+func ConfigMapName(kind string, name string) string {
+	return fmt.Sprintf("%s-%s-plan", strings.ToLower(kind), name)
+}
+
+// Publish creates or updates the ConfigMap holding the plan of the owner custom resource.
+// The ConfigMap is owned by the custom resource so that it is garbage collected along with it.
+func Publish(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, namespace string, p Plan) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+
+	gvk, err := c.GroupVersionKindFor(owner)
+	if err != nil {
+		return fmt.Errorf("failed to get kind of %s: %w", owner.GetName(), err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(gvk.Kind, owner.GetName()),
+			Namespace: namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		cm.Data = map[string]string{ConfigMapDataKey: string(data)}
+		return controllerutil.SetOwnerReference(owner, cm, scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish plan to ConfigMap %s/%s: %w", namespace, cm.Name, err)
+	}
+	return nil
+}
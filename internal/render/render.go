@@ -37,6 +37,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
 	yamlConverter "sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/gpu-operator/internal/assets"
 )
 
 const (
@@ -67,10 +69,25 @@ func NewRenderer(files []string) Renderer {
 	}
 }
 
+// NewRendererFromFiles creates a Renderer object, that will render all template files provided
+// with their content, e.g. loaded from the assets embedded in the operator.
+func NewRendererFromFiles(files []assets.File) Renderer {
+	r := &textTemplateRenderer{
+		contents: make(map[string][]byte, len(files)),
+	}
+	for _, f := range files {
+		r.files = append(r.files, f.Name)
+		r.contents[f.Name] = f.Data
+	}
+	return r
+}
+
 // textTemplateRenderer is an implementation of the Renderer interface using golang builtin text/template package
 // as its templating engine
 type textTemplateRenderer struct {
 	files []string
+	// contents holds the content of the files, which are read from disk otherwise
+	contents map[string][]byte
 }
 
 // RenderObjects renders kubernetes objects utilizing the provided TemplatingData.
@@ -90,9 +107,13 @@ func (r *textTemplateRenderer) RenderObjects(data *TemplatingData) ([]*unstructu
 // renderFile renders a single file to a list of k8s unstructured objects
 func (r *textTemplateRenderer) renderFile(filePath string, data *TemplatingData) ([]*unstructured.Unstructured, error) {
 	// Read file
-	txt, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read manifest file %s: %w", filePath, err)
+	txt, ok := r.contents[filePath]
+	if !ok {
+		var err error
+		txt, err = os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest file %s: %w", filePath, err)
+		}
 	}
 
 	// Create a new template
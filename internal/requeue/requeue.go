@@ -0,0 +1,237 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package requeue implements the requeue policy shared by the operator controllers:
+// objects which are not ready are requeued with an exponential backoff per key,
+// and periodic requeues are spread with a random jitter. The failed reconciles of the
+// controllers are backed off as per the same policy, see Backoff.RateLimiter.
+package requeue
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// DefaultInitialDelay is the default delay of the first requeue of a key not ready
+	DefaultInitialDelay = 5 * time.Second
+	// DefaultMaxDelay is the default cap of the requeue delays of a key not ready
+	DefaultMaxDelay = 5 * time.Minute
+	// DefaultFactor is the default multiplier of the delay between consecutive requeues of a key not ready
+	DefaultFactor = 2.0
+	// DefaultJitter is the default fraction of a delay added at random
+	DefaultJitter = 0.1
+	// DefaultNodePollInterval is the default interval at which the cluster is polled for
+	// new nodes when none of them is labelled by NFD
+	DefaultNodePollInterval = 45 * time.Second
+	// DefaultUpgradeInterval is the default interval at which the driver upgrade state is reconciled
+	DefaultUpgradeInterval = 2 * time.Minute
+)
+
+// Policy configures the requeue delays of the controllers
+type Policy struct {
+	// InitialDelay is the delay of the first requeue of a key not ready
+	InitialDelay time.Duration
+	// MaxDelay caps the requeue delays of a key not ready
+	MaxDelay time.Duration
+	// Factor multiplies the delay between consecutive requeues of a key not ready
+	Factor float64
+	// Jitter is the maximum fraction of a delay added at random, 0 disables the jitter
+	Jitter float64
+	// NodePollInterval is the interval at which the cluster is polled for new nodes
+	// when none of them is labelled by NFD
+	NodePollInterval time.Duration
+	// UpgradeInterval is the interval at which the driver upgrade state is reconciled
+	UpgradeInterval time.Duration
+}
+
+// DefaultPolicy returns the default requeue policy
+func DefaultPolicy() Policy {
+	return Policy{
+		InitialDelay:     DefaultInitialDelay,
+		MaxDelay:         DefaultMaxDelay,
+		Factor:           DefaultFactor,
+		Jitter:           DefaultJitter,
+		NodePollInterval: DefaultNodePollInterval,
+		UpgradeInterval:  DefaultUpgradeInterval,
+	}
+}
+
+// Validate checks the policy settings
+func (p Policy) Validate() error {
+	if p.InitialDelay <= 0 {
+		return fmt.Errorf("initial requeue delay must be positive, got %s", p.InitialDelay)
+	}
+	if p.MaxDelay < p.InitialDelay {
+		return fmt.Errorf("max requeue delay %s is lower than the initial delay %s", p.MaxDelay, p.InitialDelay)
+	}
+	if p.Factor < 1 {
+		return fmt.Errorf("requeue backoff factor must be at least 1, got %v", p.Factor)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("requeue jitter must be between 0 and 1, got %v", p.Jitter)
+	}
+	if p.NodePollInterval <= 0 {
+		return fmt.Errorf("node poll interval must be positive, got %s", p.NodePollInterval)
+	}
+	if p.UpgradeInterval <= 0 {
+		return fmt.Errorf("upgrade requeue interval must be positive, got %s", p.UpgradeInterval)
+	}
+	return nil
+}
+
+// Backoff computes the requeue delays of the controllers from a Policy and tracks the
+// consecutive requeues of each key. The methods of a nil Backoff return the delays of the
+// default policy without backoff or jitter.
+type Backoff struct {
+	policy Policy
+	random func() float64
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+// NewBackoff returns a Backoff applying the given policy
+func NewBackoff(policy Policy) *Backoff {
+	return &Backoff{
+		policy:   policy,
+		random:   rand.Float64,
+		attempts: map[string]int{},
+	}
+}
+
+// Policy returns the policy applied by the Backoff
+func (b *Backoff) Policy() Policy {
+	if b == nil {
+		return DefaultPolicy()
+	}
+	return b.policy
+}
+
+// Delay returns the delay before the next requeue of a key not ready and records the requeue.
+// The delay grows exponentially from the initial delay of the policy, or from minDelay when it
+// is larger, until it reaches the max delay of the policy.
+func (b *Backoff) Delay(key string, minDelay time.Duration) time.Duration {
+	if b == nil {
+		return max(DefaultInitialDelay, minDelay)
+	}
+
+	b.mu.Lock()
+	attempt := b.attempts[key]
+	b.attempts[key] = attempt + 1
+	b.mu.Unlock()
+
+	delay := max(b.policy.InitialDelay, minDelay)
+	maxDelay := max(b.policy.MaxDelay, delay)
+	backoff := float64(delay) * math.Pow(b.policy.Factor, float64(attempt))
+	if backoff >= float64(maxDelay) {
+		delay = maxDelay
+	} else {
+		delay = time.Duration(backoff)
+	}
+	return min(b.jitter(delay), maxDelay)
+}
+
+// Reset forgets the requeues of a key, the next delay of the key is the initial one
+func (b *Backoff) Reset(key string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.attempts, key)
+}
+
+// Forget drops the requeues of a key and of the keys scoped to it, i.e. prefixed with "<key>/",
+// once the object they are tracked for is deleted
+func (b *Backoff) Forget(key string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for k := range b.attempts {
+		if k == key || strings.HasPrefix(k, key+"/") {
+			delete(b.attempts, k)
+		}
+	}
+}
+
+// RateLimiter returns the rate limiter of the work queue of the controller name, backing off the
+// failed reconciles of an object as per the policy. The requeues of an object are forgotten by the
+// work queue once it is reconciled successfully.
+func (b *Backoff) RateLimiter(name string) workqueue.RateLimiter {
+	return &rateLimiter{backoff: b, name: name}
+}
+
+// rateLimiter backs off the failed reconciles of the objects of a controller
+type rateLimiter struct {
+	backoff *Backoff
+	name    string
+}
+
+// key returns the key of the failed reconciles of an object, scoped to the controller as
+// the Backoff is shared by the controllers
+func (r *rateLimiter) key(item interface{}) string {
+	return fmt.Sprintf("%s/error/%v", r.name, item)
+}
+
+func (r *rateLimiter) When(item interface{}) time.Duration {
+	return r.backoff.Delay(r.key(item), 0)
+}
+
+func (r *rateLimiter) Forget(item interface{}) {
+	r.backoff.Reset(r.key(item))
+}
+
+func (r *rateLimiter) NumRequeues(item interface{}) int {
+	if r.backoff == nil {
+		return 0
+	}
+	r.backoff.mu.Lock()
+	defer r.backoff.mu.Unlock()
+	return r.backoff.attempts[r.key(item)]
+}
+
+// NodePollInterval returns the delay before the next poll for nodes labelled by NFD
+func (b *Backoff) NodePollInterval() time.Duration {
+	if b == nil {
+		return DefaultNodePollInterval
+	}
+	return b.jitter(b.policy.NodePollInterval)
+}
+
+// UpgradeInterval returns the delay before the next periodic reconcile of the driver upgrade state
+func (b *Backoff) UpgradeInterval() time.Duration {
+	if b == nil {
+		return DefaultUpgradeInterval
+	}
+	return b.jitter(b.policy.UpgradeInterval)
+}
+
+// jitter adds a random fraction of up to the policy jitter to the delay
+func (b *Backoff) jitter(delay time.Duration) time.Duration {
+	if b.policy.Jitter <= 0 {
+		return delay
+	}
+	return delay + time.Duration(b.policy.Jitter*b.random()*float64(delay))
+}
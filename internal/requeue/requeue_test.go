@@ -0,0 +1,135 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package requeue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackoff(policy Policy, random float64) *Backoff {
+	b := NewBackoff(policy)
+	b.random = func() float64 { return random }
+	return b
+}
+
+func TestBackoffDelay(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.MaxDelay = time.Minute
+	policy.Jitter = 0
+	b := newTestBackoff(policy, 0)
+
+	for _, expected := range []time.Duration{
+		5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute,
+	} {
+		require.Equal(t, expected, b.Delay("a", 0))
+	}
+	// keys are backed off independently and from their minimum delay
+	require.Equal(t, 15*time.Second, b.Delay("b", 15*time.Second))
+	require.Equal(t, 30*time.Second, b.Delay("b", 15*time.Second))
+
+	b.Reset("a")
+	require.Equal(t, 5*time.Second, b.Delay("a", 0))
+	// the minimum delay of a key is not capped
+	require.Equal(t, 2*time.Minute, b.Delay("c", 2*time.Minute))
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.MaxDelay = 20 * time.Second
+	policy.Jitter = 0.5
+	b := newTestBackoff(policy, 0.5)
+
+	require.Equal(t, 6250*time.Millisecond, b.Delay("a", 0))
+	require.Equal(t, 12500*time.Millisecond, b.Delay("a", 0))
+	// the jitter does not exceed the max delay
+	require.Equal(t, 20*time.Second, b.Delay("a", 0))
+	require.Equal(t, 56250*time.Millisecond, b.NodePollInterval())
+	require.Equal(t, 150*time.Second, b.UpgradeInterval())
+}
+
+func TestNilBackoff(t *testing.T) {
+	var b *Backoff
+	require.Equal(t, DefaultInitialDelay, b.Delay("a", 0))
+	require.Equal(t, DefaultInitialDelay, b.Delay("a", 0))
+	require.Equal(t, 15*time.Second, b.Delay("a", 15*time.Second))
+	require.Equal(t, DefaultNodePollInterval, b.NodePollInterval())
+	require.Equal(t, DefaultUpgradeInterval, b.UpgradeInterval())
+	require.Equal(t, DefaultPolicy(), b.Policy())
+	b.Reset("a")
+}
+
+func TestBackoffForget(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.Jitter = 0
+	b := newTestBackoff(policy, 0)
+
+	b.Delay("cp", 0)
+	b.Delay("cp/state-driver", 0)
+	b.Delay("cp/delete", 0)
+	b.Delay("cp-other", 0)
+	b.Forget("cp")
+	require.Equal(t, map[string]int{"cp-other": 1}, b.attempts)
+
+	var nilBackoff *Backoff
+	nilBackoff.Forget("cp")
+}
+
+func TestBackoffRateLimiter(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.Jitter = 0
+	b := newTestBackoff(policy, 0)
+
+	limiter := b.RateLimiter("controller")
+	require.Equal(t, 5*time.Second, limiter.When("cp"))
+	require.Equal(t, 10*time.Second, limiter.When("cp"))
+	require.Equal(t, 2, limiter.NumRequeues("cp"))
+	// the failed reconciles are tracked per controller
+	require.Equal(t, 5*time.Second, b.RateLimiter("other").When("cp"))
+
+	limiter.Forget("cp")
+	require.Equal(t, 0, limiter.NumRequeues("cp"))
+	require.Equal(t, map[string]int{"other/error/cp": 1}, b.attempts)
+
+	var nilBackoff *Backoff
+	limiter = nilBackoff.RateLimiter("controller")
+	require.Equal(t, DefaultInitialDelay, limiter.When("cp"))
+	require.Equal(t, 0, limiter.NumRequeues("cp"))
+	limiter.Forget("cp")
+}
+
+func TestPolicyValidate(t *testing.T) {
+	require.NoError(t, DefaultPolicy().Validate())
+
+	for name, update := range map[string]func(*Policy){
+		"zero initial delay":    func(p *Policy) { p.InitialDelay = 0 },
+		"max below initial":     func(p *Policy) { p.MaxDelay = time.Second },
+		"factor below one":      func(p *Policy) { p.Factor = 0.5 },
+		"negative jitter":       func(p *Policy) { p.Jitter = -0.1 },
+		"jitter above one":      func(p *Policy) { p.Jitter = 1.5 },
+		"zero node poll":        func(p *Policy) { p.NodePollInterval = 0 },
+		"zero upgrade interval": func(p *Policy) { p.UpgradeInterval = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			policy := DefaultPolicy()
+			update(&policy)
+			require.Error(t, policy.Validate())
+		})
+	}
+}
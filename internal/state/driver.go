@@ -32,16 +32,21 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
 	"github.com/NVIDIA/gpu-operator/controllers/clusterinfo"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
 	"github.com/NVIDIA/gpu-operator/internal/image"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
 	"github.com/NVIDIA/gpu-operator/internal/render"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
 	"github.com/NVIDIA/gpu-operator/internal/utils"
 )
 
@@ -90,25 +95,48 @@ type driverRenderData struct {
 	Openshift         *openshiftSpec
 	Precompiled       *precompiledSpec
 	AdditionalConfigs *additionalConfigs
+	// Proxy and TrustedCA configure the driver containers on clusters other than OpenShift
+	Proxy     *gpuv1.ProxySpec
+	TrustedCA *trustedCASpec
+}
+
+// trustedCASpec is the CA bundle trusted by the driver containers of a node pool
+type trustedCASpec struct {
+	// Name and Key select the CA bundle in a ConfigMap
+	Name string
+	Key  string
+	// MountPath is the OS specific directory the CA bundle is mounted to, as FileName
+	MountPath string
+	FileName  string
 }
 
 func NewStateDriver(
 	k8sClient client.Client,
 	scheme *runtime.Scheme,
-	manifestDir string) (State, error) {
-
-	files, err := utils.GetFilesWithSuffix(manifestDir, render.ManifestFileSuffix...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get files from manifest directory: %v", err)
+	recorder record.EventRecorder,
+	files []assets.File) (State, error) {
+
+	var manifests []assets.File
+	for _, f := range files {
+		for _, suffix := range render.ManifestFileSuffix {
+			if strings.HasSuffix(f.Name, suffix) {
+				manifests = append(manifests, f)
+				break
+			}
+		}
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no manifest files provided")
 	}
 
-	renderer := render.NewRenderer(files)
+	renderer := render.NewRendererFromFiles(manifests)
 	state := &stateDriver{
 		stateSkel: stateSkel{
 			name:        "state-driver",
 			description: "NVIDIA driver deployed in the cluster",
 			client:      k8sClient,
 			scheme:      scheme,
+			recorder:    recorder,
 			renderer:    renderer,
 		},
 	}
@@ -125,6 +153,10 @@ func (s *stateDriver) Sync(ctx context.Context, customResource interface{}, info
 	if info == nil {
 		return SyncStateError, fmt.Errorf("failed to get ClusterPolicy CR from info catalog")
 	}
+	clusterPolicy, ok := info.(gpuv1.ClusterPolicy)
+	if !ok {
+		return SyncStateError, fmt.Errorf("unexpected ClusterPolicy CR in info catalog")
+	}
 
 	info = infoCatalog.Get(InfoTypeClusterInfo)
 	if info == nil {
@@ -132,18 +164,35 @@ func (s *stateDriver) Sync(ctx context.Context, customResource interface{}, info
 	}
 	clusterInfo := info.(clusterinfo.Interface)
 
+	// the images are only pinned if a resolver is provided
+	var images *registry.DigestResolver
+	if info = infoCatalog.Get(InfoTypeImageResolver); info != nil {
+		images = info.(*registry.DigestResolver)
+	}
+	// the image signatures are only verified if a verifier is provided
+	var verifier *registry.PolicyVerifier
+	if info = infoCatalog.Get(InfoTypeImageVerifier); info != nil {
+		verifier = info.(*registry.PolicyVerifier)
+	}
+
 	err := s.cleanupStaleDriverDaemonsets(ctx, cr)
 	if err != nil {
 		return SyncStateNotReady, fmt.Errorf("failed to cleanup stale driver DaemonSets: %w", err)
 	}
 
-	objs, err := s.getManifestObjects(ctx, cr, clusterInfo)
+	objs, pools, err := s.getManifestObjects(ctx, cr, clusterInfo, &clusterPolicy.Spec.Operator, images)
 	if err != nil {
 		return SyncStateNotReady, fmt.Errorf("failed to create k8s objects from manifests: %v", err)
 	}
 
+	// refuse to roll out DaemonSets with images which cannot be verified
+	err = verifyDaemonSetImages(ctx, objs, verifier)
+	if err != nil {
+		return SyncStateNotReady, err
+	}
+
 	// Create objects if they don't exist, Update objects if they do exist
-	err = s.createOrUpdateObjs(ctx, func(obj *unstructured.Unstructured) error {
+	err = s.createOrUpdateObjs(ctx, cr, func(obj *unstructured.Unstructured) error {
 		if err := controllerutil.SetControllerReference(cr, obj, s.scheme); err != nil {
 			return fmt.Errorf("failed to set controller reference for object: %v", err)
 		}
@@ -158,9 +207,31 @@ func (s *stateDriver) Sync(ctx context.Context, customResource interface{}, info
 	if err != nil {
 		return SyncStateNotReady, fmt.Errorf("failed to get sync state: %v", err)
 	}
+	s.recordNodePoolReadiness(ctx, cr, objs, pools)
 	return syncState, nil
 }
 
+// recordNodePoolReadiness reports the readiness of the driver DaemonSet of each node pool
+func (s *stateDriver) recordNodePoolReadiness(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver,
+	objs []*unstructured.Unstructured, pools map[string]string) {
+	reqLogger := log.FromContext(ctx)
+
+	readiness := make(map[string]bool, len(pools))
+	for _, obj := range objs {
+		pool, ok := pools[obj.GetName()]
+		if !ok || obj.GetKind() != "DaemonSet" {
+			continue
+		}
+		found := obj.DeepCopy()
+		ready := false
+		if err := s.getObj(ctx, found); err == nil {
+			ready, _ = s.isDaemonSetReady(found, reqLogger)
+		}
+		readiness[pool] = ready
+	}
+	setNVIDIADriverReadiness(cr.Name, readiness)
+}
+
 func (s *stateDriver) GetWatchSources(mgr ctrlManager) map[string]SyncingSource {
 	wr := make(map[string]SyncingSource)
 	wr["DaemonSet"] = source.Kind(
@@ -189,6 +260,8 @@ func (s *stateDriver) cleanupStaleDriverDaemonsets(ctx context.Context, cr *nvid
 			if err != nil {
 				return fmt.Errorf("error deleting DaemonSet '%s': %w", ds.Name, err)
 			}
+			s.recordEvent(cr, corev1.EventTypeNormal, consts.DaemonSetDeletedReason,
+				"DaemonSet %s/%s deleted", ds.Namespace, ds.Name)
 			continue
 		}
 		// TODO: cleanup precompiled / non-precompiled DaemonSets if spec.usePrecompiled is toggled.
@@ -197,12 +270,13 @@ func (s *stateDriver) cleanupStaleDriverDaemonsets(ctx context.Context, cr *nvid
 	return nil
 }
 
-func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, clusterInfo clusterinfo.Interface) ([]*unstructured.Unstructured, error) {
+func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1.NVIDIADriver, clusterInfo clusterinfo.Interface,
+	operator *gpuv1.OperatorSpec, images *registry.DigestResolver) ([]*unstructured.Unstructured, map[string]string, error) {
 	logger := log.FromContext(ctx)
 
 	runtimeSpec, err := getRuntimeSpec(ctx, s.client, clusterInfo, &cr.Spec)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct cluster runtime spec: %w", err)
+		return nil, nil, fmt.Errorf("failed to construct cluster runtime spec: %w", err)
 	}
 
 	gpuDirectRDMASpec := cr.Spec.GPUDirectRDMA
@@ -211,20 +285,28 @@ func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1
 		GPUDirectRDMA: gpuDirectRDMASpec,
 		Runtime:       runtimeSpec,
 	}
+	var trustedCA *gpuv1.TrustedCASpec
+	if runtimeSpec.OpenshiftVersion == "" {
+		// the cluster wide proxy is used on OpenShift
+		renderData.Proxy = getProxySpec(&cr.Spec, operator)
+		trustedCA = getTrustedCASpec(&cr.Spec, operator)
+	}
 
 	if len(runtimeSpec.NodePools) == 0 {
-		return nil, fmt.Errorf("no nodes matching the given node selector for %s", cr.Name)
+		return nil, nil, fmt.Errorf("no nodes matching the given node selector for %s", cr.Name)
 	}
 
 	// Render kubernetes objects for each node pool.
 	// We deploy one DaemonSet per node pool.
+	// the driver DaemonSets are indexed by name to the node pool they are deployed to
 	var objs []*unstructured.Unstructured
+	pools := make(map[string]string, len(runtimeSpec.NodePools))
 	for _, nodePool := range runtimeSpec.NodePools {
 		// Construct a unique driver spec per node pool. Each node pool
 		// should have a unique nodeSelector and name.
 		driverSpec, err := getDriverSpec(cr, nodePool)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct driver spec: %w", err)
+			return nil, nil, fmt.Errorf("failed to construct driver spec: %w", err)
 		}
 		renderData.Driver = driverSpec
 
@@ -237,13 +319,13 @@ func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1
 
 		gdsSpec, err := getGDSSpec(&cr.Spec, nodePool)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct GDS spec: %w", err)
+			return nil, nil, fmt.Errorf("failed to construct GDS spec: %w", err)
 		}
 		renderData.GDS = gdsSpec
 
 		gdrcopySpec, err := getGDRCopySpec(&cr.Spec, nodePool)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct GDRCopy spec: %w", err)
+			return nil, nil, fmt.Errorf("failed to construct GDRCopy spec: %w", err)
 		}
 		renderData.GDRCopy = gdrcopySpec
 
@@ -254,7 +336,15 @@ func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1
 			}
 		}
 
-		renderData.AdditionalConfigs, err = s.getDriverAdditionalConfigs(ctx, cr, clusterInfo, nodePool)
+		mirrorImages(renderData, operator.ImageRegistryMirrors)
+
+		renderData.TrustedCA, err = getTrustedCABundle(trustedCA, nodePool.osRelease)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to construct the trusted CA bundle of node pool %s: %w", nodePool.name, err)
+		}
+
+		poolCtx := tracing.WithAttributes(ctx, tracing.NodePool(nodePool.name))
+		renderData.AdditionalConfigs, err = s.getDriverAdditionalConfigs(poolCtx, cr, clusterInfo, nodePool)
 		if err != nil {
 			logger.Error(err, "error rendering addition driver volume", "NodePool", nodePool.name)
 		}
@@ -263,17 +353,23 @@ func (s *stateDriver) getManifestObjects(ctx context.Context, cr *nvidiav1alpha1
 		manifestObjs, err := s.renderManifestObjects(ctx, renderData)
 		if err != nil {
 			logger.Error(err, "error rendering manifests for node pool", "NodePool", nodePool.name)
-			return nil, err
+			return nil, nil, err
 		}
-		manifestObjs, err = s.handleDefaultImagesInObjects(ctx, manifestObjs, cr, *renderData)
+		err = pinDaemonSetImages(ctx, manifestObjs, images)
+		if err != nil {
+			logger.Error(err, "error pinning images in manifests", "NodePool", nodePool.name)
+			return nil, nil, err
+		}
+		manifestObjs, err = s.handleDefaultImagesInObjects(ctx, manifestObjs, cr, *renderData, images)
 		if err != nil {
 			logger.Error(err, "error handling default images in manifests", "NodePool", nodePool.name)
-			return nil, err
+			return nil, nil, err
 		}
 		objs = append(objs, manifestObjs...)
+		pools[driverSpec.AppName] = nodePool.name
 
 	}
-	return objs, nil
+	return objs, pools, nil
 }
 
 func (s *stateDriver) renderManifestObjects(ctx context.Context, renderData *driverRenderData) ([]*unstructured.Unstructured, error) {
@@ -298,7 +394,8 @@ func (s *stateDriver) handleDefaultImagesInObjects(
 	ctx context.Context,
 	desiredObjs []*unstructured.Unstructured,
 	cr *nvidiav1alpha1.NVIDIADriver,
-	renderData driverRenderData) ([]*unstructured.Unstructured, error) {
+	renderData driverRenderData,
+	images *registry.DigestResolver) ([]*unstructured.Unstructured, error) {
 	logger := log.FromContext(ctx)
 
 	// If 'image' field is not set in spec, then the driver image path
@@ -348,6 +445,11 @@ func (s *stateDriver) handleDefaultImagesInObjects(
 	if err != nil {
 		return nil, fmt.Errorf("failed to render kubernetes manifests: %w", err)
 	}
+	// the hash of the current DaemonSet was computed with its images pinned
+	err = pinDaemonSetImages(ctx, desiredObjsWithCurrentImages, images)
+	if err != nil {
+		return nil, err
+	}
 
 	obj, err := getObjectOfKind(desiredObjsWithCurrentImages, "DaemonSet")
 	if err != nil {
@@ -390,6 +492,144 @@ func getDaemonsetFromObjects(objs []*unstructured.Unstructured) (*appsv1.DaemonS
 	return ds, nil
 }
 
+// getProxySpec returns the proxy settings of the driver containers, those of the NVIDIADriver
+// spec overriding those of the ClusterPolicy, or nil if no proxy is configured
+func getProxySpec(spec *nvidiav1alpha1.NVIDIADriverSpec, operator *gpuv1.OperatorSpec) *gpuv1.ProxySpec {
+	proxy := operator.Proxy
+	if spec.Proxy != nil {
+		proxy = &gpuv1.ProxySpec{
+			HTTPProxy:  spec.Proxy.HTTPProxy,
+			HTTPSProxy: spec.Proxy.HTTPSProxy,
+			NoProxy:    spec.Proxy.NoProxy,
+		}
+	}
+	if proxy == nil || (proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" && proxy.NoProxy == "") {
+		return nil
+	}
+	return proxy
+}
+
+// getTrustedCASpec returns the ConfigMap holding the CA bundle trusted by the driver containers,
+// that of the NVIDIADriver spec overriding that of the ClusterPolicy, or nil if none is configured
+func getTrustedCASpec(spec *nvidiav1alpha1.NVIDIADriverSpec, operator *gpuv1.OperatorSpec) *gpuv1.TrustedCASpec {
+	trustedCA := operator.TrustedCA
+	if spec.TrustedCA != nil {
+		trustedCA = &gpuv1.TrustedCASpec{
+			Name: spec.TrustedCA.Name,
+			Key:  spec.TrustedCA.Key,
+		}
+	}
+	if trustedCA == nil || trustedCA.Name == "" {
+		return nil
+	}
+	return &gpuv1.TrustedCASpec{Name: trustedCA.Name, Key: trustedCA.GetKey()}
+}
+
+// getTrustedCABundle returns the trusted CA bundle of the driver containers of a node pool,
+// mounted to the path of the trust store of its OS, or nil if no CA bundle is configured
+func getTrustedCABundle(trustedCA *gpuv1.TrustedCASpec, osRelease string) (*trustedCASpec, error) {
+	if trustedCA == nil {
+		return nil, nil
+	}
+	dir, file, err := getTrustedCABundlePath(osRelease)
+	if err != nil {
+		return nil, err
+	}
+	return &trustedCASpec{
+		Name:      trustedCA.Name,
+		Key:       trustedCA.Key,
+		MountPath: dir,
+		FileName:  file,
+	}, nil
+}
+
+// mirrorImages rewrites the image paths of the render data per the image registry mirrors
+func mirrorImages(renderData *driverRenderData, mirrors map[string]string) {
+	if len(mirrors) == 0 {
+		return
+	}
+	if renderData.Driver != nil {
+		renderData.Driver.ImagePath = image.MirrorImage(renderData.Driver.ImagePath, mirrors)
+		renderData.Driver.ManagerImagePath = image.MirrorImage(renderData.Driver.ManagerImagePath, mirrors)
+	}
+	if renderData.GDS != nil {
+		renderData.GDS.ImagePath = image.MirrorImage(renderData.GDS.ImagePath, mirrors)
+	}
+	if renderData.GDRCopy != nil {
+		renderData.GDRCopy.ImagePath = image.MirrorImage(renderData.GDRCopy.ImagePath, mirrors)
+	}
+	if renderData.Openshift != nil {
+		renderData.Openshift.ToolkitImage = image.MirrorImage(renderData.Openshift.ToolkitImage, mirrors)
+	}
+}
+
+// pinDaemonSetImages pins the container images of the DaemonSets in objs to the digests
+// of their tags, unless images is nil
+func pinDaemonSetImages(ctx context.Context, objs []*unstructured.Unstructured, images *registry.DigestResolver) error {
+	if images == nil {
+		return nil
+	}
+	return updateDaemonSetImages(objs, func(obj *unstructured.Unstructured, name string) (string, error) {
+		pinned, err := images.Resolve(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to pin the images of DaemonSet %s: %w", obj.GetName(), err)
+		}
+		return pinned, nil
+	})
+}
+
+// verifyDaemonSetImages verifies the signatures of the container images of the DaemonSets in objs,
+// unless verifier is nil. Verification failures are returned as *registry.VerificationError.
+func verifyDaemonSetImages(ctx context.Context, objs []*unstructured.Unstructured, verifier *registry.PolicyVerifier) error {
+	if verifier == nil {
+		return nil
+	}
+	return updateDaemonSetImages(objs, func(obj *unstructured.Unstructured, name string) (string, error) {
+		if err := verifier.VerifyImage(ctx, name); err != nil {
+			return "", fmt.Errorf("refusing to roll out DaemonSet %s: %w", obj.GetName(), err)
+		}
+		return name, nil
+	})
+}
+
+// updateDaemonSetImages sets the container images of the DaemonSets in objs to the images returned by update
+func updateDaemonSetImages(objs []*unstructured.Unstructured, update func(obj *unstructured.Unstructured, image string) (string, error)) error {
+	for _, obj := range objs {
+		if obj.GetKind() != "DaemonSet" {
+			continue
+		}
+		for _, field := range []string{"initContainers", "containers"} {
+			path := []string{"spec", "template", "spec", field}
+			containers, found, err := unstructured.NestedSlice(obj.Object, path...)
+			if err != nil {
+				return fmt.Errorf("invalid %s of DaemonSet %s: %w", field, obj.GetName(), err)
+			}
+			if !found {
+				continue
+			}
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, ok := container["image"].(string)
+				if !ok {
+					continue
+				}
+				updated, err := update(obj, name)
+				if err != nil {
+					return err
+				}
+				container["image"] = updated
+			}
+			if err := unstructured.SetNestedSlice(obj.Object, containers, path...); err != nil {
+				return fmt.Errorf("failed to set %s of DaemonSet %s: %w", field, obj.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
 func getObjectOfKind(objs []*unstructured.Unstructured, kind string) (*unstructured.Unstructured, error) {
 	for _, obj := range objs {
 		if obj.GetKind() == kind {
@@ -444,6 +684,9 @@ func getDriverAppName(cr *nvidiav1alpha1.NVIDIADriver, pool nodePool) string {
 	} else if pool.rhcosVersion != "" {
 		hashBuilder.WriteString("-" + pool.rhcosVersion)
 	}
+	if _, ok := pool.nodeSelector[runtimeLabelKey]; ok {
+		hashBuilder.WriteString("-" + pool.runtime)
+	}
 
 	hash := utils.GetStringHash(hashBuilder.String())
 	appName := fmt.Sprintf("%s-%s", appNamePrefix, hash)
@@ -18,6 +18,12 @@ package state
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -37,16 +43,25 @@ import (
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
+	"github.com/NVIDIA/gpu-operator/internal/image/registry"
 	"github.com/NVIDIA/gpu-operator/internal/render"
 	"github.com/NVIDIA/gpu-operator/internal/utils"
+	"github.com/NVIDIA/gpu-operator/manifests"
 )
 
 const (
-	manifestDir       = "../../manifests"
 	manifestResultDir = "./testdata/golden"
 )
 
+func getDriverManifests(t *testing.T) []assets.File {
+	files, err := assets.NewLoader(manifests.FS, "").Load(context.Background(), "state-driver")
+	require.NoError(t, err)
+	return files
+}
+
 func getYAMLString(objs []*unstructured.Unstructured) (string, error) {
 	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme.Scheme,
 		scheme.Scheme, json.SerializerOptions{Yaml: true, Pretty: false, Strict: false})
@@ -69,7 +84,7 @@ func TestDriverRenderMinimal(t *testing.T) {
 		testName = "driver-minimal"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -92,13 +107,100 @@ func TestDriverRenderMinimal(t *testing.T) {
 	require.Equal(t, string(o), actual)
 }
 
+func TestDriverPinImages(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
+	require.Nil(t, err)
+	stateDriver, ok := state.(*stateDriver)
+	require.True(t, ok)
+
+	objs, err := stateDriver.renderer.RenderObjects(
+		&render.TemplatingData{
+			Data: getMinimalDriverRenderData(),
+		})
+	require.Nil(t, err)
+
+	// images are not pinned without a resolver
+	require.Nil(t, pinDaemonSetImages(context.TODO(), objs, nil))
+	ds, err := getDaemonsetFromObjects(objs)
+	require.Nil(t, err)
+	require.Empty(t, registry.PinnedImages(&ds.Spec.Template.Spec))
+
+	resolver := registry.NewDigestResolver(func(_ context.Context, _ string) (string, error) {
+		return digest, nil
+	})
+	require.Nil(t, pinDaemonSetImages(context.TODO(), objs, resolver))
+	pinned, err := getDaemonsetFromObjects(objs)
+	require.Nil(t, err)
+	for i, c := range pinned.Spec.Template.Spec.InitContainers {
+		require.Equal(t, ds.Spec.Template.Spec.InitContainers[i].Image+"@"+digest, c.Image)
+	}
+	for i, c := range pinned.Spec.Template.Spec.Containers {
+		require.Equal(t, ds.Spec.Template.Spec.Containers[i].Image+"@"+digest, c.Image)
+	}
+}
+
+func TestDriverVerifyImages(t *testing.T) {
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
+	require.Nil(t, err)
+	stateDriver, ok := state.(*stateDriver)
+	require.True(t, ok)
+
+	objs, err := stateDriver.renderer.RenderObjects(
+		&render.TemplatingData{
+			Data: getMinimalDriverRenderData(),
+		})
+	require.Nil(t, err)
+
+	// images are not verified without a verifier
+	require.Nil(t, verifyDaemonSetImages(context.TODO(), objs, nil))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.Nil(t, err)
+	policy, err := registry.ParsePolicy("test", map[string][]byte{
+		registry.PolicyKeysKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+	})
+	require.Nil(t, err)
+	verifier := &registry.PolicyVerifier{
+		Policy: policy,
+		Digests: registry.NewDigestResolver(func(_ context.Context, _ string) (string, error) {
+			return "", fmt.Errorf("manifest unknown")
+		}),
+		Signatures: registry.NewSignatureVerifier(registry.NewRegistryClient()),
+	}
+
+	ds, err := getDaemonsetFromObjects(objs)
+	require.Nil(t, err)
+	err = verifyDaemonSetImages(context.TODO(), objs, verifier)
+	require.ErrorContains(t, err, "refusing to roll out DaemonSet "+ds.Name)
+	require.True(t, registry.IsVerificationError(err))
+}
+
+func TestDriverMirrorImages(t *testing.T) {
+	renderData := getMinimalDriverRenderData()
+	renderData.GDS = &gdsDriverSpec{ImagePath: "nvcr.io/nvidia/cloud-native/nvidia-fs:2.16.1-ubuntu22.04"}
+	renderData.Openshift = &openshiftSpec{ToolkitImage: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}
+
+	mirrorImages(renderData, map[string]string{
+		"nvcr.io/nvidia":                "registry.example.com/nvidia",
+		"quay.io/openshift-release-dev": "registry.example.com/ocp",
+	})
+	require.Equal(t, "registry.example.com/nvidia/driver:525.85.03-ubuntu22.04", renderData.Driver.ImagePath)
+	require.Equal(t, "registry.example.com/nvidia/cloud-native/k8s-driver-manager:devel", renderData.Driver.ManagerImagePath)
+	require.Equal(t, "registry.example.com/nvidia/cloud-native/nvidia-fs:2.16.1-ubuntu22.04", renderData.GDS.ImagePath)
+	require.Equal(t, "registry.example.com/ocp/ocp-v4.0-art-dev@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", renderData.Openshift.ToolkitImage)
+}
+
 func TestDriverRenderRDMA(t *testing.T) {
 	// Construct a sample driver state manager
 	const (
 		testName = "driver-rdma"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -173,7 +275,7 @@ func TestDriverRDMAHostMOFED(t *testing.T) {
 	const (
 		testName = "driver-rdma-hostmofed"
 	)
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -205,7 +307,7 @@ func TestDriverSpec(t *testing.T) {
 	const (
 		testName = "driver-full-spec"
 	)
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -293,7 +395,7 @@ func TestDriverGDS(t *testing.T) {
 		testName = "driver-gds"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -329,7 +431,7 @@ func TestDriverGDRCopy(t *testing.T) {
 		testName = "driver-gdrcopy"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -367,7 +469,7 @@ func TestDriverGDRCopyOpenShift(t *testing.T) {
 		toolkitImage = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:7fecaebc1d51b28bc3548171907e4d91823a031d7a6a694ab686999be2b4d867"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -416,12 +518,92 @@ func TestDriverGDRCopyOpenShift(t *testing.T) {
 	require.Equal(t, string(o), actual)
 }
 
+func TestDriverProxy(t *testing.T) {
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
+	require.Nil(t, err)
+	stateDriver, ok := state.(*stateDriver)
+	require.True(t, ok)
+
+	operator := &gpuv1.OperatorSpec{
+		Proxy:     &gpuv1.ProxySpec{HTTPProxy: "http://proxy.example.com:3128", NoProxy: ".cluster.local"},
+		TrustedCA: &gpuv1.TrustedCASpec{Name: "cluster-ca"},
+	}
+	spec := &nvidiav1alpha1.NVIDIADriverSpec{
+		Proxy: &nvidiav1alpha1.ProxySpec{HTTPSProxy: "http://driver-proxy.example.com:3128"},
+	}
+
+	// the settings of the NVIDIADriver override those of the ClusterPolicy
+	require.Equal(t, &gpuv1.ProxySpec{HTTPProxy: "http://proxy.example.com:3128", NoProxy: ".cluster.local"},
+		getProxySpec(&nvidiav1alpha1.NVIDIADriverSpec{}, operator))
+	require.Equal(t, &gpuv1.ProxySpec{HTTPSProxy: "http://driver-proxy.example.com:3128"}, getProxySpec(spec, operator))
+	require.Nil(t, getProxySpec(&nvidiav1alpha1.NVIDIADriverSpec{}, &gpuv1.OperatorSpec{Proxy: &gpuv1.ProxySpec{}}))
+	require.Equal(t, &gpuv1.TrustedCASpec{Name: "cluster-ca", Key: "ca-bundle.crt"}, getTrustedCASpec(spec, operator))
+	spec.TrustedCA = &nvidiav1alpha1.TrustedCASpec{Name: "driver-ca", Key: "ca.pem"}
+	require.Equal(t, &gpuv1.TrustedCASpec{Name: "driver-ca", Key: "ca.pem"}, getTrustedCASpec(spec, operator))
+	require.Nil(t, getTrustedCASpec(&nvidiav1alpha1.NVIDIADriverSpec{}, &gpuv1.OperatorSpec{}))
+
+	renderData := getMinimalDriverRenderData()
+	renderData.GDS = &gdsDriverSpec{
+		ImagePath: "nvcr.io/nvidia/cloud-native/nvidia-fs:2.16.1",
+		Spec:      &nvidiav1alpha1.GPUDirectStorageSpec{Enabled: utils.BoolPtr(true)},
+	}
+	renderData.GDRCopy = &gdrcopyDriverSpec{
+		ImagePath: "nvcr.io/nvidia/cloud-native/gdrdrv:v2.4.1",
+		Spec:      &nvidiav1alpha1.GDRCopySpec{Enabled: utils.BoolPtr(true)},
+	}
+	renderData.Proxy = getProxySpec(&nvidiav1alpha1.NVIDIADriverSpec{}, operator)
+	// the CA bundle is mounted to the trust store of the OS of the node pool
+	trustedCA, err := getTrustedCABundle(getTrustedCASpec(&nvidiav1alpha1.NVIDIADriverSpec{}, operator), "rhel")
+	require.NoError(t, err)
+	require.Equal(t, &trustedCASpec{Name: "cluster-ca", Key: "ca-bundle.crt", MountPath: "/etc/pki/ca-trust/extracted/pem", FileName: "tls-ca-bundle.pem"}, trustedCA)
+	_, err = getTrustedCABundle(getTrustedCASpec(&nvidiav1alpha1.NVIDIADriverSpec{}, operator), "unknown")
+	require.Error(t, err)
+	renderData.TrustedCA, err = getTrustedCABundle(getTrustedCASpec(&nvidiav1alpha1.NVIDIADriverSpec{}, operator), "ubuntu")
+	require.NoError(t, err)
+
+	objs, err := stateDriver.renderer.RenderObjects(
+		&render.TemplatingData{
+			Data: renderData,
+		})
+	require.Nil(t, err)
+	ds, err := getDaemonSetObj(objs)
+	require.Nil(t, err)
+
+	proxyEnv := []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: "http://proxy.example.com:3128"},
+		{Name: "http_proxy", Value: "http://proxy.example.com:3128"},
+		{Name: "NO_PROXY", Value: ".cluster.local"},
+		{Name: "no_proxy", Value: ".cluster.local"},
+	}
+	for _, name := range []string{"nvidia-driver-ctr", "nvidia-fs-ctr", "nvidia-gdrcopy-ctr"} {
+		ctr, err := getContainerObj(ds.Spec.Template.Spec.Containers, name)
+		require.Nil(t, err, "%s should be in the list of containers", name)
+		for _, env := range proxyEnv {
+			require.Contains(t, ctr.Env, env, name)
+		}
+		require.Contains(t, ctr.VolumeMounts, corev1.VolumeMount{
+			Name:      "gpu-operator-trusted-ca",
+			MountPath: "/usr/local/share/ca-certificates",
+			ReadOnly:  true,
+		}, name)
+	}
+	require.Contains(t, ds.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "gpu-operator-trusted-ca",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "cluster-ca"},
+				Items:                []corev1.KeyToPath{{Key: "ca-bundle.crt", Path: "gpu-operator-trusted-ca.crt"}},
+			},
+		},
+	})
+}
+
 func TestDriverAdditionalConfigs(t *testing.T) {
 	const (
 		testName = "driver-additional-configs"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -506,7 +688,7 @@ func TestDriverOpenshiftDriverToolkit(t *testing.T) {
 		toolkitImage = "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:7fecaebc1d51b28bc3548171907e4d91823a031d7a6a694ab686999be2b4d867"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -551,7 +733,7 @@ func TestDriverPrecompiled(t *testing.T) {
 		testName = "driver-precompiled"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -638,11 +820,38 @@ func TestGetDriverAppNameRHCOS(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestGetDriverAppNameRuntime(t *testing.T) {
+	cr := &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: apitypes.UID("bfac7359-6033-45ce-88d6-53db0078526e"),
+		},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			DriverType: nvidiav1alpha1.GPU,
+		},
+	}
+
+	pool := nodePool{
+		osRelease: "ubuntu",
+		osVersion: "20.04",
+		runtime:   "crio",
+	}
+
+	// the name is unchanged unless the node pool is partitioned per container runtime
+	assert.Equal(t, "nvidia-gpu-driver-ubuntu20.04-67cc6dbb79", getDriverAppName(cr, pool))
+
+	pool.nodeSelector = map[string]string{runtimeLabelKey: "crio"}
+	crio := getDriverAppName(cr, pool)
+	pool.runtime = "containerd"
+	pool.nodeSelector[runtimeLabelKey] = "containerd"
+	assert.NotEqual(t, "nvidia-gpu-driver-ubuntu20.04-67cc6dbb79", crio)
+	assert.NotEqual(t, crio, getDriverAppName(cr, pool))
+}
+
 func TestVGPUHostManagerDaemonset(t *testing.T) {
 	const (
 		testName = "driver-vgpu-host-manager"
 	)
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
@@ -880,7 +1089,7 @@ func TestDriverVGPULicensing(t *testing.T) {
 		testName = "driver-vgpu-licensing"
 	)
 
-	state, err := NewStateDriver(nil, nil, manifestDir)
+	state, err := NewStateDriver(nil, nil, nil, getDriverManifests(t))
 	require.Nil(t, err)
 	stateDriver, ok := state.(*stateDriver)
 	require.True(t, ok)
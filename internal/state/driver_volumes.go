@@ -49,6 +49,16 @@ var CertConfigPathMap = map[string]string{
 	"rhel":   "/etc/pki/ca-trust/extracted/pem",
 }
 
+// TrustedCABundleFileMap indicates the OS specific file names of the trusted CA bundle in the
+// directory of CertConfigPathMap. update-ca-certificates only picks up .crt files on Ubuntu, while
+// the bundle extracted from the trust store is replaced on RHEL based systems.
+var TrustedCABundleFileMap = map[string]string{
+	"centos": "tls-ca-bundle.pem",
+	"ubuntu": "gpu-operator-trusted-ca.crt",
+	"rhcos":  "tls-ca-bundle.pem",
+	"rhel":   "tls-ca-bundle.pem",
+}
+
 // MountPathToVolumeSource maps a container mount path to a VolumeSource
 type MountPathToVolumeSource map[string]corev1.VolumeSource
 
@@ -160,9 +170,14 @@ func (s *stateDriver) getDriverAdditionalConfigs(ctx context.Context, cr *v1alph
 			additionalCfgs.Volumes = append(additionalCfgs.Volumes, createConfigMapVolume(cr.Spec.CertConfig.Name, itemsToInclude))
 		}
 
-		runtime, err := info.GetContainerRuntime()
-		if err != nil {
-			return nil, fmt.Errorf("unexpected error when trying to retrieve container runtime info from cluster: %w", err)
+		// the container runtime of the node pool, if known, takes precedence over the one of the cluster
+		runtime := pool.runtime
+		if runtime == "" {
+			var err error
+			runtime, err = info.GetContainerRuntime()
+			if err != nil {
+				return nil, fmt.Errorf("unexpected error when trying to retrieve container runtime info from cluster: %w", err)
+			}
 		}
 
 		openshiftVersion, err := info.GetOpenshiftVersion()
@@ -290,6 +305,15 @@ func getCertConfigPath(os string) (string, error) {
 	return "", fmt.Errorf("distribution %s not supported", os)
 }
 
+// getTrustedCABundlePath returns the OS specific directory and file name of the trusted CA bundle
+func getTrustedCABundlePath(os string) (string, string, error) {
+	dir, err := getCertConfigPath(os)
+	if err != nil {
+		return "", "", err
+	}
+	return dir, TrustedCABundleFileMap[os], nil
+}
+
 // getSubscriptionPathsToVolumeSources returns the MountPathToVolumeSource map containing all
 // OS-specific subscription/entitlement paths that need to be mounted in the container.
 func getSubscriptionPathsToVolumeSources(os string) (map[string]corev1.VolumeSource, error) {
@@ -21,6 +21,12 @@ type InfoType uint
 const (
 	InfoTypeClusterInfo = iota
 	InfoTypeClusterPolicyCR
+	// InfoTypeImageResolver is the registry.DigestResolver pinning the operand images,
+	// only present if the images are to be pinned to the digests of their tags
+	InfoTypeImageResolver
+	// InfoTypeImageVerifier is the registry.PolicyVerifier verifying the signatures of the operand images,
+	// only present if the image signatures are to be verified
+	InfoTypeImageVerifier
 )
 
 func NewInfoCatalog() InfoCatalog {
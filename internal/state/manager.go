@@ -19,36 +19,64 @@ package state
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/assets"
+	"github.com/NVIDIA/gpu-operator/internal/conditions"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
+	"github.com/NVIDIA/gpu-operator/internal/tracing"
 )
 
 type Manager interface {
 	GetWatchSources(ctrlManager) []SyncingSource
 	SyncState(ctx context.Context, customResource interface{}, infoCatalog InfoCatalog) Results
+	// Forget drops the state statuses tracked for a deleted custom resource
+	Forget(key types.NamespacedName)
 }
 
 type stateManager struct {
-	states []State
-	client client.Client
+	states   []State
+	client   client.Client
+	recorder record.EventRecorder
+
+	// mu guards lastStatus
+	mu sync.Mutex
+	// lastStatus holds the last status of each state per custom resource, to record their transitions
+	lastStatus map[types.NamespacedName]*stateStatuses
+}
+
+// stateStatuses are the last statuses of the states of a custom resource
+type stateStatuses struct {
+	uid      types.UID
+	statuses map[string]SyncState
 }
 
 var _ Manager = (*stateManager)(nil)
 
-func NewManager(crdKind string, k8sClient client.Client, scheme *runtime.Scheme) (Manager, error) {
-	states, err := newStates(crdKind, k8sClient, scheme)
+// NewManager creates a state manager for the given CRD kind, with the manifests of its states
+// loaded by manifests. Events are recorded on the custom resources with recorder, which can be
+// nil to record no Events.
+func NewManager(ctx context.Context, crdKind string, k8sClient client.Client, scheme *runtime.Scheme,
+	recorder record.EventRecorder, manifests *assets.Loader) (Manager, error) {
+	states, err := newStates(ctx, crdKind, k8sClient, scheme, recorder, manifests)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add states: %v", err)
 	}
 
 	manager := &stateManager{
-		states: states,
-		client: k8sClient,
+		states:     states,
+		client:     k8sClient,
+		recorder:   recorder,
+		lastStatus: make(map[types.NamespacedName]*stateStatuses),
 	}
 	return manager, nil
 }
@@ -84,9 +112,13 @@ func (m *stateManager) SyncState(ctx context.Context, customResource interface{}
 	for _, state := range m.states {
 		logger.V(consts.LogLevelInfo).Info("Sync State", "Name", state.Name(), "Description", state.Description())
 		stateCtx := log.IntoContext(ctx, logger.WithName("state").WithName(state.Name()))
+		stateCtx, span := tracing.Start(stateCtx, "Sync "+state.Name(), tracing.State(state.Name()))
 		ss, err := state.Sync(stateCtx, customResource, infoCatalog)
+		span.SetAttributes(tracing.StatusKey.String(string(ss)))
+		tracing.End(span, err)
 		result := Result{StateName: state.Name(), Status: ss, ErrInfo: err}
 		managerResult.StatesStatus = append(managerResult.StatesStatus, result)
+		m.recordStateTransition(customResource, result)
 
 		if result.Status == SyncStateNotReady || result.Status == SyncStateError {
 			statesReady = false
@@ -108,18 +140,87 @@ func (m *stateManager) SyncState(ctx context.Context, customResource interface{}
 	return managerResult
 }
 
-func newStates(crdKind string, k8sClient client.Client, scheme *runtime.Scheme) ([]State, error) {
+func (m *stateManager) Forget(key types.NamespacedName) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.lastStatus, key)
+}
+
+// recordStateTransition records an Event on the custom resource when the status of a state
+// changed since its last sync. The states of a custom resource first synced since the operator
+// started are assumed to be in the status reported by its conditions, so that a restart of the
+// operator does not record them again.
+func (m *stateManager) recordStateTransition(customResource interface{}, result Result) {
+	obj, ok := customResource.(client.Object)
+	if !ok || m.recorder == nil {
+		return
+	}
+
+	m.mu.Lock()
+	key := client.ObjectKeyFromObject(obj)
+	last, ok := m.lastStatus[key]
+	if !ok || last.uid != obj.GetUID() {
+		last = &stateStatuses{uid: obj.GetUID(), statuses: make(map[string]SyncState)}
+		m.lastStatus[key] = last
+	}
+	previous, found := last.statuses[result.StateName]
+	last.statuses[result.StateName] = result.Status
+	m.mu.Unlock()
+
+	if !found && isReady(customResource) && (result.Status == SyncStateReady || result.Status == SyncStateIgnore) {
+		// the custom resource already reported all its states ready or disabled
+		return
+	}
+	if found && previous == result.Status {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	if result.Status == SyncStateError {
+		eventType = corev1.EventTypeWarning
+	}
+	if found {
+		m.recorder.Eventf(obj, eventType, consts.StateTransitionReason, "State %s transitioned from %s to %s",
+			result.StateName, eventStatus(previous), eventStatus(result.Status))
+		return
+	}
+	m.recorder.Eventf(obj, eventType, consts.StateTransitionReason, "State %s is %s",
+		result.StateName, eventStatus(result.Status))
+}
+
+// isReady returns true if the conditions of the custom resource report all its states ready
+func isReady(customResource interface{}) bool {
+	if cr, ok := customResource.(*nvidiav1alpha1.NVIDIADriver); ok {
+		return meta.IsStatusConditionTrue(cr.Status.Conditions, conditions.Ready)
+	}
+	return false
+}
+
+// eventStatus returns the status of a state as reported in Events
+func eventStatus(s SyncState) string {
+	if s == SyncStateIgnore {
+		return "disabled"
+	}
+	return string(s)
+}
+
+func newStates(ctx context.Context, crdKind string, k8sClient client.Client, scheme *runtime.Scheme,
+	recorder record.EventRecorder, manifests *assets.Loader) ([]State, error) {
 	switch crdKind {
 	case nvidiav1alpha1.NVIDIADriverCRDName:
-		return newNVIDIADriverStates(k8sClient, scheme)
+		return newNVIDIADriverStates(ctx, k8sClient, scheme, recorder, manifests)
 	default:
 		break
 	}
 	return nil, fmt.Errorf("unsupported CRD for state manager factory: %s", crdKind)
 }
 
-func newNVIDIADriverStates(k8sClient client.Client, scheme *runtime.Scheme) ([]State, error) {
-	driverState, err := NewStateDriver(k8sClient, scheme, "/opt/gpu-operator/manifests/state-driver")
+func newNVIDIADriverStates(ctx context.Context, k8sClient client.Client, scheme *runtime.Scheme,
+	recorder record.EventRecorder, manifests *assets.Loader) ([]State, error) {
+	files, err := manifests.Load(ctx, "state-driver")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load NVIDIA driver manifests: %w", err)
+	}
+	driverState, err := NewStateDriver(k8sClient, scheme, recorder, files)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create NVIDIA driver state: %v", err)
 	}
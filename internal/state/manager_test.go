@@ -0,0 +1,114 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/conditions"
+)
+
+type fakeState struct {
+	name   string
+	status SyncState
+	err    error
+}
+
+func (s *fakeState) Name() string        { return s.name }
+func (s *fakeState) Description() string { return s.name }
+func (s *fakeState) Sync(context.Context, interface{}, InfoCatalog) (SyncState, error) {
+	return s.status, s.err
+}
+func (s *fakeState) GetWatchSources(ctrlManager) map[string]SyncingSource { return nil }
+
+func TestSyncStateRecordsTransitions(t *testing.T) {
+	driver := &fakeState{name: "state-driver", status: SyncStateNotReady}
+	recorder := record.NewFakeRecorder(10)
+	m := &stateManager{
+		states:     []State{driver},
+		recorder:   recorder,
+		lastStatus: make(map[types.NamespacedName]*stateStatuses),
+	}
+	cr := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "driver-uid"}}
+	catalog := NewInfoCatalog()
+
+	m.SyncState(context.Background(), cr, catalog)
+	require.Equal(t, "Normal StateTransition State state-driver is notReady", <-recorder.Events)
+
+	m.SyncState(context.Background(), cr, catalog)
+	require.Empty(t, recorder.Events)
+
+	driver.status = SyncStateReady
+	m.SyncState(context.Background(), cr, catalog)
+	require.Equal(t, "Normal StateTransition State state-driver transitioned from notReady to ready", <-recorder.Events)
+
+	driver.status, driver.err = SyncStateError, fmt.Errorf("failed")
+	m.SyncState(context.Background(), cr, catalog)
+	require.Equal(t, "Warning StateTransition State state-driver transitioned from ready to error", <-recorder.Events)
+
+	driver.status, driver.err = SyncStateIgnore, nil
+	m.SyncState(context.Background(), cr, catalog)
+	require.Equal(t, "Normal StateTransition State state-driver transitioned from error to disabled", <-recorder.Events)
+
+	// the transitions are tracked per custom resource
+	other := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "other-uid"}}
+	m.SyncState(context.Background(), other, catalog)
+	require.Equal(t, "Normal StateTransition State state-driver is disabled", <-recorder.Events)
+}
+
+func TestSyncStateTransitionsAfterRestart(t *testing.T) {
+	driver := &fakeState{name: "state-driver", status: SyncStateReady}
+	recorder := record.NewFakeRecorder(10)
+	m := &stateManager{
+		states:     []State{driver},
+		recorder:   recorder,
+		lastStatus: make(map[types.NamespacedName]*stateStatuses),
+	}
+	cr := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "driver-uid"}}
+	cr.Status.Conditions = []metav1.Condition{{Type: conditions.Ready, Status: metav1.ConditionTrue}}
+	catalog := NewInfoCatalog()
+
+	// the custom resource reported its states ready before the restart
+	m.SyncState(context.Background(), cr, catalog)
+	require.Empty(t, recorder.Events)
+
+	driver.status = SyncStateNotReady
+	m.SyncState(context.Background(), cr, catalog)
+	require.Equal(t, "Normal StateTransition State state-driver transitioned from ready to notReady", <-recorder.Events)
+
+	// the statuses of a deleted custom resource are dropped
+	m.Forget(types.NamespacedName{Name: cr.Name})
+	require.Empty(t, m.lastStatus)
+
+	// a custom resource recreated with the same name is tracked from scratch
+	m.SyncState(context.Background(), cr, catalog)
+	require.Equal(t, "Normal StateTransition State state-driver is notReady", <-recorder.Events)
+	recreated := cr.DeepCopy()
+	recreated.UID = "recreated-uid"
+	recreated.Status.Conditions = nil
+	m.SyncState(context.Background(), recreated, catalog)
+	require.Equal(t, "Normal StateTransition State state-driver is notReady", <-recorder.Events)
+	require.Empty(t, recorder.Events)
+}
@@ -0,0 +1,54 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package state
+
+import (
+	promcli "github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// nvidiaDriverReady reports the readiness of the driver DaemonSet of each
+// node pool of the NVIDIADriver instances
+var nvidiaDriverReady = promcli.NewGaugeVec(
+	promcli.GaugeOpts{
+		Name: "gpu_operator_nvidiadriver_ready",
+		Help: "1 if the driver DaemonSet of the node pool of the NVIDIADriver is ready, 0 otherwise",
+	},
+	[]string{"name", "pool"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(nvidiaDriverReady)
+}
+
+// setNVIDIADriverReadiness replaces the readiness of the node pools of the NVIDIADriver,
+// the pools which are not part of the instance anymore are removed
+func setNVIDIADriverReadiness(name string, pools map[string]bool) {
+	nvidiaDriverReady.DeletePartialMatch(promcli.Labels{"name": name})
+	for pool, ready := range pools {
+		value := 0.0
+		if ready {
+			value = 1
+		}
+		nvidiaDriverReady.WithLabelValues(name, pool).Set(value)
+	}
+}
+
+// DeleteNVIDIADriverMetrics removes the metrics of a deleted NVIDIADriver
+func DeleteNVIDIADriverMetrics(name string) {
+	nvidiaDriverReady.DeletePartialMatch(promcli.Labels{"name": name})
+}
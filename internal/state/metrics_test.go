@@ -0,0 +1,86 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+)
+
+func nvidiaDriverReadyValue(t *testing.T, name, pool string) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, nvidiaDriverReady.WithLabelValues(name, pool).Write(m))
+	return m.GetGauge().GetValue()
+}
+
+func TestDriverRecordNodePoolReadiness(t *testing.T) {
+	const namespace = "test-operator"
+	newDaemonSet := func(name string, available int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status: appsv1.DaemonSetStatus{
+				DesiredNumberScheduled: 2,
+				NumberAvailable:        available,
+				UpdatedNumberScheduled: available,
+			},
+		}
+	}
+	ready := newDaemonSet("nvidia-gpu-driver-ubuntu22.04-ready", 2)
+	notReady := newDaemonSet("nvidia-gpu-driver-rhel9.4-not-ready", 1)
+
+	var objs []*unstructured.Unstructured
+	for _, ds := range []*appsv1.DaemonSet{ready, notReady} {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ds)
+		require.NoError(t, err)
+		objs = append(objs, &unstructured.Unstructured{Object: u})
+	}
+	pools := map[string]string{
+		ready.Name:    "ubuntu22.04",
+		notReady.Name: "rhel9.4",
+	}
+
+	s := &stateDriver{
+		stateSkel: stateSkel{
+			client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ready, notReady).Build(),
+		},
+	}
+	cr := &nvidiav1alpha1.NVIDIADriver{ObjectMeta: metav1.ObjectMeta{Name: "test-driver"}}
+
+	// the series of a node pool which is gone are removed
+	nvidiaDriverReady.WithLabelValues(cr.Name, "ubuntu20.04").Set(1)
+
+	s.recordNodePoolReadiness(context.Background(), cr, objs, pools)
+	require.Equal(t, 1.0, nvidiaDriverReadyValue(t, cr.Name, "ubuntu22.04"))
+	require.Equal(t, 0.0, nvidiaDriverReadyValue(t, cr.Name, "rhel9.4"))
+	require.False(t, nvidiaDriverReady.DeleteLabelValues(cr.Name, "ubuntu20.04"))
+
+	DeleteNVIDIADriverMetrics(cr.Name)
+	require.False(t, nvidiaDriverReady.DeleteLabelValues(cr.Name, "ubuntu22.04"))
+	require.False(t, nvidiaDriverReady.DeleteLabelValues(cr.Name, "rhel9.4"))
+}
@@ -20,15 +20,20 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
 )
 
 const (
 	nfdKernelLabelKey        = "feature.node.kubernetes.io/kernel-version.full"
 	nfdOSTreeVersionLabelKey = "feature.node.kubernetes.io/system-os_release.OSTREE_VERSION"
+	// runtimeLabelKey is set by the ClusterPolicy controller on the GPU nodes to the container runtime of the node
+	runtimeLabelKey = "nvidia.com/gpu-operator.container-runtime"
 )
 
 // TODO: move this code to it's own module?
@@ -39,6 +44,7 @@ type nodePool struct {
 	osVersion    string
 	rhcosVersion string
 	kernel       string
+	runtime      string
 	nodeSelector map[string]string
 }
 
@@ -50,6 +56,8 @@ type nodePool struct {
 //  2. When running on OpenShift and precompiled is disabled, we create one node pool per rhcosVersion.
 //  3. Otherwise, we create one node pool per osVersion.
 //
+// When the nodes run more than one container runtime, each node pool is further partitioned per runtime.
+//
 // Each nodePool object contains information needed to identify the corresonding node pool.
 // Most importantly, it contains a nodeSelector used to identify the node pool.
 func getNodePools(ctx context.Context, k8sClient client.Client, selector map[string]string, precompiled bool, openshift bool) ([]nodePool, error) {
@@ -70,6 +78,12 @@ func getNodePools(ctx context.Context, k8sClient client.Client, selector map[str
 		return nil, err
 	}
 
+	// the nodes are only partitioned per container runtime if they run more than one
+	runtimes := make(map[string]bool)
+	for _, node := range nodeList.Items {
+		runtimes[getNodeRuntime(node, openshift)] = true
+	}
+
 	for _, node := range nodeList.Items {
 		node := node
 		nodeLabels := node.GetLabels()
@@ -117,6 +131,16 @@ func getNodePools(ctx context.Context, k8sClient client.Client, selector map[str
 			nodePool.name = rhcosVersion
 		}
 
+		nodePool.runtime = getNodeRuntime(node, openshift)
+		if len(runtimes) > 1 {
+			if nodePool.runtime == "" {
+				logger.Info("WARNING: Could not detect the container runtime of node, skipping it", "Node", node.Name)
+				continue
+			}
+			nodePool.nodeSelector[runtimeLabelKey] = nodePool.runtime
+			nodePool.name = fmt.Sprintf("%s-%s", nodePool.name, nodePool.runtime)
+		}
+
 		if _, exists := nodePoolMap[nodePool.name]; !exists {
 			logger.Info("Detected new node pool", "NodePool", nodePool)
 			nodePoolMap[nodePool.name] = nodePool
@@ -134,3 +158,21 @@ func getNodePools(ctx context.Context, k8sClient client.Client, selector map[str
 func (n nodePool) getOS() string {
 	return fmt.Sprintf("%s%s", n.osRelease, n.osVersion)
 }
+
+// getNodeRuntime returns the container runtime of a node, or an empty string if it is not recognized
+func getNodeRuntime(node corev1.Node, openshift bool) string {
+	if openshift {
+		return consts.CRIO
+	}
+	// ContainerRuntimeVersion string will look like <runtime>://<x.y.z>
+	runtimeVer := node.Status.NodeInfo.ContainerRuntimeVersion
+	switch {
+	case strings.HasPrefix(runtimeVer, "docker"):
+		return consts.Docker
+	case strings.HasPrefix(runtimeVer, "containerd"):
+		return consts.Containerd
+	case strings.HasPrefix(runtimeVer, "cri-o"):
+		return consts.CRIO
+	}
+	return ""
+}
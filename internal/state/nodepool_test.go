@@ -0,0 +1,133 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package state
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/NVIDIA/gpu-operator/internal/consts"
+)
+
+func newTestPoolNode(name string, osID string, osVersion string, runtimeVersion string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"nvidia.com/gpu.present": "true",
+				nfdOSReleaseIDLabelKey:   osID,
+				nfdOSVersionIDLabelKey:   osVersion,
+			},
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{ContainerRuntimeVersion: runtimeVersion},
+		},
+	}
+}
+
+func TestGetNodePools(t *testing.T) {
+	testCases := []struct {
+		description string
+		nodes       []client.Object
+		expected    []nodePool
+	}{
+		{
+			description: "single container runtime",
+			nodes: []client.Object{
+				newTestPoolNode("node-1", "rhel", "8.8", "cri-o://1.28.1"),
+				newTestPoolNode("node-2", "rhel", "8.8", "cri-o://1.28.1"),
+				newTestPoolNode("node-3", "ubuntu", "22.04", "cri-o://1.28.1"),
+			},
+			expected: []nodePool{
+				{
+					name:      "rhel8.8",
+					osRelease: "rhel",
+					osVersion: "8.8",
+					runtime:   consts.CRIO,
+					nodeSelector: map[string]string{
+						"nvidia.com/gpu.present": "true",
+						nfdOSReleaseIDLabelKey:   "rhel",
+						nfdOSVersionIDLabelKey:   "8.8",
+					},
+				},
+				{
+					name:      "ubuntu22.04",
+					osRelease: "ubuntu",
+					osVersion: "22.04",
+					runtime:   consts.CRIO,
+					nodeSelector: map[string]string{
+						"nvidia.com/gpu.present": "true",
+						nfdOSReleaseIDLabelKey:   "ubuntu",
+						nfdOSVersionIDLabelKey:   "22.04",
+					},
+				},
+			},
+		},
+		{
+			description: "node pools partitioned per container runtime",
+			nodes: []client.Object{
+				newTestPoolNode("node-1", "rhel", "8.8", "cri-o://1.28.1"),
+				newTestPoolNode("node-2", "rhel", "8.8", "containerd://1.7.2"),
+				newTestPoolNode("node-3", "rhel", "8.8", "unknown://1.0"),
+			},
+			expected: []nodePool{
+				{
+					name:      "rhel8.8-containerd",
+					osRelease: "rhel",
+					osVersion: "8.8",
+					runtime:   consts.Containerd,
+					nodeSelector: map[string]string{
+						"nvidia.com/gpu.present": "true",
+						nfdOSReleaseIDLabelKey:   "rhel",
+						nfdOSVersionIDLabelKey:   "8.8",
+						runtimeLabelKey:          consts.Containerd,
+					},
+				},
+				{
+					name:      "rhel8.8-crio",
+					osRelease: "rhel",
+					osVersion: "8.8",
+					runtime:   consts.CRIO,
+					nodeSelector: map[string]string{
+						"nvidia.com/gpu.present": "true",
+						nfdOSReleaseIDLabelKey:   "rhel",
+						nfdOSVersionIDLabelKey:   "8.8",
+						runtimeLabelKey:          consts.CRIO,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.nodes...).Build()
+			pools, err := getNodePools(context.Background(), k8sClient, nil, false, false)
+			require.NoError(t, err)
+			sort.Slice(pools, func(i, j int) bool { return pools[i].name < pools[j].name })
+			require.Equal(t, tc.expected, pools)
+		})
+	}
+}
@@ -23,15 +23,18 @@ import (
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/NVIDIA/gpu-operator/internal/apply"
 	"github.com/NVIDIA/gpu-operator/internal/consts"
 	"github.com/NVIDIA/gpu-operator/internal/nodeinfo"
 	"github.com/NVIDIA/gpu-operator/internal/render"
@@ -46,6 +49,7 @@ type stateSkel struct {
 
 	client   client.Client
 	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 	renderer render.Renderer
 }
 
@@ -59,6 +63,14 @@ func (s *stateSkel) Description() string {
 	return s.description
 }
 
+// recordEvent records an Event on obj, if the state has an event recorder
+func (s *stateSkel) recordEvent(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
 func getSupportedGVKs() []schema.GroupVersionKind {
 	return []schema.GroupVersionKind{
 		{
@@ -177,19 +189,16 @@ func (s *stateSkel) getObj(ctx context.Context, obj *unstructured.Unstructured)
 	return err
 }
 
-func (s *stateSkel) createObj(ctx context.Context, obj *unstructured.Unstructured) error {
+// applyObj server-side applies obj, so that only the fields rendered by the operator are owned by it
+func (s *stateSkel) applyObj(ctx context.Context, obj *unstructured.Unstructured) error {
 	reqLogger := log.FromContext(ctx)
 
 	s.checkDeleteSupported(ctx, obj)
-	reqLogger.V(consts.LogLevelInfo).Info("Creating Object", "Namespace:", obj.GetNamespace(), "Name:", obj.GetName())
-	toCreate := obj.DeepCopy()
-	if err := s.client.Create(ctx, toCreate); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			reqLogger.V(consts.LogLevelInfo).Info("Object Already Exists")
-		}
+	reqLogger.V(consts.LogLevelInfo).Info("Applying Object", "Namespace:", obj.GetNamespace(), "Name:", obj.GetName())
+	if _, err := apply.Apply(ctx, s.client, obj.DeepCopy()); err != nil {
 		return err
 	}
-	reqLogger.V(consts.LogLevelInfo).Info("Object created successfully")
+	reqLogger.V(consts.LogLevelInfo).Info("Object applied successfully")
 	return nil
 }
 
@@ -206,22 +215,9 @@ func (s *stateSkel) checkDeleteSupported(ctx context.Context, obj *unstructured.
 		"Namespace:", obj.GetNamespace(), "Name:", obj.GetName(), "GVK", obj.GroupVersionKind())
 }
 
-func (s *stateSkel) updateObj(ctx context.Context, obj *unstructured.Unstructured) error {
-	reqLogger := log.FromContext(ctx)
-	reqLogger.V(consts.LogLevelInfo).Info("Updating Object", "Namespace:", obj.GetNamespace(), "Name:", obj.GetName())
-
-	// Note: Some objects may require update of the resource version
-	// TODO: using Patch preserves runtime attributes. In the future consider using patch if relevant
-	desired := obj.DeepCopy()
-	if err := s.client.Update(ctx, desired); err != nil {
-		return fmt.Errorf("failed to update resource: %w", err)
-	}
-	reqLogger.V(consts.LogLevelInfo).Info("Object updated successfully")
-	return nil
-}
-
 func (s *stateSkel) createOrUpdateObjs(
 	ctx context.Context,
+	owner client.Object,
 	setControllerReference func(obj *unstructured.Unstructured) error,
 	objs []*unstructured.Unstructured) error {
 	reqLogger := log.FromContext(ctx)
@@ -235,53 +231,55 @@ func (s *stateSkel) createOrUpdateObjs(
 
 		s.addStateSpecificLabels(desiredObj)
 
-		var desiredObjectHash string
 		if desiredObj.GetKind() == "DaemonSet" {
-			desiredObjectHash = utils.GetObjectHash(desiredObj)
+			// the hash of the rendered DaemonSet allows to detect spec changes
 			annotations := desiredObj.GetAnnotations()
 			if annotations == nil {
 				annotations = make(map[string]string)
 			}
-			annotations[consts.NvidiaAnnotationHashKey] = desiredObjectHash
+			annotations[consts.NvidiaAnnotationHashKey] = utils.GetObjectHash(desiredObj)
 			desiredObj.SetAnnotations(annotations)
 		}
 
-		err := s.createObj(ctx, desiredObj)
-		if err == nil {
-			// object created successfully
-			continue
-		}
-		if !apierrors.IsAlreadyExists(err) {
-			// Some error occurred
+		reason, err := s.getDaemonSetEventReason(ctx, desiredObj)
+		if err != nil {
 			return err
 		}
-
-		currentObj := desiredObj.DeepCopy()
-		if err := s.getObj(ctx, currentObj); err != nil {
-			// Some error occurred
+		if err := s.applyObj(ctx, desiredObj); err != nil {
 			return err
 		}
-
-		if desiredObj.GetKind() == "DaemonSet" {
-			if currentObjHash, ok := currentObj.GetAnnotations()[consts.NvidiaAnnotationHashKey]; ok {
-				if desiredObjectHash == currentObjHash {
-					reqLogger.V(consts.LogLevelDebug).Info("Object is unchanged, so skipping update",
-						"Kind", desiredObj.GetKind(), "Name", desiredObj.GetName())
-					return nil
-				}
-			}
+		if reason != "" {
+			s.recordEvent(owner, corev1.EventTypeNormal, reason, "DaemonSet %s/%s %s",
+				desiredObj.GetNamespace(), desiredObj.GetName(), daemonSetAction[reason])
 		}
+	}
+	return nil
+}
 
-		if err := s.mergeObjects(desiredObj, currentObj); err != nil {
-			return err
-		}
+var daemonSetAction = map[string]string{
+	consts.DaemonSetCreatedReason: "created",
+	consts.DaemonSetUpdatedReason: "updated",
+}
 
-		// Object found, Update it
-		if err := s.updateObj(ctx, desiredObj); err != nil {
-			return err
-		}
+// getDaemonSetEventReason returns the reason of the Event to record when applying obj, if it is a
+// DaemonSet which does not exist yet or whose rendered spec changed, and an empty reason otherwise
+func (s *stateSkel) getDaemonSetEventReason(ctx context.Context, obj *unstructured.Unstructured) (string, error) {
+	if obj.GetKind() != "DaemonSet" {
+		return "", nil
 	}
-	return nil
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	err := s.client.Get(ctx, client.ObjectKeyFromObject(obj), live)
+	if apierrors.IsNotFound(err) {
+		return consts.DaemonSetCreatedReason, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get DaemonSet %s: %w", obj.GetName(), err)
+	}
+	if live.GetAnnotations()[consts.NvidiaAnnotationHashKey] != obj.GetAnnotations()[consts.NvidiaAnnotationHashKey] {
+		return consts.DaemonSetUpdatedReason, nil
+	}
+	return "", nil
 }
 
 func (s *stateSkel) addStateSpecificLabels(obj *unstructured.Unstructured) {
@@ -341,44 +339,6 @@ func (s *stateSkel) deleteStateRelatedObjects(ctx context.Context) (bool, error)
 	return found, nil
 }
 
-func (s *stateSkel) mergeObjects(updated, current *unstructured.Unstructured) error {
-	// Set resource version
-	// ResourceVersion must be passed unmodified back to the server.
-	// ResourceVersion helps the kubernetes API server to implement optimistic concurrency for PUT operations
-	// when two PUT requests are specifying the resourceVersion, one of the PUTs will fail.
-	updated.SetResourceVersion(current.GetResourceVersion())
-
-	gvk := updated.GroupVersionKind()
-	if gvk.Group == "" && gvk.Kind == "ServiceAccount" {
-		return s.mergeServiceAccount(updated, current)
-	}
-	return nil
-}
-
-// For Service Account, keep secrets if exists
-func (s *stateSkel) mergeServiceAccount(updated, current *unstructured.Unstructured) error {
-	curSecrets, ok, err := unstructured.NestedSlice(current.Object, "secrets")
-	if err != nil {
-		return err
-	}
-	if ok {
-		if err := unstructured.SetNestedField(updated.Object, curSecrets, "secrets"); err != nil {
-			return err
-		}
-	}
-
-	curImagePullSecrets, ok, err := unstructured.NestedSlice(current.Object, "imagePullSecrets")
-	if err != nil {
-		return err
-	}
-	if ok {
-		if err := unstructured.SetNestedField(updated.Object, curImagePullSecrets, "imagePullSecrets"); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // Iterate over objects and check for their readiness
 func (s *stateSkel) getSyncState(ctx context.Context, objs []*unstructured.Unstructured) (SyncState, error) {
 	reqLogger := log.FromContext(ctx)
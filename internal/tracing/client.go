@@ -0,0 +1,170 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package tracing
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tracedClient starts a span for each API call made through the embedded client
+type tracedClient struct {
+	client.Client
+}
+
+// WrapClient returns a client starting a span for each API call, tagged with
+// the verb and the kind, name and namespace of the object
+func WrapClient(c client.Client) client.Client {
+	return &tracedClient{Client: c}
+}
+
+// call describes an API call traced by the client
+type call struct {
+	verb        string
+	subResource string
+	obj         runtime.Object
+	name        string
+	namespace   string
+}
+
+// start starts the span of the API call
+func (a call) start(ctx context.Context, c client.Client) (context.Context, trace.Span) {
+	kind := objectKind(c, a.obj)
+	attrs := []attribute.KeyValue{VerbKey.String(a.verb), ObjectKindKey.String(kind)}
+	if a.name != "" {
+		attrs = append(attrs, ObjectNameKey.String(a.name))
+	}
+	if a.namespace != "" {
+		attrs = append(attrs, ObjectNamespaceKey.String(a.namespace))
+	}
+	name := a.verb + " " + kind
+	if a.subResource != "" {
+		attrs = append(attrs, SubResourceKey.String(a.subResource))
+		name += "/" + a.subResource
+	}
+	return Start(ctx, name, attrs...)
+}
+
+// objectCall returns the API call with the given verb on the object
+func objectCall(verb string, obj client.Object) call {
+	return call{verb: verb, obj: obj, name: obj.GetName(), namespace: obj.GetNamespace()}
+}
+
+// statusCall returns the API call with the given verb on the status of the object
+func statusCall(verb string, obj client.Object) call {
+	a := objectCall(verb, obj)
+	a.subResource = "status"
+	return a
+}
+
+// objectKind returns the kind of the object, or its Go type if it is not registered in the scheme.
+// The kind of a list is the kind of its items.
+func objectKind(c client.Client, obj runtime.Object) string {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil || gvk.Kind == "" {
+		return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	if _, isList := obj.(client.ObjectList); isList {
+		return strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return gvk.Kind
+}
+
+// endCall ends the span of an API call, a missing object is not recorded as an error
+func endCall(span trace.Span, err error) {
+	if apierrors.IsNotFound(err) {
+		err = nil
+	}
+	End(span, err)
+}
+
+func (c *tracedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) (err error) {
+	ctx, span := call{verb: "get", obj: obj, name: key.Name, namespace: key.Namespace}.start(ctx, c.Client)
+	defer func() { endCall(span, err) }()
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *tracedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	ctx, span := call{verb: "list", obj: list}.start(ctx, c.Client)
+	defer func() { endCall(span, err) }()
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *tracedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) (err error) {
+	ctx, span := objectCall("create", obj).start(ctx, c.Client)
+	defer func() { endCall(span, err) }()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) (err error) {
+	ctx, span := objectCall("delete", obj).start(ctx, c.Client)
+	defer func() { endCall(span, err) }()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) (err error) {
+	ctx, span := objectCall("update", obj).start(ctx, c.Client)
+	defer func() { endCall(span, err) }()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) (err error) {
+	ctx, span := objectCall("patch", obj).start(ctx, c.Client)
+	defer func() { endCall(span, err) }()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *tracedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) (err error) {
+	ctx, span := objectCall("deletecollection", obj).start(ctx, c.Client)
+	defer func() { endCall(span, err) }()
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Status() client.SubResourceWriter {
+	return &tracedStatusWriter{SubResourceWriter: c.Client.Status(), client: c.Client}
+}
+
+// tracedStatusWriter starts a span for each update of the status of an object
+type tracedStatusWriter struct {
+	client.SubResourceWriter
+	client client.Client
+}
+
+func (w *tracedStatusWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) (err error) {
+	ctx, span := statusCall("create", obj).start(ctx, w.client)
+	defer func() { endCall(span, err) }()
+	return w.SubResourceWriter.Create(ctx, obj, subResource, opts...)
+}
+
+func (w *tracedStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) (err error) {
+	ctx, span := statusCall("update", obj).start(ctx, w.client)
+	defer func() { endCall(span, err) }()
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *tracedStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) (err error) {
+	ctx, span := statusCall("patch", obj).start(ctx, w.client)
+	defer func() { endCall(span, err) }()
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
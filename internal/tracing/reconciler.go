@@ -0,0 +1,49 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconciler starts a span for each reconcile of the wrapped reconciler
+type reconciler struct {
+	name string
+	reconcile.Reconciler
+}
+
+// WrapReconciler returns a reconciler starting a span for each reconcile of the named controller.
+// The API calls and states synced by the reconcile are traced as children of the span.
+func WrapReconciler(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &reconciler{name: name, Reconciler: r}
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (res reconcile.Result, err error) {
+	attrs := []attribute.KeyValue{Controller(r.name), ObjectNameKey.String(req.Name)}
+	if req.Namespace != "" {
+		attrs = append(attrs, ObjectNamespaceKey.String(req.Namespace))
+	}
+	ctx, span := Start(ctx, "Reconcile "+r.name, attrs...)
+	defer func() {
+		span.SetAttributes(RequeueAfterKey.Int64(res.RequeueAfter.Milliseconds()))
+		End(span, err)
+	}()
+	return r.Reconciler.Reconcile(ctx, req)
+}
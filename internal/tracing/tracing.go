@@ -0,0 +1,156 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package tracing traces the reconciles of the operator controllers with OpenTelemetry.
+// The spans are exported with OTLP when an endpoint is configured, and dropped otherwise.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the instrumentation scope of the spans of the operator
+	tracerName = "github.com/NVIDIA/gpu-operator"
+	// serviceName is the service the spans of the operator are reported for
+	serviceName = "gpu-operator"
+)
+
+// Attribute keys of the spans of the operator
+const (
+	ControllerKey      = attribute.Key("gpu_operator.controller")
+	StateKey           = attribute.Key("gpu_operator.state")
+	NodePoolKey        = attribute.Key("gpu_operator.node_pool")
+	ObjectKindKey      = attribute.Key("k8s.object.kind")
+	ObjectNameKey      = attribute.Key("k8s.object.name")
+	ObjectNamespaceKey = semconv.K8SNamespaceNameKey
+	VerbKey            = attribute.Key("k8s.api.verb")
+	SubResourceKey     = attribute.Key("k8s.api.subresource")
+	StatusKey          = attribute.Key("gpu_operator.status")
+	RuntimeKey         = attribute.Key("gpu_operator.container_runtime")
+	RequeueAfterKey    = attribute.Key("gpu_operator.requeue_after_ms")
+)
+
+// Options configures the export of the spans
+type Options struct {
+	// Endpoint is the host:port of the OTLP gRPC collector, tracing is disabled if empty
+	Endpoint string
+	// Insecure disables the transport security of the connection to the collector
+	Insecure bool
+	// SampleRatio is the fraction of the reconciles traced, between 0 and 1
+	SampleRatio float64
+	// ServiceVersion is the version of the operator reported with the spans
+	ServiceVersion string
+}
+
+// Setup registers the global tracer provider exporting the spans to the configured
+// OTLP endpoint, and returns the function flushing the spans on shutdown.
+// It is a no-op when no endpoint is configured.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("trace sample ratio must be between 0 and 1, got %v", opts.SampleRatio)
+	}
+
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(opts.ServiceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// attributesKey is the context key of the attributes inherited by the spans
+type attributesKey struct{}
+
+// WithAttributes returns a copy of the context whose spans started with Start carry
+// the given attributes, e.g. the node pool of the objects being synced
+func WithAttributes(ctx context.Context, kv ...attribute.KeyValue) context.Context {
+	inherited := contextAttributes(ctx)
+	attrs := make([]attribute.KeyValue, 0, len(inherited)+len(kv))
+	attrs = append(attrs, inherited...)
+	attrs = append(attrs, kv...)
+	return context.WithValue(ctx, attributesKey{}, attrs)
+}
+
+// contextAttributes returns the attributes inherited by the spans started with the context
+func contextAttributes(ctx context.Context) []attribute.KeyValue {
+	attrs, _ := ctx.Value(attributesKey{}).([]attribute.KeyValue)
+	return attrs
+}
+
+// Start starts a span carrying the given attributes and the attributes of the context.
+// The returned context passes the given attributes on to the spans started with it.
+func Start(ctx context.Context, name string, kv ...attribute.KeyValue) (context.Context, trace.Span) {
+	if len(kv) != 0 {
+		ctx = WithAttributes(ctx, kv...)
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(contextAttributes(ctx)...))
+}
+
+// End records the error, if any, on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Controller returns the attribute of the controller name
+func Controller(name string) attribute.KeyValue {
+	return ControllerKey.String(name)
+}
+
+// State returns the attribute of the state name
+func State(name string) attribute.KeyValue {
+	return StateKey.String(name)
+}
+
+// NodePool returns the attribute of the node pool name
+func NodePool(name string) attribute.KeyValue {
+	return NodePoolKey.String(name)
+}
+
+// Runtime returns the attribute of the container runtime
+func Runtime(name string) attribute.KeyValue {
+	return RuntimeKey.String(name)
+}
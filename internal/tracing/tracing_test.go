@@ -0,0 +1,155 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// newTestExporter registers a tracer provider recording the spans in memory for the duration of the test
+func newTestExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+// spanAttributes returns the attributes of the span as a map
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]string {
+	attrs := map[attribute.Key]string{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	return attrs
+}
+
+func TestStartInheritsAttributes(t *testing.T) {
+	exporter := newTestExporter(t)
+
+	ctx, parent := Start(context.Background(), "parent", State("state-driver"))
+	ctx = WithAttributes(ctx, NodePool("pool-a"))
+	_, child := Start(ctx, "child")
+	End(child, errors.New("failed"))
+	End(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	require.Equal(t, map[attribute.Key]string{
+		StateKey:    "state-driver",
+		NodePoolKey: "pool-a",
+	}, spanAttributes(spans[0]))
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Equal(t, map[attribute.Key]string{StateKey: "state-driver"}, spanAttributes(spans[1]))
+	require.Equal(t, codes.Unset, spans[1].Status.Code)
+}
+
+func TestWrapClient(t *testing.T) {
+	exporter := newTestExporter(t)
+
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset", Namespace: "gpu-operator"}}
+	c := WrapClient(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ds).Build())
+	ctx := WithAttributes(context.Background(), State("state-device-plugin"))
+
+	got := &appsv1.DaemonSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: ds.Name, Namespace: ds.Namespace}, got))
+	require.NoError(t, c.List(ctx, &corev1.NodeList{}))
+	require.NoError(t, c.Status().Update(ctx, got))
+	err := c.Get(ctx, types.NamespacedName{Name: "missing", Namespace: ds.Namespace}, &corev1.ConfigMap{})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+
+	require.Equal(t, "get DaemonSet", spans[0].Name)
+	require.Equal(t, map[attribute.Key]string{
+		StateKey:           "state-device-plugin",
+		VerbKey:            "get",
+		ObjectKindKey:      "DaemonSet",
+		ObjectNameKey:      ds.Name,
+		ObjectNamespaceKey: ds.Namespace,
+	}, spanAttributes(spans[0]))
+
+	require.Equal(t, "list Node", spans[1].Name)
+	require.Equal(t, "Node", spanAttributes(spans[1])[ObjectKindKey])
+
+	require.Equal(t, "update DaemonSet/status", spans[2].Name)
+	require.Equal(t, "status", spanAttributes(spans[2])[SubResourceKey])
+
+	// a missing object is not an error of the API call
+	require.Equal(t, "get ConfigMap", spans[3].Name)
+	require.Equal(t, codes.Unset, spans[3].Status.Code)
+}
+
+type fakeReconciler struct {
+	err error
+}
+
+func (r fakeReconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	_, span := Start(ctx, "step")
+	span.End()
+	return reconcile.Result{}, r.err
+}
+
+func TestWrapReconciler(t *testing.T) {
+	exporter := newTestExporter(t)
+
+	r := WrapReconciler("clusterpolicy-controller", fakeReconciler{err: errors.New("failed")})
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster-policy"}})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "Reconcile clusterpolicy-controller", spans[1].Name)
+	require.Equal(t, codes.Error, spans[1].Status.Code)
+	require.Equal(t, "clusterpolicy-controller", spanAttributes(spans[1])[ControllerKey])
+	require.Equal(t, "cluster-policy", spanAttributes(spans[1])[ObjectNameKey])
+	// the spans started by the reconcile are its children and carry the controller
+	require.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	require.Equal(t, "clusterpolicy-controller", spanAttributes(spans[0])[ControllerKey])
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), Options{Endpoint: "localhost:4317", SampleRatio: 2})
+	require.Error(t, err)
+}
@@ -0,0 +1,174 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validator
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/patch"
+)
+
+// supportedWorkloads is the list of GPU workload configurations supported for sandboxWorkloads.defaultWorkload
+var supportedWorkloads = []string{"container", "vm-passthrough", "vm-vgpu"}
+
+// ValidateClusterPolicySpec validates the constraints of a ClusterPolicy spec
+// which cannot be expressed through the CRD schema
+func ValidateClusterPolicySpec(spec *gpuv1.ClusterPolicySpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	// driver related constraints are enforced on the NVIDIADriver CRs when they are in use
+	if !spec.Driver.UseNvdiaDriverCRDType() {
+		allErrs = append(allErrs, validateClusterPolicyDriver(spec, specPath)...)
+	}
+
+	if spec.KataManager.IsEnabled() && spec.KataManager.Config != nil {
+		for i, rc := range spec.KataManager.Config.RuntimeClasses {
+			if rc.Name != "" && rc.Name == spec.Operator.RuntimeClass {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("kataManager", "config", "runtimeClasses").Index(i).Child("name"), rc.Name,
+					fmt.Sprintf("conflicts with the runtimeclass used for the gpu-operator operand pods (operator.runtimeClass=%s)", spec.Operator.RuntimeClass)))
+			}
+		}
+	}
+
+	if workload := spec.SandboxWorkloads.DefaultWorkload; workload != "" {
+		valid := false
+		for _, w := range supportedWorkloads {
+			if workload == w {
+				valid = true
+			}
+		}
+		if !valid {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("sandboxWorkloads", "defaultWorkload"), workload, supportedWorkloads))
+		}
+	}
+
+	allErrs = append(allErrs, validateClusterPolicyImages(spec, specPath)...)
+	allErrs = append(allErrs, validateNodePoolOverrides(spec, specPath)...)
+	allErrs = append(allErrs, validateClusterPolicyPodTemplatePatches(spec, specPath)...)
+
+	return allErrs
+}
+
+func validateClusterPolicyDriver(spec *gpuv1.ClusterPolicySpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	gdsEnabled := spec.GPUDirectStorage != nil && spec.GPUDirectStorage.IsEnabled()
+	gdrcopyEnabled := spec.GDRCopy != nil && spec.GDRCopy.IsEnabled()
+
+	if spec.Driver.UsePrecompiledDrivers() {
+		if gdsEnabled {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("gds", "enabled"), true,
+				"GPUDirect Storage driver (nvidia-fs) is not supported along with pre-compiled NVIDIA drivers"))
+		}
+		if gdrcopyEnabled {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("gdrcopy", "enabled"), true,
+				"GDRCopy is not supported along with pre-compiled NVIDIA drivers"))
+		}
+	}
+
+	if gdsEnabled && spec.GPUDirectStorage.IsOpenKernelModulesRequired() && !spec.Driver.OpenKernelModulesEnabled() {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("driver", "useOpenKernelModules"), spec.Driver.OpenKernelModulesEnabled(),
+			fmt.Sprintf("GPUDirect Storage driver '%s' is only supported with NVIDIA OpenRM drivers", spec.GPUDirectStorage.Version)))
+	}
+
+	return allErrs
+}
+
+func validateClusterPolicyImages(spec *gpuv1.ClusterPolicySpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateImage(specPath.Child("operator", "initContainer"), spec.Operator.InitContainer.Repository, spec.Operator.InitContainer.Image, spec.Operator.InitContainer.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("driver"), spec.Driver.Repository, spec.Driver.Image, spec.Driver.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("driver", "manager"), spec.Driver.Manager.Repository, spec.Driver.Manager.Image, spec.Driver.Manager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("toolkit"), spec.Toolkit.Repository, spec.Toolkit.Image, spec.Toolkit.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("devicePlugin"), spec.DevicePlugin.Repository, spec.DevicePlugin.Image, spec.DevicePlugin.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("dcgmExporter"), spec.DCGMExporter.Repository, spec.DCGMExporter.Image, spec.DCGMExporter.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("dcgm"), spec.DCGM.Repository, spec.DCGM.Image, spec.DCGM.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("nodeStatusExporter"), spec.NodeStatusExporter.Repository, spec.NodeStatusExporter.Image, spec.NodeStatusExporter.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("gfd"), spec.GPUFeatureDiscovery.Repository, spec.GPUFeatureDiscovery.Image, spec.GPUFeatureDiscovery.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("migManager"), spec.MIGManager.Repository, spec.MIGManager.Image, spec.MIGManager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("validator"), spec.Validator.Repository, spec.Validator.Image, spec.Validator.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("vfioManager"), spec.VFIOManager.Repository, spec.VFIOManager.Image, spec.VFIOManager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("vfioManager", "driverManager"), spec.VFIOManager.DriverManager.Repository, spec.VFIOManager.DriverManager.Image, spec.VFIOManager.DriverManager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("sandboxDevicePlugin"), spec.SandboxDevicePlugin.Repository, spec.SandboxDevicePlugin.Image, spec.SandboxDevicePlugin.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("vgpuManager"), spec.VGPUManager.Repository, spec.VGPUManager.Image, spec.VGPUManager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("vgpuManager", "driverManager"), spec.VGPUManager.DriverManager.Repository, spec.VGPUManager.DriverManager.Image, spec.VGPUManager.DriverManager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("vgpuDeviceManager"), spec.VGPUDeviceManager.Repository, spec.VGPUDeviceManager.Image, spec.VGPUDeviceManager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("kataManager"), spec.KataManager.Repository, spec.KataManager.Image, spec.KataManager.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("ccManager"), spec.CCManager.Repository, spec.CCManager.Image, spec.CCManager.Version)...)
+	if spec.GPUDirectStorage != nil {
+		allErrs = append(allErrs, validateImage(specPath.Child("gds"), spec.GPUDirectStorage.Repository, spec.GPUDirectStorage.Image, spec.GPUDirectStorage.Version)...)
+	}
+	if spec.GDRCopy != nil {
+		allErrs = append(allErrs, validateImage(specPath.Child("gdrcopy"), spec.GDRCopy.Repository, spec.GDRCopy.Image, spec.GDRCopy.Version)...)
+	}
+
+	return allErrs
+}
+
+func validateClusterPolicyPodTemplatePatches(spec *gpuv1.ClusterPolicySpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("driver"), spec.Driver.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("toolkit"), spec.Toolkit.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("devicePlugin"), spec.DevicePlugin.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("dcgmExporter"), spec.DCGMExporter.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("dcgm"), spec.DCGM.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("nodeStatusExporter"), spec.NodeStatusExporter.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("gfd"), spec.GPUFeatureDiscovery.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("migManager"), spec.MIGManager.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("validator"), spec.Validator.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("vfioManager"), spec.VFIOManager.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("sandboxDevicePlugin"), spec.SandboxDevicePlugin.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("vgpuManager"), spec.VGPUManager.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("vgpuDeviceManager"), spec.VGPUDeviceManager.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("kataManager"), spec.KataManager.PodTemplatePatch)...)
+	allErrs = append(allErrs, validatePodTemplatePatch(specPath.Child("ccManager"), spec.CCManager.PodTemplatePatch)...)
+
+	for i, o := range spec.NodePoolOverrides {
+		path := specPath.Child("nodePoolOverrides").Index(i)
+		if o.DevicePlugin != nil {
+			allErrs = append(allErrs, validatePodTemplatePatch(path.Child("devicePlugin"), o.DevicePlugin.PodTemplatePatch)...)
+		}
+		if o.DCGMExporter != nil {
+			allErrs = append(allErrs, validatePodTemplatePatch(path.Child("dcgmExporter"), o.DCGMExporter.PodTemplatePatch)...)
+		}
+		if o.GPUFeatureDiscovery != nil {
+			allErrs = append(allErrs, validatePodTemplatePatch(path.Child("gfd"), o.GPUFeatureDiscovery.PodTemplatePatch)...)
+		}
+		if o.MIGManager != nil {
+			allErrs = append(allErrs, validatePodTemplatePatch(path.Child("migManager"), o.MIGManager.PodTemplatePatch)...)
+		}
+	}
+
+	return allErrs
+}
+
+func validatePodTemplatePatch(fldPath *field.Path, p *gpuv1.PodTemplatePatch) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p == nil {
+		return allErrs
+	}
+	if err := patch.Validate(p); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("podTemplatePatch", "patch"), p.Patch, err.Error()))
+	}
+	return allErrs
+}
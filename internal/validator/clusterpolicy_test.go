@@ -0,0 +1,170 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validator
+
+import (
+	"testing"
+
+	kata_v1alpha1 "github.com/NVIDIA/k8s-kata-manager/api/v1alpha1/config"
+	"github.com/stretchr/testify/require"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func TestValidateClusterPolicySpec(t *testing.T) {
+	boolTrue := true
+
+	tests := []struct {
+		description string
+		spec        gpuv1.ClusterPolicySpec
+		errFields   []string
+	}{
+		{
+			description: "empty spec",
+			spec:        gpuv1.ClusterPolicySpec{},
+		},
+		{
+			description: "valid images",
+			spec: gpuv1.ClusterPolicySpec{
+				Driver:       gpuv1.DriverSpec{Repository: "nvcr.io/nvidia", Image: "driver", Version: "550.54.15"},
+				DevicePlugin: gpuv1.DevicePluginSpec{Repository: "nvcr.io/nvidia", Image: "k8s-device-plugin", Version: "sha256:" + testDigest},
+				Toolkit:      gpuv1.ToolkitSpec{Image: "nvcr.io/nvidia/k8s/container-toolkit@sha256:" + testDigest},
+			},
+		},
+		{
+			description: "malformed image fields",
+			spec: gpuv1.ClusterPolicySpec{
+				Driver:       gpuv1.DriverSpec{Repository: "nvcr.io/nvidia", Image: "driver"},
+				DevicePlugin: gpuv1.DevicePluginSpec{Repository: "nvcr.io/nvidia", Image: "k8s-device-plugin", Version: "v0.15.0 rc"},
+			},
+			errFields: []string{"spec.driver.version", "spec.devicePlugin"},
+		},
+		{
+			description: "precompiled with gds and gdrcopy",
+			spec: gpuv1.ClusterPolicySpec{
+				Driver:           gpuv1.DriverSpec{UsePrecompiled: &boolTrue},
+				GPUDirectStorage: &gpuv1.GPUDirectStorageSpec{Enabled: &boolTrue, Repository: "nvcr.io/nvidia/cloud-native", Image: "nvidia-fs", Version: "2.16.1"},
+				GDRCopy:          &gpuv1.GDRCopySpec{Enabled: &boolTrue},
+			},
+			errFields: []string{"spec.gds.enabled", "spec.gdrcopy.enabled"},
+		},
+		{
+			description: "gds requires open kernel modules",
+			spec: gpuv1.ClusterPolicySpec{
+				GPUDirectStorage: &gpuv1.GPUDirectStorageSpec{Enabled: &boolTrue, Repository: "nvcr.io/nvidia/cloud-native", Image: "nvidia-fs", Version: "2.17.5"},
+			},
+			errFields: []string{"spec.driver.useOpenKernelModules"},
+		},
+		{
+			description: "gds with open kernel modules",
+			spec: gpuv1.ClusterPolicySpec{
+				Driver:           gpuv1.DriverSpec{UseOpenKernelModules: &boolTrue},
+				GPUDirectStorage: &gpuv1.GPUDirectStorageSpec{Enabled: &boolTrue, Repository: "nvcr.io/nvidia/cloud-native", Image: "nvidia-fs", Version: "2.17.5"},
+			},
+		},
+		{
+			description: "kata runtimeclass conflicts with operator runtimeclass",
+			spec: gpuv1.ClusterPolicySpec{
+				Operator: gpuv1.OperatorSpec{RuntimeClass: "nvidia"},
+				KataManager: gpuv1.KataManagerSpec{
+					Enabled: &boolTrue,
+					Config: &kata_v1alpha1.Config{
+						RuntimeClasses: []kata_v1alpha1.RuntimeClass{{Name: "kata-qemu-nvidia-gpu"}, {Name: "nvidia"}},
+					},
+				},
+			},
+			errFields: []string{"spec.kataManager.config.runtimeClasses[1].name"},
+		},
+		{
+			description: "invalid default workload",
+			spec: gpuv1.ClusterPolicySpec{
+				SandboxWorkloads: gpuv1.SandboxWorkloadsSpec{DefaultWorkload: "vm"},
+			},
+			errFields: []string{"spec.sandboxWorkloads.defaultWorkload"},
+		},
+		{
+			description: "valid node pool overrides",
+			spec: gpuv1.ClusterPolicySpec{
+				DevicePlugin: gpuv1.DevicePluginSpec{Repository: "nvcr.io/nvidia", Image: "k8s-device-plugin", Version: "v0.15.0"},
+				NodePoolOverrides: []gpuv1.NodePoolOverride{
+					{
+						Name:         "a100",
+						NodeSelector: map[string]string{"nvidia.com/gpu.product": "A100-SXM4-80GB"},
+						// merged with the cluster-wide repository and image
+						DevicePlugin: &gpuv1.DevicePluginSpec{Version: "v0.16.0"},
+						MIG:          &gpuv1.MIGSpec{Strategy: gpuv1.MIGStrategyMixed},
+					},
+				},
+			},
+		},
+		{
+			description: "invalid node pool overrides",
+			spec: gpuv1.ClusterPolicySpec{
+				NodePoolOverrides: []gpuv1.NodePoolOverride{
+					{Name: "empty", NodeSelector: map[string]string{"pool": "empty"}},
+					{Name: "l4", DCGMExporter: &gpuv1.DCGMExporterSpec{Repository: "nvcr.io/nvidia/k8s", Image: "dcgm-exporter"}},
+				},
+			},
+			errFields: []string{"spec.nodePoolOverrides[0]", "spec.nodePoolOverrides[1].nodeSelector", "spec.nodePoolOverrides[1].dcgmExporter.version"},
+		},
+		{
+			description: "valid pod template patches",
+			spec: gpuv1.ClusterPolicySpec{
+				Driver: gpuv1.DriverSpec{PodTemplatePatch: &gpuv1.PodTemplatePatch{
+					Patch: "spec:\n  hostAliases:\n  - ip: 10.0.0.1\n    hostnames: [\"mirror.local\"]\n",
+				}},
+				DCGMExporter: gpuv1.DCGMExporterSpec{PodTemplatePatch: &gpuv1.PodTemplatePatch{
+					Type:  gpuv1.PodTemplatePatchJSON,
+					Patch: `[{"op": "add", "path": "/spec/containers/0/args/-", "value": "-v"}]`,
+				}},
+			},
+		},
+		{
+			description: "invalid pod template patches",
+			spec: gpuv1.ClusterPolicySpec{
+				Toolkit: gpuv1.ToolkitSpec{PodTemplatePatch: &gpuv1.PodTemplatePatch{
+					Patch: "spec:\n  hostAliasses: []\n",
+				}},
+				Validator: gpuv1.ValidatorSpec{PodTemplatePatch: &gpuv1.PodTemplatePatch{
+					Type:  gpuv1.PodTemplatePatchJSON,
+					Patch: `{"op": "add"}`,
+				}},
+				NodePoolOverrides: []gpuv1.NodePoolOverride{
+					{
+						Name:         "l4",
+						NodeSelector: map[string]string{"nvidia.com/gpu.product": "L4"},
+						GPUFeatureDiscovery: &gpuv1.GPUFeatureDiscoverySpec{PodTemplatePatch: &gpuv1.PodTemplatePatch{
+							Patch: "- spec",
+						}},
+					},
+				},
+			},
+			errFields: []string{"spec.toolkit.podTemplatePatch.patch", "spec.validator.podTemplatePatch.patch", "spec.nodePoolOverrides[0].gfd.podTemplatePatch.patch"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			errs := ValidateClusterPolicySpec(&tc.spec)
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			require.ElementsMatch(t, tc.errFields, fields)
+		})
+	}
+}
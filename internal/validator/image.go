@@ -0,0 +1,60 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validator
+
+import (
+	"strings"
+
+	"github.com/regclient/regclient/types/ref"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// validateImage verifies that the image path built from the repository, image and version
+// fields of a component spec is a valid image reference. Fields which are all empty are
+// skipped, as the image path is then taken from the operator environment.
+func validateImage(fldPath *field.Path, repository, image, version string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var imagePath string
+	if repository == "" && version == "" {
+		imagePath = image
+	} else {
+		if image == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("image"), "image must be set when repository or version is specified"))
+		}
+		if version == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("version"), "version must be set when repository is specified"))
+		}
+		if len(allErrs) != 0 {
+			return allErrs
+		}
+		if strings.HasPrefix(version, "sha256:") {
+			imagePath = repository + "/" + image + "@" + version
+		} else {
+			imagePath = repository + "/" + image + ":" + version
+		}
+	}
+
+	if imagePath == "" {
+		return allErrs
+	}
+
+	if _, err := ref.New(imagePath); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, imagePath, "invalid image reference: "+err.Error()))
+	}
+	return allErrs
+}
@@ -0,0 +1,79 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+// ValidateNodePoolOverrides returns an error when a node is selected by more than one
+// node pool of the ClusterPolicy, as a single DaemonSet per operand is deployed on a node
+func ValidateNodePoolOverrides(overrides []gpuv1.NodePoolOverride, nodes []corev1.Node) error {
+	for _, node := range nodes {
+		pools := []string{}
+		for _, o := range overrides {
+			if labels.SelectorFromSet(o.NodeSelector).Matches(labels.Set(node.Labels)) {
+				pools = append(pools, o.Name)
+			}
+		}
+		if len(pools) > 1 {
+			return fmt.Errorf("conflicting nodePoolOverrides, node %s is selected by node pools %s", node.Name, strings.Join(pools, ", "))
+		}
+	}
+	return nil
+}
+
+func validateNodePoolOverrides(spec *gpuv1.ClusterPolicySpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, o := range spec.NodePoolOverrides {
+		path := specPath.Child("nodePoolOverrides").Index(i)
+		if len(o.NodeSelector) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("nodeSelector"), "nodeSelector must select the nodes of the pool"))
+		}
+		if o.DevicePlugin == nil && o.DCGMExporter == nil && o.GPUFeatureDiscovery == nil && o.MIG == nil && o.MIGManager == nil {
+			allErrs = append(allErrs, field.Required(path, "at least one component must be overridden"))
+		}
+		// the images are validated once the overrides are merged with the cluster-wide spec
+		merged := spec.DeepCopy()
+		if err := o.ApplyTo(merged); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, o.Name, err.Error()))
+			continue
+		}
+		if o.DevicePlugin != nil {
+			allErrs = append(allErrs, validateImage(path.Child("devicePlugin"), merged.DevicePlugin.Repository, merged.DevicePlugin.Image, merged.DevicePlugin.Version)...)
+		}
+		if o.DCGMExporter != nil {
+			allErrs = append(allErrs, validateImage(path.Child("dcgmExporter"), merged.DCGMExporter.Repository, merged.DCGMExporter.Image, merged.DCGMExporter.Version)...)
+		}
+		if o.GPUFeatureDiscovery != nil {
+			allErrs = append(allErrs, validateImage(path.Child("gfd"), merged.GPUFeatureDiscovery.Repository, merged.GPUFeatureDiscovery.Image, merged.GPUFeatureDiscovery.Version)...)
+		}
+		if o.MIGManager != nil {
+			allErrs = append(allErrs, validateImage(path.Child("migManager"), merged.MIGManager.Repository, merged.MIGManager.Image, merged.MIGManager.Version)...)
+		}
+	}
+
+	return allErrs
+}
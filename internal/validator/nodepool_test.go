@@ -0,0 +1,47 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+)
+
+func TestValidateNodePoolOverrides(t *testing.T) {
+	newNode := func(name string, labels map[string]string) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	nodes := []corev1.Node{
+		newNode("node-a100", map[string]string{"nvidia.com/gpu.product": "A100", "zone": "a"}),
+		newNode("node-l4", map[string]string{"nvidia.com/gpu.product": "L4", "zone": "a"}),
+	}
+
+	overrides := []gpuv1.NodePoolOverride{
+		{Name: "a100", NodeSelector: map[string]string{"nvidia.com/gpu.product": "A100"}},
+		{Name: "l4", NodeSelector: map[string]string{"nvidia.com/gpu.product": "L4"}},
+	}
+	require.NoError(t, ValidateNodePoolOverrides(overrides, nodes))
+
+	overrides = append(overrides, gpuv1.NodePoolOverride{Name: "zone-a", NodeSelector: map[string]string{"zone": "a"}})
+	err := ValidateNodePoolOverrides(overrides, nodes)
+	require.ErrorContains(t, err, "node node-a100 is selected by node pools a100, zone-a")
+}
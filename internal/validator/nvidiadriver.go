@@ -0,0 +1,64 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+)
+
+// ValidateNVIDIADriverSpec validates the constraints of a NVIDIADriver spec
+// which cannot be expressed through the CRD schema
+func ValidateNVIDIADriverSpec(spec *nvidiav1alpha1.NVIDIADriverSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if spec.UsePrecompiledDrivers() {
+		if spec.IsGDSEnabled() {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("gds", "enabled"), true,
+				"GPUDirect Storage driver (nvidia-fs) is not supported along with pre-compiled NVIDIA drivers"))
+		}
+		if spec.IsGDRCopyEnabled() {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("gdrcopy", "enabled"), true,
+				"GDRCopy driver is not supported along with pre-compiled NVIDIA drivers"))
+		}
+		if strings.HasPrefix(spec.Version, "sha256:") {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("version"), spec.Version,
+				"specifying image digest is not supported when precompiled is enabled"))
+		}
+	}
+
+	if spec.IsGDSEnabled() && spec.IsOpenKernelModulesRequired() && !spec.IsOpenKernelModulesEnabled() {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("useOpenKernelModules"), spec.IsOpenKernelModulesEnabled(),
+			fmt.Sprintf("GPUDirect Storage driver '%s' is only supported with NVIDIA OpenRM drivers", spec.GPUDirectStorage.Version)))
+	}
+
+	allErrs = append(allErrs, validateImage(specPath, spec.Repository, spec.Image, spec.Version)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("manager"), spec.Manager.Repository, spec.Manager.Image, spec.Manager.Version)...)
+	if spec.GPUDirectStorage != nil {
+		allErrs = append(allErrs, validateImage(specPath.Child("gds"), spec.GPUDirectStorage.Repository, spec.GPUDirectStorage.Image, spec.GPUDirectStorage.Version)...)
+	}
+	if spec.GDRCopy != nil {
+		allErrs = append(allErrs, validateImage(specPath.Child("gdrcopy"), spec.GDRCopy.Repository, spec.GDRCopy.Image, spec.GDRCopy.Version)...)
+	}
+
+	return allErrs
+}
@@ -0,0 +1,80 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+)
+
+const testDigest = "7a9f0e2a0e9ba9fbe0d5f1e3bb0ee1b1a4a8c4cbb3b4c62e5d8b3f3a2c1d0e9f"
+
+func TestValidateNVIDIADriverSpec(t *testing.T) {
+	boolTrue := true
+
+	tests := []struct {
+		description string
+		spec        nvidiav1alpha1.NVIDIADriverSpec
+		errFields   []string
+	}{
+		{
+			description: "valid spec",
+			spec:        nvidiav1alpha1.NVIDIADriverSpec{Repository: "nvcr.io/nvidia", Image: "driver", Version: "550.54.15"},
+		},
+		{
+			description: "precompiled with gds, gdrcopy and digest",
+			spec: nvidiav1alpha1.NVIDIADriverSpec{
+				Repository:       "nvcr.io/nvidia",
+				Image:            "driver",
+				Version:          "sha256:" + testDigest,
+				UsePrecompiled:   &boolTrue,
+				GPUDirectStorage: &nvidiav1alpha1.GPUDirectStorageSpec{Enabled: &boolTrue, Repository: "nvcr.io/nvidia/cloud-native", Image: "nvidia-fs", Version: "2.16.1"},
+				GDRCopy:          &nvidiav1alpha1.GDRCopySpec{Enabled: &boolTrue},
+			},
+			errFields: []string{"spec.gds.enabled", "spec.gdrcopy.enabled", "spec.version"},
+		},
+		{
+			description: "gds requires open kernel modules",
+			spec: nvidiav1alpha1.NVIDIADriverSpec{
+				Repository:       "nvcr.io/nvidia",
+				Image:            "driver",
+				Version:          "550.54.15",
+				GPUDirectStorage: &nvidiav1alpha1.GPUDirectStorageSpec{Enabled: &boolTrue, Repository: "nvcr.io/nvidia/cloud-native", Image: "nvidia-fs", Version: "2.17.5"},
+			},
+			errFields: []string{"spec.useOpenKernelModules"},
+		},
+		{
+			description: "malformed image",
+			spec:        nvidiav1alpha1.NVIDIADriverSpec{Repository: "nvcr.io/nvidia", Image: "Driver", Version: "550.54.15"},
+			errFields:   []string{"spec"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			errs := ValidateNVIDIADriverSpec(&tc.spec)
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			require.ElementsMatch(t, tc.errFields, fields)
+		})
+	}
+}
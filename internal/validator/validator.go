@@ -51,6 +51,19 @@ func (nsv *nodeSelectorValidator) Validate(ctx context.Context, cr *nvidiav1alph
 		return err
 	}
 
+	// The instance being validated may not be persisted yet (e.g. during admission)
+	// or may carry an updated spec, so always validate against the provided object.
+	found := false
+	for i := range drivers.Items {
+		if drivers.Items[i].Name == cr.Name {
+			drivers.Items[i] = *cr.DeepCopy()
+			found = true
+		}
+	}
+	if !found {
+		drivers.Items = append(drivers.Items, *cr.DeepCopy())
+	}
+
 	names := []string{}
 	for _, driver := range drivers.Items {
 		driver := driver
@@ -142,3 +142,29 @@ func TestContainsDuplicates(t *testing.T) {
 		assert.Equal(t, tc.shouldReturnTrue, containsDuplicates(tc.arr))
 	}
 }
+
+func TestCheckNodeSelectorNotPersisted(t *testing.T) {
+	node := makeTestNode(labelled(map[string]string{"os-version": "ubuntu20.04"}))
+	driver := makeTestDriver(nodeSelector(node.Labels))
+	conflictingDriver := makeTestDriver(named("conflictingDriver"), nodeSelector(node.Labels))
+
+	s := scheme.Scheme
+	err := nvidiav1alpha1.AddToScheme(s)
+	require.NoError(t, err)
+	c := fake.
+		NewClientBuilder().
+		WithScheme(s).
+		WithObjects(node, driver).
+		Build()
+	nsv := NewNodeSelectorValidator(c)
+
+	// a new instance which is not yet persisted (admission) is validated against existing instances
+	err = nsv.Validate(context.Background(), conflictingDriver)
+	assert.Error(t, err)
+
+	// an update of an existing instance is validated using the provided spec
+	updatedDriver := driver.DeepCopy()
+	updatedDriver.Spec.NodeSelector = map[string]string{"os-version": "ubuntu22.04"}
+	err = nsv.Validate(context.Background(), updatedDriver)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,72 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/NVIDIA/gpu-operator/internal/validator"
+)
+
+// +kubebuilder:webhook:path=/validate-nvidia-com-v1-clusterpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=nvidia.com,resources=clusterpolicies,verbs=create;update,versions=v1,name=vclusterpolicy.nvidia.com,admissionReviewVersions=v1
+
+// clusterPolicyValidator validates ClusterPolicy objects on admission
+type clusterPolicyValidator struct{}
+
+var _ admission.CustomValidator = &clusterPolicyValidator{}
+
+func setupClusterPolicyWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gpuv1.ClusterPolicy{}).
+		WithValidator(&clusterPolicyValidator{}).
+		Complete()
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *clusterPolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *clusterPolicyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *clusterPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *clusterPolicyValidator) validate(obj runtime.Object) error {
+	cp, ok := obj.(*gpuv1.ClusterPolicy)
+	if !ok {
+		return fmt.Errorf("expected a ClusterPolicy object but got %T", obj)
+	}
+
+	errs := validator.ValidateClusterPolicySpec(&cp.Spec)
+	if len(errs) != 0 {
+		return apierrors.NewInvalid(gpuv1.GroupVersion.WithKind("ClusterPolicy").GroupKind(), cp.Name, errs)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+)
+
+func TestClusterPolicyValidator(t *testing.T) {
+	v := &clusterPolicyValidator{}
+	cp := &gpuv1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"},
+	}
+
+	_, err := v.ValidateCreate(context.Background(), cp)
+	require.NoError(t, err)
+
+	invalid := cp.DeepCopy()
+	invalid.Spec.SandboxWorkloads.DefaultWorkload = "vm"
+	_, err = v.ValidateUpdate(context.Background(), cp, invalid)
+	require.Error(t, err)
+	require.True(t, apierrors.IsInvalid(err))
+
+	_, err = v.ValidateCreate(context.Background(), &nvidiav1alpha1.NVIDIADriver{})
+	require.Error(t, err)
+}
@@ -0,0 +1,84 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/validator"
+)
+
+// +kubebuilder:webhook:path=/validate-nvidia-com-v1alpha1-nvidiadriver,mutating=false,failurePolicy=fail,sideEffects=None,groups=nvidia.com,resources=nvidiadrivers,verbs=create;update,versions=v1alpha1,name=vnvidiadriver.nvidia.com,admissionReviewVersions=v1
+
+// nvidiaDriverValidator validates NVIDIADriver objects on admission
+type nvidiaDriverValidator struct {
+	nodeSelectorValidator validator.Validator
+}
+
+var _ admission.CustomValidator = &nvidiaDriverValidator{}
+
+func setupNVIDIADriverWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&nvidiav1alpha1.NVIDIADriver{}).
+		WithValidator(&nvidiaDriverValidator{
+			nodeSelectorValidator: validator.NewNodeSelectorValidator(mgr.GetClient()),
+		}).
+		Complete()
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *nvidiaDriverValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *nvidiaDriverValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *nvidiaDriverValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *nvidiaDriverValidator) validate(ctx context.Context, obj runtime.Object) error {
+	driver, ok := obj.(*nvidiav1alpha1.NVIDIADriver)
+	if !ok {
+		return fmt.Errorf("expected a NVIDIADriver object but got %T", obj)
+	}
+
+	errs := validator.ValidateNVIDIADriverSpec(&driver.Spec)
+
+	// Verify the nodeSelector does not conflict with any other NVIDIADriver
+	// instance. This ensures only one driver is deployed per GPU node.
+	if err := v.nodeSelectorValidator.Validate(ctx, driver.DeepCopy()); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "nodeSelector"), driver.Spec.NodeSelector, err.Error()))
+	}
+
+	if len(errs) != 0 {
+		return apierrors.NewInvalid(nvidiav1alpha1.GroupVersion.WithKind("NVIDIADriver").GroupKind(), driver.Name, errs)
+	}
+	return nil
+}
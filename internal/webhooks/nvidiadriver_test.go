@@ -0,0 +1,94 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	nvidiav1alpha1 "github.com/NVIDIA/gpu-operator/api/v1alpha1"
+	"github.com/NVIDIA/gpu-operator/internal/validator"
+)
+
+func newTestNVIDIADriver(name string, nodeSelector map[string]string) *nvidiav1alpha1.NVIDIADriver {
+	return &nvidiav1alpha1.NVIDIADriver{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: nvidiav1alpha1.NVIDIADriverSpec{
+			Repository:   "nvcr.io/nvidia",
+			Image:        "driver",
+			Version:      "550.54.15",
+			NodeSelector: nodeSelector,
+		},
+	}
+}
+
+func TestNVIDIADriverValidator(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, nvidiav1alpha1.AddToScheme(s))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{"nvidia.com/gpu.present": "true", "os-version": "ubuntu22.04"},
+		},
+	}
+	existing := newTestNVIDIADriver("existing", map[string]string{"os-version": "ubuntu22.04"})
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(node, existing).Build()
+	v := &nvidiaDriverValidator{nodeSelectorValidator: validator.NewNodeSelectorValidator(c)}
+	ctx := context.Background()
+
+	// a driver selecting other nodes than the existing drivers is admitted
+	driver := newTestNVIDIADriver("driver", map[string]string{"os-version": "rhel9.2"})
+	_, err := v.ValidateCreate(ctx, driver)
+	require.NoError(t, err)
+
+	// a driver selecting the nodes of an existing driver is rejected
+	conflicting := newTestNVIDIADriver("conflicting", map[string]string{"os-version": "ubuntu22.04"})
+	_, err = v.ValidateCreate(ctx, conflicting)
+	require.Error(t, err)
+	require.True(t, apierrors.IsInvalid(err))
+	require.Contains(t, err.Error(), "spec.nodeSelector")
+
+	// an update of the existing driver is validated against its new spec
+	updated := existing.DeepCopy()
+	updated.Spec.NodeSelector = map[string]string{"os-version": "rhel9.2"}
+	_, err = v.ValidateUpdate(ctx, existing, updated)
+	require.NoError(t, err)
+
+	// the constraints of the spec are validated
+	invalid := updated.DeepCopy()
+	invalid.Spec.UsePrecompiled = new(bool)
+	*invalid.Spec.UsePrecompiled = true
+	invalid.Spec.Version = "sha256:7fecaebc1d51b28bc3548171907e4d91823a031d7a6a694ab686999be2b4d867"
+	_, err = v.ValidateUpdate(ctx, existing, invalid)
+	require.Error(t, err)
+	require.True(t, apierrors.IsInvalid(err))
+	require.Contains(t, err.Error(), "spec.version")
+
+	_, err = v.ValidateCreate(ctx, &gpuv1.ClusterPolicy{})
+	require.Error(t, err)
+}
@@ -0,0 +1,35 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package webhooks
+
+import (
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// SetupWithManager registers the validating admission webhooks for
+// ClusterPolicy and NVIDIADriver with the webhook server of the manager
+func SetupWithManager(mgr ctrl.Manager) error {
+	if err := setupClusterPolicyWebhook(mgr); err != nil {
+		return fmt.Errorf("failed to setup ClusterPolicy webhook: %w", err)
+	}
+	if err := setupNVIDIADriverWebhook(mgr); err != nil {
+		return fmt.Errorf("failed to setup NVIDIADriver webhook: %w", err)
+	}
+	return nil
+}